@@ -0,0 +1,196 @@
+package commands
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"osctl/pkg/config"
+	"osctl/pkg/logging"
+	"osctl/pkg/opensearch"
+	"osctl/pkg/state"
+	"osctl/pkg/utils"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var alertsCmd = &cobra.Command{
+	Use:   "alerts",
+	Short: "Alert state and silences",
+	Long:  `Inspect alert deduplication state and manage time-bounded silences.`,
+}
+
+var alertsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show tracked alerts and active silences",
+	Long: `Print alerts kept in the alert state store (alert-state-store index or file):
+firing and recently resolved subjects per trigger and the silences that are still active.`,
+	RunE: runAlertsList,
+}
+
+var alertsSilenceCmd = &cobra.Command{
+	Use:   "silence",
+	Short: "Silence an alert trigger for a subject",
+	Long: `Add a time-bounded silence. While it is active, matching alerts are recorded in the
+alert state but not sent. --silence-subject accepts glob patterns (logs-*), * silences the whole trigger.`,
+	RunE: runAlertsSilence,
+}
+
+var alertsUnsilenceCmd = &cobra.Command{
+	Use:   "unsilence",
+	Short: "Expire a silence before its end time",
+	RunE:  runAlertsUnsilence,
+}
+
+func init() {
+	alertsCmd.AddCommand(alertsListCmd, alertsSilenceCmd, alertsUnsilenceCmd)
+	addFlags(alertsCmd)
+	addFlags(alertsListCmd)
+	addFlags(alertsSilenceCmd)
+	addFlags(alertsUnsilenceCmd)
+}
+
+func alertStoreFromConfig(cfg *config.Config) (state.AlertStore, error) {
+	var client *opensearch.Client
+	if cfg.GetAlertStateStore() == "index" {
+		var err error
+		client, err = utils.NewOSClientWithURL(cfg, cfg.GetOpenSearchURL())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OpenSearch client: %v", err)
+		}
+	}
+	return utils.NewAlertStore(cfg, client), nil
+}
+
+func runAlertsList(cmd *cobra.Command, args []string) error {
+	cfg := config.GetConfig()
+	logger := logging.NewLogger()
+	store, err := alertStoreFromConfig(cfg)
+	if err != nil {
+		return err
+	}
+	st, err := store.LoadAlerts()
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	st.PruneSilences(now)
+	logger.WithFields(logging.Fields{"alerts": len(st.Alerts), "silences": len(st.Silences), "store": cfg.GetAlertStateStore()}).Info("Alert state loaded")
+	return writeAlertsTable(os.Stdout, st, now)
+}
+
+func writeAlertsTable(w io.Writer, st *state.AlertState, now time.Time) error {
+	records := st.Records()
+	if len(records) == 0 {
+		fmt.Fprintln(w, "No tracked alerts")
+	} else {
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "TRIGGER\tSUBJECT\tSTATUS\tSEVERITY\tCOUNT\tFIRST SEEN\tLAST SEEN\tLAST NOTIFIED\tSILENCED")
+		for _, r := range records {
+			notified := "-"
+			if !r.LastNotified.IsZero() {
+				notified = r.LastNotified.Format(time.RFC3339)
+			}
+			silenced := "-"
+			if s := st.Silenced(r.Trigger, r.Subject, now); s != nil {
+				silenced = s.ID
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%s\t%s\t%s\t%s\n", r.Trigger, r.Subject, r.Status, r.Severity, r.Count,
+				r.FirstSeen.Format(time.RFC3339), r.LastSeen.Format(time.RFC3339), notified, silenced)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintln(w)
+	if len(st.Silences) == 0 {
+		fmt.Fprintln(w, "No active silences")
+		return nil
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SILENCE ID\tTRIGGER\tSUBJECT\tEXPIRES\tLEFT\tCREATED BY\tREASON")
+	for _, s := range st.Silences {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", s.ID, s.Trigger, s.Subject, s.ExpiresAt.Format(time.RFC3339),
+			s.ExpiresAt.Sub(now).Round(time.Minute), s.CreatedBy, s.Reason)
+	}
+	return tw.Flush()
+}
+
+func runAlertsSilence(cmd *cobra.Command, args []string) error {
+	cfg := config.GetConfig()
+	logger := logging.NewLogger()
+
+	now := time.Now().UTC()
+	idBytes := make([]byte, 4)
+	if _, err := rand.Read(idBytes); err != nil {
+		return fmt.Errorf("failed to generate silence id: %v", err)
+	}
+	createdBy := os.Getenv("USER")
+	if createdBy == "" {
+		createdBy, _ = os.Hostname()
+	}
+	silence := &state.Silence{
+		ID:        hex.EncodeToString(idBytes),
+		Trigger:   cfg.GetSilenceTrigger(),
+		Subject:   cfg.GetSilenceSubject(),
+		Reason:    cfg.GetSilenceReason(),
+		CreatedBy: createdBy,
+		CreatedAt: now,
+		ExpiresAt: now.Add(cfg.GetSilenceDuration()),
+	}
+	if cfg.GetDryRun() {
+		logger.WithFields(logging.Fields{"trigger": silence.Trigger, "subject": silence.Subject, "expires": silence.ExpiresAt.Format(time.RFC3339), "reason": silence.Reason}).Info("DRY RUN: Would add silence")
+		return nil
+	}
+
+	store, err := alertStoreFromConfig(cfg)
+	if err != nil {
+		return err
+	}
+	err = store.UpdateAlerts(func(st *state.AlertState) bool {
+		st.PruneSilences(now)
+		st.Silences = append(st.Silences, silence)
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	logger.WithFields(logging.Fields{"id": silence.ID, "trigger": silence.Trigger, "subject": silence.Subject, "expires": silence.ExpiresAt.Format(time.RFC3339), "reason": silence.Reason}).Info("Silence added")
+	return nil
+}
+
+func runAlertsUnsilence(cmd *cobra.Command, args []string) error {
+	cfg := config.GetConfig()
+	logger := logging.NewLogger()
+	store, err := alertStoreFromConfig(cfg)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	id := cfg.GetSilenceID()
+	var expired *state.Silence
+	err = store.UpdateAlerts(func(st *state.AlertState) bool {
+		expired = nil
+		for _, s := range st.Silences {
+			if s.ID == id && s.Active(now) {
+				s.ExpiresAt = now
+				expired = s
+				st.PruneSilences(now)
+				return true
+			}
+		}
+		return false
+	})
+	if err != nil {
+		return err
+	}
+	if expired == nil {
+		return fmt.Errorf("active silence %s not found", id)
+	}
+	logger.WithFields(logging.Fields{"id": expired.ID, "trigger": expired.Trigger, "subject": expired.Subject}).Info("Silence expired")
+	return nil
+}
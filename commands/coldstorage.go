@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"osctl/pkg/config"
 	"osctl/pkg/logging"
+	"osctl/pkg/report"
 	"osctl/pkg/utils"
 	"strings"
 	"time"
@@ -30,7 +31,7 @@ func runColdStorage(cmd *cobra.Command, args []string) error {
 	coldAttribute := cfg.GetColdAttribute()
 	dateFormat := cfg.GetDateFormat()
 	logger := logging.NewLogger()
-	logger.Info(fmt.Sprintf("Starting cold storage migration hotCount=%d coldAttribute=%s dryRun=%t", hotCount, coldAttribute, cfg.GetDryRun()))
+	logger.WithFields(logging.Fields{"hotCount": hotCount, "coldAttribute": coldAttribute, "dryRun": cfg.GetDryRun()}).Info("Starting cold storage migration")
 
 	client, err := utils.NewOSClientWithURL(cfg, cfg.GetOpenSearchURL())
 	if err != nil {
@@ -46,7 +47,7 @@ func runColdStorage(cmd *cobra.Command, args []string) error {
 
 	allNames := utils.IndexInfosToNames(allIndices)
 	if len(allNames) > 0 {
-		logger.Info(fmt.Sprintf("Found indices %s", strings.Join(allNames, ", ")))
+		logger.WithField("indices", strings.Join(allNames, ", ")).Info("Found indices")
 	} else {
 		logger.Info("Found indices none")
 	}
@@ -68,29 +69,31 @@ func runColdStorage(cmd *cobra.Command, args []string) error {
 	for _, idx := range candidates {
 		req, err := client.GetIndexColdRequirement(idx)
 		if err != nil {
-			logger.Error(fmt.Sprintf("Skip index due to read settings error index=%s error=%v", idx, err))
+			logger.WithFields(logging.Fields{"index": idx, "error": err}).Error("Skip index due to read settings error")
+			report.Failed(idx, err)
 			continue
 		}
 		if req == coldAttribute {
-			logger.Info(fmt.Sprintf("Already in cold: index=%s attr=%s", idx, req))
+			logger.WithFields(logging.Fields{"index": idx, "attr": req}).Info("Already in cold")
 			alreadyCold = append(alreadyCold, idx)
+			report.Skipped(idx, "already in cold")
 			continue
 		}
-		logger.Info(fmt.Sprintf("Candidate for cold storage: index=%s current_attr=%s target_attr=%s", idx, req, coldAttribute))
+		logger.WithFields(logging.Fields{"index": idx, "current_attr": req, "target_attr": coldAttribute}).Info("Candidate for cold storage")
 		coldIndices = append(coldIndices, idx)
 	}
 
 	if len(alreadyCold) > 0 {
-		logger.Info(fmt.Sprintf("Skip already in cold count=%d list=%s", len(alreadyCold), strings.Join(alreadyCold, ", ")))
+		logger.WithFields(logging.Fields{"count": len(alreadyCold), "list": strings.Join(alreadyCold, ", ")}).Info("Skip already in cold")
 	}
 	if len(coldIndices) == 0 {
 		logger.Info("No indices require cold storage migration")
 		return nil
 	}
 
-	logger.Info(fmt.Sprintf("Found indices for cold storage migration count=%d", len(coldIndices)))
+	logger.WithField("count", len(coldIndices)).Info("Found indices for cold storage migration")
 	if len(coldIndices) > 0 {
-		logger.Info(fmt.Sprintf("Cold storage candidates %s", strings.Join(coldIndices, ", ")))
+		logger.WithField("indices", strings.Join(coldIndices, ", ")).Info("Cold storage candidates")
 	}
 
 	var successfulMigrations []string
@@ -98,19 +101,22 @@ func runColdStorage(cmd *cobra.Command, args []string) error {
 
 	for _, index := range coldIndices {
 		if cfg.GetDryRun() {
-			logger.Info(fmt.Sprintf("DRY RUN: Would migrate to cold storage index=%s attribute=%s", index, coldAttribute))
+			logger.WithFields(logging.Fields{"index": index, "attribute": coldAttribute}).Info("DRY RUN: Would migrate to cold storage")
 			successfulMigrations = append(successfulMigrations, index)
+			report.Skipped(index, "dry run")
 			continue
 		}
 
-		if err := client.SetColdStorage(index, coldAttribute); err != nil {
-			logger.Error(fmt.Sprintf("Failed to migrate to cold storage index=%s error=%v", index, err))
+		if err := client.WithAuditReason("older than "+cutoffDate).SetColdStorage(index, coldAttribute); err != nil {
+			logger.WithFields(logging.Fields{"index": index, "error": err}).Error("Failed to migrate to cold storage")
 			failedMigrations = append(failedMigrations, index)
+			report.Failed(index, err)
 			continue
 		}
 
-		logger.Info(fmt.Sprintf("Migrated to cold storage index=%s", index))
+		logger.WithField("index", index).Info("Migrated to cold storage")
 		successfulMigrations = append(successfulMigrations, index)
+		report.Succeeded(index)
 	}
 
 	if !cfg.GetDryRun() {
@@ -120,21 +126,21 @@ func runColdStorage(cmd *cobra.Command, args []string) error {
 		if len(successfulMigrations) > 0 {
 			logger.Info(fmt.Sprintf("Successfully migrated to cold: %d indices", len(successfulMigrations)))
 			for _, name := range successfulMigrations {
-				logger.Info(fmt.Sprintf("  ✓ %s", name))
+				logger.WithField("index", name).Info("  ✓")
 			}
 		}
 		if len(failedMigrations) > 0 {
 			logger.Info("")
 			logger.Info(fmt.Sprintf("Failed to migrate: %d indices", len(failedMigrations)))
 			for _, name := range failedMigrations {
-				logger.Info(fmt.Sprintf("  ✗ %s", name))
+				logger.WithField("index", name).Info("  ✗")
 			}
 		}
 		if len(alreadyCold) > 0 {
 			logger.Info("")
 			logger.Info(fmt.Sprintf("Already in cold: %d indices", len(alreadyCold)))
 			for _, name := range alreadyCold {
-				logger.Info(fmt.Sprintf("  - %s", name))
+				logger.WithField("index", name).Info("  -")
 			}
 		}
 		if len(successfulMigrations) == 0 && len(failedMigrations) == 0 && len(alreadyCold) == 0 {
@@ -143,6 +149,6 @@ func runColdStorage(cmd *cobra.Command, args []string) error {
 		logger.Info(strings.Repeat("=", 60))
 	}
 
-	logger.Info(fmt.Sprintf("Cold storage migration completed processed=%d skipped_already_cold=%d", len(coldIndices), len(alreadyCold)))
+	logger.WithFields(logging.Fields{"processed": len(coldIndices), "skipped_already_cold": len(alreadyCold)}).Info("Cold storage migration completed")
 	return nil
 }
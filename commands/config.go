@@ -0,0 +1,247 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"osctl/pkg/config"
+	"osctl/pkg/logging"
+	"osctl/pkg/utils"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Check osctl configuration",
+	Long:  `Inspect and check osctl configuration files.`,
+}
+
+var configLintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Find conflicting rules in osctl-indices-config",
+	Long: `Check osctl-indices-config for rules that silently change retention: prefixes shadowed by
+other prefixes, regexes that can never match the date format or are shadowed by a prefix, rules that
+produce the same snapshot name, snapshot_count_s3 smaller than days_count and repositories that are
+referenced but not declared. With --lint-live also checks that the repositories are registered in the
+cluster and lists live indices that match no rule or more than one rule.
+Exits with an error when at least one finding has severity error.`,
+	RunE: runConfigLint,
+}
+
+var configSchemaCmd = &cobra.Command{
+	Use:   "schema <config|indices|tenants>",
+	Short: "Print JSON Schema of a config file",
+	Long: `Print JSON Schema of config.yaml (config), osctlindicesconfig.yaml (indices) or
+osctltenants.yaml (tenants) for editors and CI. The schema is generated from the config structures,
+flag types, descriptions and min/max limits; unknown fields are not allowed.`,
+	Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	ValidArgs: config.SchemaKinds,
+	RunE:      runConfigSchema,
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate config files against their JSON Schema",
+	Long: `Validate config.yaml (--config), osctlindicesconfig.yaml (--osctl-indices-config) and tenants
+files (--kibana-tenants-config, --indexpatterns-kibana-tenants-config) against the schema printed by
+osctl config schema: unknown fields with a suggestion, wrong types, enum and min/max violations, with
+line numbers. The indices and tenants files are also loaded the way commands load them.
+Files at default paths that do not exist are skipped. Exits with an error when any file is invalid.`,
+	RunE: runConfigValidate,
+}
+
+func init() {
+	configCmd.AddCommand(configLintCmd, configSchemaCmd, configValidateCmd)
+	addFlags(configCmd)
+	addFlags(configLintCmd)
+	addFlags(configSchemaCmd)
+	addFlags(configValidateCmd)
+}
+
+func flagInfoLookup() func(flag string) (config.FlagInfo, bool) {
+	var find func(cmd *cobra.Command, flag string) (config.FlagInfo, bool)
+	find = func(cmd *cobra.Command, flag string) (config.FlagInfo, bool) {
+		f := cmd.Flags().Lookup(flag)
+		if f == nil {
+			f = cmd.PersistentFlags().Lookup(flag)
+		}
+		if f != nil {
+			return config.FlagInfo{Type: f.Value.Type(), Description: f.Usage}, true
+		}
+		for _, child := range cmd.Commands() {
+			if info, ok := find(child, flag); ok {
+				return info, true
+			}
+		}
+		return config.FlagInfo{}, false
+	}
+	return func(flag string) (config.FlagInfo, bool) {
+		return find(rootCmd, flag)
+	}
+}
+
+func runConfigSchema(cmd *cobra.Command, args []string) error {
+	schema, err := config.Schema(args[0], flagInfoLookup())
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(schema)
+}
+
+func runConfigValidate(cmd *cobra.Command, args []string) error {
+	cfg := config.GetConfig()
+	logger := logging.NewLogger()
+	files := []struct {
+		kind, flag, path string
+	}{
+		{"config", "config", cfg.GetOSCTLConfig()},
+		{"indices", "osctl-indices-config", cfg.GetOSCTLIndicesConfig()},
+		{"tenants", "kibana-tenants-config", cfg.GetOSCTLTenantsConfig()},
+		{"tenants", "indexpatterns-kibana-tenants-config", cfg.GetIndexPatternsKibanaTenantsConfig()},
+	}
+
+	lookup := flagInfoLookup()
+	checked, invalid := 0, 0
+	seen := map[string]bool{}
+	for _, f := range files {
+		if f.path == "" || seen[f.path] {
+			continue
+		}
+		seen[f.path] = true
+		data, err := os.ReadFile(f.path)
+		if err != nil {
+			if os.IsNotExist(err) && !cmd.Flags().Changed(f.flag) {
+				fmt.Printf("SKIP %s (%s): not found\n", f.path, f.kind)
+				continue
+			}
+			return fmt.Errorf("failed to read %s: %v", f.path, err)
+		}
+		schema, err := config.Schema(f.kind, lookup)
+		if err != nil {
+			return err
+		}
+		problems := config.ValidateAgainstSchema(schema, data)
+		if len(problems) == 0 {
+			problems = loadConfigProblems(f.kind, f.path, cfg.GetDateFormat())
+		}
+		checked++
+		if len(problems) == 0 {
+			fmt.Printf("OK   %s (%s)\n", f.path, f.kind)
+			continue
+		}
+		invalid++
+		fmt.Printf("FAIL %s (%s)\n", f.path, f.kind)
+		for _, p := range problems {
+			fmt.Printf("  %s\n", p)
+		}
+	}
+	logger.WithFields(logging.Fields{"checked": checked, "invalid": invalid}).Info("Config validation finished")
+
+	if checked == 0 {
+		return fmt.Errorf("no config files found to validate")
+	}
+	if invalid > 0 {
+		return fmt.Errorf("%d of %d config files are invalid", invalid, checked)
+	}
+	return nil
+}
+
+func loadConfigProblems(kind, path, dateFormat string) []string {
+	var err error
+	switch kind {
+	case "indices":
+		var oic *config.OsctlIndicesConfig
+		if oic, err = config.LoadOsctlIndicesConfig(path); err == nil {
+			err = config.ValidateOsctlIndicesConfig(oic, dateFormat)
+		}
+	case "tenants":
+		_, err = config.LoadTenantsConfig(path)
+	}
+	if err != nil {
+		return []string{err.Error()}
+	}
+	return nil
+}
+
+func runConfigLint(cmd *cobra.Command, args []string) error {
+	cfg := config.GetConfig()
+	logger := logging.NewLogger()
+	path := cfg.GetOSCTLIndicesConfig()
+	if path == "" {
+		return fmt.Errorf("osctl-indices-config is required")
+	}
+
+	oic, err := config.LoadOsctlIndicesConfig(path)
+	if err != nil {
+		return err
+	}
+	var declared []string
+	for _, repo := range cfg.GetRepositories() {
+		declared = append(declared, repo.Name)
+	}
+	findings := utils.LintIndicesConfig(oic, path, cfg.GetDateFormat(), cfg.GetSnapshotRepo(), declared)
+
+	if cfg.GetLintLive() {
+		client, err := utils.NewOSClientWithURL(cfg, cfg.GetOpenSearchURL())
+		if err != nil {
+			return fmt.Errorf("failed to create OpenSearch client: %v", err)
+		}
+		repos, err := client.GetRepositories()
+		if err != nil {
+			return fmt.Errorf("failed to get snapshot repositories: %v", err)
+		}
+		registered := make(map[string]bool, len(repos))
+		for name := range repos {
+			registered[name] = true
+		}
+		findings = append(findings, utils.LintRegisteredRepositories(utils.ReferencedRepositories(oic, cfg.GetSnapshotRepo()), registered)...)
+
+		indices, err := client.GetIndicesWithFields("*", "index")
+		if err != nil {
+			return fmt.Errorf("failed to get indices: %v", err)
+		}
+		names := make([]string, 0, len(indices))
+		for _, idx := range indices {
+			names = append(names, idx.Index)
+		}
+		findings = append(findings, utils.LintLiveIndices(oic, path, cfg.GetDateFormat(), names)...)
+	}
+
+	errors := 0
+	for _, f := range findings {
+		if f.Severity == utils.LintError {
+			errors++
+		}
+	}
+	logger.WithFields(logging.Fields{"findings": len(findings), "errors": errors}).Info("Config lint finished")
+
+	if err := writeLintFindings(os.Stdout, findings); err != nil {
+		return err
+	}
+	if errors > 0 {
+		return fmt.Errorf("config lint found %d errors", errors)
+	}
+	return nil
+}
+
+func writeLintFindings(w io.Writer, findings []utils.LintFinding) error {
+	if len(findings) == 0 {
+		fmt.Fprintln(w, "No problems found")
+		return nil
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SEVERITY\tCHECK\tRULE\tMESSAGE")
+	for _, f := range findings {
+		rule := f.Rule
+		if rule == "" {
+			rule = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", f.Severity, f.Check, rule, f.Message)
+	}
+	return tw.Flush()
+}
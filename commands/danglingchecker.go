@@ -5,8 +5,11 @@ import (
 	"osctl/pkg/alerts"
 	"osctl/pkg/config"
 	"osctl/pkg/logging"
+	"osctl/pkg/opensearch"
+	"osctl/pkg/report"
 	"osctl/pkg/utils"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -15,7 +18,9 @@ var danglingCheckerCmd = &cobra.Command{
 	Use:   "danglingchecker",
 	Short: "Check for dangling indices and send alerts",
 	Long: `Check for dangling indices that are not referenced by any index pattern
-and send alerts to Madison if found.`,
+and send alerts to the configured notifiers if found.
+With --dangling-resolve dangling indices are imported or deleted according to
+osctl-indices-config policies; only unresolved ones are alerted.`,
 	RunE: runDanglingChecker,
 }
 
@@ -23,14 +28,26 @@ func init() {
 	addFlags(danglingCheckerCmd)
 }
 
+const (
+	danglingActionImport = "import"
+	danglingActionDelete = "delete"
+	danglingActionKeep   = "keep"
+)
+
+type danglingDecision struct {
+	index  opensearch.DanglingIndex
+	action string
+	reason string
+}
+
 func runDanglingChecker(cmd *cobra.Command, args []string) error {
 	cfg := config.GetConfig()
 
-	madisonKey := cfg.GetMadisonKey()
-	osdURL := cfg.GetOSDURL()
+	resolve := cfg.GetDanglingResolve()
+	notifier := utils.NewNotifier(cfg)
 
-	if madisonKey == "" || osdURL == "" || cfg.GetMadisonURL() == "" {
-		return fmt.Errorf("madison-key, osd-url and madison-url parameters are required")
+	if !resolve && notifier == nil {
+		return fmt.Errorf("alert notifier is required: set alert-notifiers and its parameters (madison-key, osd-url and madison-url for madison)")
 	}
 
 	logger := logging.NewLogger()
@@ -46,6 +63,9 @@ func runDanglingChecker(cmd *cobra.Command, args []string) error {
 
 	if len(danglingIndices) == 0 {
 		logger.Info("No dangling indices found")
+		if !cfg.GetDryRun() {
+			utils.ResolveAlerts(notifier, alerts.DanglingIndices, nil, logger)
+		}
 		return nil
 	}
 
@@ -53,17 +73,167 @@ func runDanglingChecker(cmd *cobra.Command, args []string) error {
 	for i, di := range danglingIndices {
 		indexNames[i] = di.IndexName
 	}
-	logger.Info(fmt.Sprintf("Dangling indices found (%d): %s", len(indexNames), strings.Join(indexNames, ", ")))
+	logger.WithFields(logging.Fields{"count": len(indexNames), "indices": strings.Join(indexNames, ", ")}).Info("Dangling indices found")
+
+	if resolve {
+		indexNames, err = resolveDanglingIndices(client, cfg, danglingIndices, logger)
+		if err != nil {
+			return err
+		}
+		if !cfg.GetDryRun() {
+			utils.ResolveAlerts(notifier, alerts.DanglingIndices, indexNames, logger)
+		}
+		if len(indexNames) == 0 {
+			return nil
+		}
+		if notifier == nil {
+			logger.Warn("No alert notifier is configured (alert-notifiers) — alert for unresolved dangling indices skipped")
+			return nil
+		}
+	}
 
 	if cfg.GetDryRun() {
-		logger.Info(fmt.Sprintf("DRY RUN: Would send Madison alert for dangling indices count=%d", len(danglingIndices)))
+		logger.WithField("count", len(indexNames)).Info("DRY RUN: Would send alert for dangling indices")
 	} else {
-		madisonClient := alerts.NewMadisonClient(madisonKey, osdURL, cfg.GetMadisonURL())
-		response, err := madisonClient.SendMadisonDanglingIndicesAlert(indexNames)
+		if !resolve {
+			utils.ResolveAlerts(notifier, alerts.DanglingIndices, indexNames, logger)
+		}
+		response, err := notifier.Notify(alerts.NewDanglingIndicesEvent(indexNames, cfg.GetOSDURL()))
 		if err != nil {
-			return fmt.Errorf("failed to send Madison alert: %v", err)
+			return fmt.Errorf("failed to send alert: %v", err)
 		}
-		logger.Info(fmt.Sprintf("Madison alert sent successfully: type=DanglingIndices count=%d response=%s", len(danglingIndices), response))
+		logger.WithFields(logging.Fields{"type": "DanglingIndices", "count": len(indexNames), "notifiers": notifier.Name(), "response": response}).Info("Alert sent successfully")
 	}
 	return nil
 }
+
+func resolveDanglingIndices(client *opensearch.Client, cfg *config.Config, danglingIndices []opensearch.DanglingIndex, logger *logging.Logger) ([]string, error) {
+	indicesConfig, err := cfg.GetOsctlIndices()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get osctl indices: %v", err)
+	}
+	unknownConfig := cfg.GetOsctlIndicesUnknownConfig()
+	dateFormat := cfg.GetDateFormat()
+
+	var decisions []danglingDecision
+	for _, di := range danglingIndices {
+		d := decideDangling(client, cfg, di, indicesConfig, unknownConfig, dateFormat, logger)
+		logger.WithFields(logging.Fields{"index": di.IndexName, "uuid": di.IndexUUID, "action": d.action, "reason": d.reason}).Info("Dangling decision")
+		decisions = append(decisions, d)
+	}
+
+	var done, failed, unresolved []string
+	for _, d := range decisions {
+		label := fmt.Sprintf("%s (%s): %s", d.index.IndexName, d.index.IndexUUID, d.reason)
+		switch d.action {
+		case danglingActionKeep:
+			unresolved = append(unresolved, d.index.IndexName)
+			report.Skipped(d.index.IndexName, d.reason)
+			continue
+		case danglingActionImport:
+			if cfg.GetDryRun() {
+				logger.WithFields(logging.Fields{"index": d.index.IndexName, "uuid": d.index.IndexUUID}).Info("DRY RUN: Would import dangling index")
+				report.Skipped(d.index.IndexName, "dry run")
+				continue
+			}
+			err = client.WithAuditReason(d.reason).ImportDanglingIndex(d.index.IndexUUID)
+		case danglingActionDelete:
+			if cfg.GetDryRun() {
+				logger.WithFields(logging.Fields{"index": d.index.IndexName, "uuid": d.index.IndexUUID}).Info("DRY RUN: Would delete dangling index")
+				report.Skipped(d.index.IndexName, "dry run")
+				continue
+			}
+			err = client.WithAuditReason(d.reason).DeleteDanglingIndex(d.index.IndexUUID)
+		}
+		if err != nil {
+			logger.WithFields(logging.Fields{"action": d.action, "index": d.index.IndexName, "uuid": d.index.IndexUUID, "error": err}).Error("Failed to resolve dangling index")
+			failed = append(failed, d.action+" "+label)
+			unresolved = append(unresolved, d.index.IndexName)
+			report.Failed(d.index.IndexName, fmt.Sprintf("%s failed: %v", d.action, err))
+			continue
+		}
+		logger.WithFields(logging.Fields{"action": d.action, "index": d.index.IndexName, "uuid": d.index.IndexUUID}).Info("Dangling index resolved")
+		done = append(done, d.action+" "+label)
+		report.Succeeded(d.index.IndexName)
+	}
+
+	logger.Info(strings.Repeat("=", 60))
+	logger.Info("DANGLING INDICES SUMMARY")
+	logger.Info(strings.Repeat("=", 60))
+	for _, s := range done {
+		logger.Info("  ✓ " + s)
+	}
+	for _, s := range failed {
+		logger.Info("  ✗ " + s)
+	}
+	for _, d := range decisions {
+		if d.action == danglingActionKeep || cfg.GetDryRun() {
+			logger.WithFields(logging.Fields{"index": d.index.IndexName, "uuid": d.index.IndexUUID, "reason": d.reason}).Info("  - " + d.action)
+		}
+	}
+	logger.Info(strings.Repeat("=", 60))
+	return unresolved, nil
+}
+
+func decideDangling(client *opensearch.Client, cfg *config.Config, di opensearch.DanglingIndex, indicesConfig []config.IndexConfig, unknownConfig config.UnknownConfig, dateFormat string, logger *logging.Logger) danglingDecision {
+	d := danglingDecision{index: di}
+	indexConfig := utils.FindMatchingIndexConfig(di.IndexName, indicesConfig)
+
+	daysCount := unknownConfig.DaysCount
+	snapshotRepo := cfg.GetSnapshotRepo()
+	if indexConfig != nil {
+		daysCount = indexConfig.DaysCount
+		if indexConfig.Repository != "" {
+			snapshotRepo = indexConfig.Repository
+		}
+	}
+
+	dateStr := utils.ExtractDateFromIndex(di.IndexName, dateFormat)
+	if daysCount > 0 {
+		cutoff := time.Now().AddDate(0, 0, -daysCount)
+		if dateStr != "" {
+			if utils.IsOlderThanCutoff(di.IndexName, utils.FormatDate(cutoff, dateFormat), dateFormat) {
+				d.action = danglingActionDelete
+				d.reason = fmt.Sprintf("older than days_count=%d", daysCount)
+				return d
+			}
+		} else if di.CreationDateMillis > 0 && time.UnixMilli(di.CreationDateMillis).Before(cutoff) {
+			d.action = danglingActionDelete
+			d.reason = fmt.Sprintf("created before days_count=%d", daysCount)
+			return d
+		}
+	}
+
+	if dateStr != "" && snapshotRepo != "" {
+		snapshots, err := utils.GetSnapshotsIgnore404(client, snapshotRepo, "*"+dateStr+"*")
+		if err != nil {
+			logger.WithFields(logging.Fields{"index": di.IndexName, "repo": snapshotRepo, "error": err}).Warn("Failed to check snapshots for dangling index")
+		} else if utils.HasValidSnapshot(di.IndexName, snapshots) {
+			d.action = danglingActionDelete
+			d.reason = fmt.Sprintf("covered by snapshot in repo=%s", snapshotRepo)
+			return d
+		}
+	}
+
+	if indexConfig == nil {
+		d.action = danglingActionKeep
+		d.reason = "no matching index config"
+		return d
+	}
+
+	exists, err := client.IndexExists(di.IndexName)
+	if err != nil {
+		d.action = danglingActionKeep
+		d.reason = fmt.Sprintf("failed to check live index: %v", err)
+		return d
+	}
+	if exists {
+		d.action = danglingActionKeep
+		d.reason = "live index with the same name exists"
+		return d
+	}
+
+	d.action = danglingActionImport
+	d.reason = fmt.Sprintf("matches %s %s and no live index exists", indexConfig.Kind, indexConfig.Value)
+	return d
+}
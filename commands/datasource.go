@@ -7,6 +7,7 @@ import (
 	"osctl/pkg/config"
 	"osctl/pkg/kibana"
 	"osctl/pkg/logging"
+	"osctl/pkg/report"
 	"osctl/pkg/utils"
 	"slices"
 	"strings"
@@ -70,29 +71,34 @@ func runDataSource(cmd *cobra.Command, args []string) error {
 	var createdDataSources []string
 	var existingDataSources []string
 
-	logger.Info(fmt.Sprintf("Tenants to process (%d): %s", len(tenants), strings.Join(tenantNamesForLog, ", ")))
+	logger.WithFields(logging.Fields{"count": len(tenants), "tenants": strings.Join(tenantNamesForLog, ", ")}).Info("Tenants to process")
 	for i, tenant := range tenants {
 		tenantNameForLog := tenantNamesForLog[i]
+		item := fmt.Sprintf("%s (tenant=%s)", dataSourceName, tenantNameForLog)
 		existingTitles, err := getTenantDataSourceTitles(kb, tenant)
 		if err != nil {
 			return err
 		}
 		exists := slices.Contains(existingTitles, dataSourceName)
-		logger.Info(fmt.Sprintf("Tenant %s existing data-sources (%d): %s", tenantNameForLog, len(existingTitles), strings.Join(existingTitles, ", ")))
+		logger.WithFields(logging.Fields{"tenant": tenantNameForLog, "count": len(existingTitles), "dataSources": strings.Join(existingTitles, ", ")}).Info("Tenant existing data-sources")
 		if !exists {
 			if cfg.GetDryRun() {
-				logger.Info(fmt.Sprintf("DRY RUN: Would create data source '%s' in tenant %s", dataSourceName, tenantNameForLog))
-				createdDataSources = append(createdDataSources, fmt.Sprintf("%s (tenant=%s)", dataSourceName, tenantNameForLog))
+				logger.WithFields(logging.Fields{"dataSource": dataSourceName, "tenant": tenantNameForLog}).Info("DRY RUN: Would create data source")
+				createdDataSources = append(createdDataSources, item)
+				report.Skipped(item, "dry run")
 			} else {
 				if err := kb.CreateDataSource(tenant, dataSourceName, dataSourceEndpoint, user, pass); err != nil {
+					report.Failed(item, err)
 					return err
 				}
-				logger.Info(fmt.Sprintf("Created data source '%s' in tenant %s", dataSourceName, tenantNameForLog))
-				createdDataSources = append(createdDataSources, fmt.Sprintf("%s (tenant=%s)", dataSourceName, tenantNameForLog))
+				logger.WithFields(logging.Fields{"dataSource": dataSourceName, "tenant": tenantNameForLog}).Info("Created data source")
+				createdDataSources = append(createdDataSources, item)
+				report.Succeeded(item)
 			}
 		} else {
-			logger.Info(fmt.Sprintf("Data source already exists in tenant %s (title=%s)", tenantNameForLog, dataSourceName))
-			existingDataSources = append(existingDataSources, fmt.Sprintf("%s (tenant=%s)", dataSourceName, tenantNameForLog))
+			logger.WithFields(logging.Fields{"tenant": tenantNameForLog, "title": dataSourceName}).Info("Data source already exists")
+			existingDataSources = append(existingDataSources, item)
+			report.Skipped(item, "already exists")
 		}
 	}
 
@@ -103,14 +109,14 @@ func runDataSource(cmd *cobra.Command, args []string) error {
 		if len(createdDataSources) > 0 {
 			logger.Info(fmt.Sprintf("Created: %d data sources", len(createdDataSources)))
 			for _, name := range createdDataSources {
-				logger.Info(fmt.Sprintf("  ✓ %s", name))
+				logger.WithField("dataSource", name).Info("  ✓")
 			}
 		}
 		if len(existingDataSources) > 0 {
 			logger.Info("")
 			logger.Info(fmt.Sprintf("Already exists: %d data sources", len(existingDataSources)))
 			for _, name := range existingDataSources {
-				logger.Info(fmt.Sprintf("  - %s", name))
+				logger.WithField("dataSource", name).Info("  -")
 			}
 		}
 		if len(createdDataSources) == 0 && len(existingDataSources) == 0 {
@@ -5,6 +5,7 @@ import (
 	"osctl/pkg/config"
 	"osctl/pkg/logging"
 	"osctl/pkg/opensearch"
+	"osctl/pkg/report"
 	"osctl/pkg/utils"
 	"strings"
 	"time"
@@ -37,7 +38,7 @@ func runDereplicator(cmd *cobra.Command, args []string) error {
 	}
 
 	logger := logging.NewLogger()
-	logger.Info(fmt.Sprintf("Starting dereplication process daysCount=%d useSnapshot=%t snapRepo=%s dryRun=%t", daysCount, useSnapshot, snapRepo, cfg.GetDryRun()))
+	logger.WithFields(logging.Fields{"daysCount": daysCount, "useSnapshot": useSnapshot, "snapRepo": snapRepo, "dryRun": cfg.GetDryRun()}).Info("Starting dereplication process")
 
 	client, err := utils.NewOSClientWithURL(cfg, cfg.GetOpenSearchURL())
 	if err != nil {
@@ -51,7 +52,7 @@ func runDereplicator(cmd *cobra.Command, args []string) error {
 
 	allNames := utils.IndexInfosToNames(indices)
 	if len(allNames) > 0 {
-		logger.Info(fmt.Sprintf("Found indices %s", strings.Join(allNames, ", ")))
+		logger.WithField("indices", strings.Join(allNames, ", ")).Info("Found indices")
 	} else {
 		logger.Info("Found indices none")
 	}
@@ -68,7 +69,7 @@ func runDereplicator(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	logger.Info(fmt.Sprintf("Indices to dereplicate %s", strings.Join(targetIndices, ", ")))
+	logger.WithField("indices", strings.Join(targetIndices, ", ")).Info("Indices to dereplicate")
 
 	var snapshots []opensearch.Snapshot
 	if useSnapshot {
@@ -86,23 +87,27 @@ func runDereplicator(cmd *cobra.Command, args []string) error {
 	var skippedNoSnapshot []string
 	for _, index := range targetIndices {
 		if useSnapshot && !utils.HasValidSnapshot(index, snapshots) {
-			logger.Warn(fmt.Sprintf("No valid snapshot found index=%s", index))
+			logger.WithField("index", index).Warn("No valid snapshot found")
 			skippedNoSnapshot = append(skippedNoSnapshot, index)
+			report.Skipped(index, "no valid snapshot")
 			continue
 		}
 
 		if cfg.GetDryRun() {
-			logger.Info(fmt.Sprintf("DRY RUN: Would set replicas to 0 index=%s", index))
+			logger.WithField("index", index).Info("DRY RUN: Would set replicas to 0")
 			successfulDereplications = append(successfulDereplications, index)
+			report.Skipped(index, "dry run")
 			continue
 		}
 
-		if err := client.SetReplicas(index, 0); err != nil {
-			logger.Error(fmt.Sprintf("Failed to set replicas index=%s error=%v", index, err))
+		if err := client.WithAuditReason(fmt.Sprintf("older than %d days", daysCount)).SetReplicas(index, 0); err != nil {
+			logger.WithFields(logging.Fields{"index": index, "error": err}).Error("Failed to set replicas")
 			problemIndices = append(problemIndices, index)
+			report.Failed(index, err)
 		} else {
-			logger.Info(fmt.Sprintf("Successfully set replicas to 0 index=%s", index))
+			logger.WithField("index", index).Info("Successfully set replicas to 0")
 			successfulDereplications = append(successfulDereplications, index)
+			report.Succeeded(index)
 		}
 	}
 
@@ -113,21 +118,21 @@ func runDereplicator(cmd *cobra.Command, args []string) error {
 		if len(successfulDereplications) > 0 {
 			logger.Info(fmt.Sprintf("Successfully dereplicated: %d indices", len(successfulDereplications)))
 			for _, name := range successfulDereplications {
-				logger.Info(fmt.Sprintf("  ✓ %s", name))
+				logger.WithField("index", name).Info("  ✓")
 			}
 		}
 		if len(problemIndices) > 0 {
 			logger.Info("")
 			logger.Info(fmt.Sprintf("Failed to dereplicate: %d indices", len(problemIndices)))
 			for _, name := range problemIndices {
-				logger.Info(fmt.Sprintf("  ✗ %s", name))
+				logger.WithField("index", name).Info("  ✗")
 			}
 		}
 		if len(skippedNoSnapshot) > 0 {
 			logger.Info("")
 			logger.Info(fmt.Sprintf("Skipped (no valid snapshot): %d indices", len(skippedNoSnapshot)))
 			for _, name := range skippedNoSnapshot {
-				logger.Info(fmt.Sprintf("  - %s", name))
+				logger.WithField("index", name).Info("  -")
 			}
 		}
 		if len(successfulDereplications) == 0 && len(problemIndices) == 0 && len(skippedNoSnapshot) == 0 {
@@ -137,11 +142,11 @@ func runDereplicator(cmd *cobra.Command, args []string) error {
 	}
 
 	if len(skippedNoSnapshot) > 0 {
-		logger.Warn(fmt.Sprintf("Skipped (no valid snapshot) %s", strings.Join(skippedNoSnapshot, ", ")))
+		logger.WithField("indices", strings.Join(skippedNoSnapshot, ", ")).Warn("Skipped (no valid snapshot)")
 	}
 
 	if len(problemIndices) > 0 {
-		logger.Warn(fmt.Sprintf("Problem indices %s", strings.Join(problemIndices, ", ")))
+		logger.WithField("indices", strings.Join(problemIndices, ", ")).Warn("Problem indices")
 		return fmt.Errorf("failed to process %d indices", len(problemIndices))
 	}
 
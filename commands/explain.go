@@ -0,0 +1,202 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"osctl/pkg/config"
+	"osctl/pkg/logging"
+	"osctl/pkg/utils"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var explainCmd = &cobra.Command{
+	Use:   "explain <index-or-snapshot>",
+	Short: "Explain which policy applies to an index or snapshot",
+	Long: `Show the osctl-indices-config rule that matches an index or snapshot, with its line in the
+config file, the rules it shadows, special handling of system and extracted_ indices, the computed
+delete, snapshot, snapshot expiry, dereplicate and cold dates, and what every action would do to the
+object today. The name is looked up as an index first, then as a snapshot in the configured repositories.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runExplain,
+}
+
+func init() {
+	addFlags(explainCmd)
+}
+
+func runExplain(cmd *cobra.Command, args []string) error {
+	cfg := config.GetConfig()
+	logger := logging.NewLogger()
+	name := args[0]
+
+	client, err := utils.NewOSClientWithURL(cfg, cfg.GetOpenSearchURL())
+	if err != nil {
+		return fmt.Errorf("failed to create OpenSearch client: %v", err)
+	}
+	indicesConfig, err := cfg.GetOsctlIndices()
+	if err != nil {
+		return fmt.Errorf("failed to get osctl indices: %v", err)
+	}
+
+	facts := utils.IndexFacts{ValidSnapshot: map[string]bool{}}
+	indices, err := client.GetIndicesWithFields(name, "index,rep")
+	if err != nil && !strings.Contains(err.Error(), "index_not_found_exception") && !strings.Contains(err.Error(), "404") {
+		return fmt.Errorf("failed to get index %s: %v", name, err)
+	}
+	for _, idx := range indices {
+		if idx.Index == name {
+			facts.Found = true
+			facts.Replicas = idx.Rep
+		}
+	}
+
+	repos := utils.SnapshotRepositoriesFor(cfg.GetSnapshotRepo(), indicesConfig)
+	for _, ic := range indicesConfig {
+		if ic.SecondaryRepository != "" && !slices.Contains(repos, ic.SecondaryRepository) {
+			repos = append(repos, ic.SecondaryRepository)
+		}
+	}
+
+	var explanation *utils.Explanation
+	if !facts.Found {
+		for _, repo := range repos {
+			snapshots, err := utils.GetSnapshotsIgnore404(client, repo, name)
+			if err != nil {
+				logger.WithFields(logging.Fields{"repo": repo, "error": err}).Warn("Failed to look up snapshot")
+				continue
+			}
+			if len(snapshots) > 0 {
+				explanation, err = utils.ExplainSnapshot(cfg, snapshots[0], repo)
+				if err != nil {
+					return err
+				}
+				break
+			}
+		}
+	}
+
+	if explanation == nil {
+		if facts.Found {
+			if facts.ColdRequirement, err = client.GetIndexColdRequirement(name); err != nil {
+				logger.WithFields(logging.Fields{"index": name, "error": err}).Warn("Failed to read index tier")
+			}
+			for _, repo := range explainSnapshotRepos(cfg, indicesConfig, name) {
+				snapshots, err := utils.GetSnapshotsIgnore404(client, repo, "*")
+				if err != nil {
+					logger.WithFields(logging.Fields{"repo": repo, "error": err}).Warn("Failed to list snapshots")
+					continue
+				}
+				facts.ValidSnapshot[repo] = utils.HasValidSnapshot(name, snapshots)
+			}
+		}
+		explanation, err = utils.ExplainIndex(cfg, name, facts)
+		if err != nil {
+			return err
+		}
+	}
+	logger.WithFields(logging.Fields{"name": name, "type": explanation.Type, "found": explanation.Found}).Info("Explained")
+
+	if cfg.GetExplainFormat() == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(explanation)
+	}
+	return writeExplanation(os.Stdout, explanation, cfg.GetOSCTLIndicesConfig())
+}
+
+func explainSnapshotRepos(cfg *config.Config, indicesConfig []config.IndexConfig, index string) []string {
+	var repos []string
+	if cfg.GetSnapshotRepo() != "" {
+		repos = append(repos, cfg.GetSnapshotRepo())
+	}
+	if ic := utils.FindMatchingIndexConfig(index, indicesConfig); ic != nil && ic.Snapshot && ic.Repository != "" && !slices.Contains(repos, ic.Repository) {
+		repos = append(repos, ic.Repository)
+	}
+	return repos
+}
+
+func describeRule(m utils.RuleMatch, path string) string {
+	ic := m.Rule
+	parts := []string{ic.Kind + " " + ic.Value}
+	if ic.Name != "" {
+		parts = append(parts, "name="+ic.Name)
+	}
+	if ic.System {
+		parts = append(parts, "system")
+	}
+	parts = append(parts, fmt.Sprintf("days_count=%d", ic.DaysCount), fmt.Sprintf("snapshot=%t", ic.Snapshot))
+	if ic.ManualSnapshot {
+		parts = append(parts, "manual_snapshot")
+	}
+	if ic.SnapshotCountS3 > 0 {
+		parts = append(parts, fmt.Sprintf("snapshot_count_s3=%d", ic.SnapshotCountS3))
+	}
+	if ic.Repository != "" {
+		parts = append(parts, "repository="+ic.Repository)
+	}
+	if ic.SecondaryRepository != "" {
+		parts = append(parts, "secondary_repository="+ic.SecondaryRepository)
+	}
+	desc := strings.Join(parts, " ")
+	if m.Line > 0 {
+		desc += fmt.Sprintf(" (%s:%d)", path, m.Line)
+	}
+	return desc
+}
+
+func writeExplanation(w io.Writer, e *utils.Explanation, path string) error {
+	found := "found"
+	if !e.Found {
+		found = "not found"
+	}
+	header := fmt.Sprintf("%s %s (%s)", strings.ToUpper(e.Type), e.Name, found)
+	if e.Repository != "" {
+		header += " in " + e.Repository
+	}
+	fmt.Fprintln(w, header)
+	if e.Date != "" {
+		fmt.Fprintln(w, "date: "+e.Date)
+	}
+
+	fmt.Fprintln(w, "")
+	switch {
+	case e.Matched != nil:
+		fmt.Fprintln(w, "RULE "+describeRule(*e.Matched, path))
+	case e.Unknown:
+		fmt.Fprintln(w, "RULE unknown (no rule matched, unknown section applies)")
+	default:
+		fmt.Fprintln(w, "RULE none")
+	}
+	for _, m := range e.Shadowed {
+		fmt.Fprintln(w, "  shadows "+describeRule(m, path))
+	}
+	for _, note := range e.Notes {
+		fmt.Fprintln(w, "  note: "+note)
+	}
+
+	if len(e.Events) > 0 {
+		fmt.Fprintln(w, "")
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "EVENT\tDATE\tBY")
+		for _, ev := range e.Events {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", ev.Event, ev.Date, ev.Note)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintln(w, "")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ACTION\tTODAY")
+	for _, a := range e.Actions {
+		fmt.Fprintf(tw, "%s\t%s\n", a.Action, a.Decision)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,237 @@
+package commands
+
+import (
+	"fmt"
+	"osctl/pkg/config"
+	"osctl/pkg/kibana"
+	"osctl/pkg/logging"
+	"osctl/pkg/opensearch"
+	"osctl/pkg/report"
+	"osctl/pkg/utils"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+const extractTempPrefix = "osctl-extract-"
+
+var extractCmd = &cobra.Command{
+	Use:   "extract",
+	Short: "Extract a time range of a prefix from a snapshot into the recoverer",
+	Long: `Restore the indices of the given prefixes from the snapshot of --date into temporary
+indices on the recoverer, _reindex only documents inside [extract-time-from, extract-time-to)
+and matching the optional extract-query into <extracted_pattern><index>_<HHMM>-<HHMM>_<restore-date>,
+delete the temporary indices and create a Kibana index pattern for every result.`,
+	RunE: runExtract,
+}
+
+func init() {
+	addFlags(extractCmd)
+}
+
+type extractResult struct {
+	index  string
+	target string
+	docs   int64
+	err    error
+}
+
+func runExtract(cmd *cobra.Command, args []string) error {
+	cfg := config.GetConfig()
+	logger := logging.NewLogger()
+	client, err := utils.NewOSClientWithURL(cfg, cfg.GetOpenSearchRecovererURL())
+	if err != nil {
+		return fmt.Errorf("failed to create OpenSearch recoverer client: %v", err)
+	}
+
+	repo := cfg.GetSnapshotRepo()
+	date := cfg.GetRestoreDate()
+	dateFormat := cfg.GetDateFormat()
+	from, err := utils.ParseExtractTime(cfg.GetExtractTimeFrom(), date, dateFormat)
+	if err != nil {
+		return fmt.Errorf("invalid extract-time-from: %v", err)
+	}
+	to, err := utils.ParseExtractTime(cfg.GetExtractTimeTo(), date, dateFormat)
+	if err != nil {
+		return fmt.Errorf("invalid extract-time-to: %v", err)
+	}
+	if !to.After(from) {
+		return fmt.Errorf("extract-time-to (%s) must be after extract-time-from (%s)", to.Format(time.RFC3339), from.Format(time.RFC3339))
+	}
+
+	var filter []string
+	for _, prefix := range cfg.GetRestorePrefixes() {
+		filter = append(filter, prefix+"-*")
+	}
+	timeField := cfg.GetExtractTimeField()
+	query := cfg.GetExtractQuery()
+	timeout := cfg.GetExtractTimeout()
+	extractedPattern := cfg.GetExtractedPattern()
+	if extractedPattern == "" {
+		extractedPattern = "extracted_"
+	}
+	restoreDate := utils.FormatDate(time.Now(), cfg.GetRecovererDateFormat())
+	logger.WithFields(logging.Fields{"repo": repo, "date": date, "prefixes": strings.Join(cfg.GetRestorePrefixes(), ","), "from": from.Format(time.RFC3339), "to": to.Format(time.RFC3339), "timeField": timeField, "query": query, "dryRun": cfg.GetDryRun()}).Info("Starting extract")
+
+	if err := utils.CheckSnapshotRepositories(client, []string{repo}, logger); err != nil {
+		return err
+	}
+
+	snapshots, err := utils.GetSnapshotsIgnore404(client, repo, "*"+date+"*")
+	if err != nil {
+		return fmt.Errorf("failed to get snapshots repo=%s date=%s: %v", repo, date, err)
+	}
+	type extractSource struct {
+		snapshot string
+		index    string
+	}
+	var sources []extractSource
+	seen := make(map[string]bool)
+	for _, s := range snapshots {
+		matched := utils.FilterIndices(s.Indices, filter)
+		if len(matched) == 0 {
+			continue
+		}
+		if s.State != "SUCCESS" {
+			logger.WithFields(logging.Fields{"snapshot": s.Snapshot, "state": s.State}).Warn("Snapshot is not SUCCESS, skipping")
+			continue
+		}
+		for _, idx := range matched {
+			if utils.ShouldSkipIndex(idx) || seen[idx] {
+				continue
+			}
+			seen[idx] = true
+			sources = append(sources, extractSource{snapshot: s.Snapshot, index: idx})
+		}
+	}
+	if len(sources) == 0 {
+		return fmt.Errorf("no SUCCESS snapshots with indices matching %s found for date=%s in repo=%s", strings.Join(filter, ","), date, repo)
+	}
+	logger.Info(fmt.Sprintf("Indices to extract: %d", len(sources)))
+
+	if cfg.GetDryRun() {
+		for i, src := range sources {
+			target := utils.ExtractIndexName(extractedPattern, src.index, from, to, restoreDate)
+			logger.WithFields(logging.Fields{"snapshot": src.snapshot, "index": src.index, "temp": extractTempPrefix + src.index, "target": target}).Info(fmt.Sprintf("DRY RUN: Would extract %d", i+1))
+			report.Skipped(target, "dry run")
+		}
+		return nil
+	}
+
+	var kb *kibana.Client
+	dataSourceID := ""
+	tenant := cfg.GetExtractTenant()
+	if osdURL := utils.NormalizeURL(cfg.GetOSDURL()); osdURL != "" {
+		kb = kibana.NewClient(osdURL, cfg.GetKibanaUser(), cfg.GetKibanaPass(), cfg.GetTimeout())
+		if name := cfg.GetDataSourceName(); name != "" {
+			dataSourceID, err = kb.FindDataSourceID(tenant, name)
+			if err != nil {
+				logger.WithFields(logging.Fields{"title": name, "error": err}).Warn("Failed to look up data source")
+			} else if dataSourceID == "" {
+				logger.WithFields(logging.Fields{"tenant": tenant, "title": name}).Warn("Data source not found, index patterns will be created without a data-source reference")
+			}
+		}
+	} else {
+		logger.Warn("osd-url is not set — index patterns will not be created")
+	}
+
+	var results []extractResult
+	for _, src := range sources {
+		target := utils.ExtractIndexName(extractedPattern, src.index, from, to, restoreDate)
+		r := extractResult{index: src.index, target: target}
+		start := time.Now()
+		r.docs, r.err = extractIndex(client, repo, src.snapshot, src.index, target, utils.ExtractQuery(timeField, from, to, query), timeout, logger)
+		if r.err == nil && r.docs > 0 && kb != nil {
+			if err := kb.CreateIndexPattern(tenant, uuid.NewString(), target, timeField, dataSourceID); err != nil {
+				r.err = fmt.Errorf("extracted, but failed to create index pattern: %v", err)
+			} else {
+				logger.WithFields(logging.Fields{"pattern": target, "tenant": tenant}).Info("Created index pattern")
+			}
+		}
+		results = append(results, r)
+		if r.err != nil {
+			report.Failed(target, r.err).Took(time.Since(start))
+		} else {
+			report.Succeeded(target).Took(time.Since(start))
+		}
+	}
+
+	failed := 0
+	logger.Info(strings.Repeat("=", 60))
+	logger.Info("EXTRACT SUMMARY")
+	logger.Info(strings.Repeat("=", 60))
+	for _, r := range results {
+		if r.err != nil {
+			failed++
+			logger.WithFields(logging.Fields{"index": r.index, "target": r.target, "error": r.err}).Info("  ✗")
+			continue
+		}
+		logger.WithFields(logging.Fields{"index": r.index, "target": r.target, "docs": r.docs}).Info("  ✓")
+	}
+	logger.Info(strings.Repeat("=", 60))
+
+	if failed > 0 {
+		return fmt.Errorf("extract failed for %d of %d indices", failed, len(results))
+	}
+	return nil
+}
+
+func extractIndex(client *opensearch.Client, repo, snapshot, index, target string, query map[string]any, timeout time.Duration, logger *logging.Logger) (int64, error) {
+	temp := extractTempPrefix + index
+	for _, name := range []string{temp, target} {
+		if exists, err := client.IndexExists(name); err == nil && exists {
+			logger.WithField("index", name).Warn("Index left from a previous run, deleting")
+			if err := client.DeleteIndex(name); err != nil {
+				return 0, fmt.Errorf("failed to delete stale index %s: %v", name, err)
+			}
+		}
+	}
+
+	utils.WaitForOurRestoreSlot(client, []string{extractTempPrefix + "*"}, 1, 30*time.Second, logger, 0)
+
+	logger.WithFields(logging.Fields{"repo": repo, "snapshot": snapshot, "index": index, "temp": temp}).Info("Restore started")
+	if err := client.RestoreSnapshot(repo, snapshot, utils.RestoreBodyWithRename(index, temp)); err != nil {
+		return 0, fmt.Errorf("restore request failed: %v", err)
+	}
+	keepTemp := false
+	defer func() {
+		if keepTemp {
+			logger.WithField("index", temp).Warn("Temporary index kept, delete it after the reindex task stops")
+			return
+		}
+		if err := client.DeleteIndex(temp); err != nil {
+			logger.WithFields(logging.Fields{"index": temp, "error": err}).Error("Failed to delete temporary index")
+		} else {
+			logger.WithField("index", temp).Info("Temporary index deleted")
+		}
+	}()
+	if err := utils.WaitForVerifyRestore(client, temp, 30*time.Second, timeout, logger); err != nil {
+		return 0, err
+	}
+
+	body := map[string]any{
+		"source": map[string]any{"index": temp, "query": query},
+		"dest":   map[string]any{"index": target},
+	}
+	taskID, err := client.StartReindex(body)
+	if err != nil {
+		return 0, fmt.Errorf("reindex request failed: %v", err)
+	}
+	logger.WithFields(logging.Fields{"task": taskID, "source": temp, "dest": target}).Info("Reindex started")
+	created, err := utils.WaitForReindexTask(client, taskID, 10*time.Second, timeout, logger)
+	if err != nil {
+		if status, statusErr := client.GetTask(taskID); statusErr != nil || !status.Completed {
+			if cancelErr := utils.CancelReindexTask(client, taskID, 10*time.Second, 5*time.Minute, logger); cancelErr != nil {
+				keepTemp = true
+				return created, fmt.Errorf("%v; %v, task %s may still be running", err, cancelErr, taskID)
+			}
+		}
+		return created, err
+	}
+	if created == 0 {
+		logger.WithField("index", index).Warn("No documents matched the time range and query")
+	}
+	return created, nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"osctl/pkg/config"
 	"osctl/pkg/logging"
+	"osctl/pkg/report"
 	"osctl/pkg/utils"
 	"strings"
 	"time"
@@ -34,29 +35,31 @@ func runExtractedDelete(cmd *cobra.Command, args []string) error {
 	}
 
 	cutoffDate := utils.FormatDate(time.Now().AddDate(0, 0, -days), dateFormat)
-	logger.Info(fmt.Sprintf("Starting extracted indices deletion days=%d cutoffDate=%s dryRun=%t", days, cutoffDate, cfg.GetDryRun()))
+	logger.WithFields(logging.Fields{"days": days, "cutoffDate": cutoffDate, "dryRun": cfg.GetDryRun()}).Info("Starting extracted indices deletion")
 
 	pattern := cfg.GetExtractedPattern()
 	if pattern == "" {
 		pattern = "extracted_"
 	}
-	allIndices, err := client.GetIndicesWithFields(pattern+"*", "index")
+	allIndices, err := client.GetIndicesWithFields(pattern+"*", "index,ss")
 	if err != nil {
 		return fmt.Errorf("failed to get extracted indices: %v", err)
 	}
-	logger.Info(fmt.Sprintf("Retrieved extracted indices from OpenSearch count=%d", len(allIndices)))
+	logger.WithField("count", len(allIndices)).Info("Retrieved extracted indices from OpenSearch")
 
 	names := utils.IndexInfosToNames(allIndices)
 	if len(names) > 0 {
-		logger.Info(fmt.Sprintf("Found extracted indices %s", strings.Join(names, ", ")))
+		logger.WithField("indices", strings.Join(names, ", ")).Info("Found extracted indices")
 	} else {
 		logger.Info("Found extracted indices none")
 	}
 
 	var extractedIndices []string
+	sizes := make(map[string]string, len(allIndices))
 	for _, index := range allIndices {
-		if utils.IsOlderThanCutoff(index.Index, cutoffDate, dateFormat) {
+		if utils.IsLastDateOlderThanCutoff(index.Index, cutoffDate, dateFormat) {
 			extractedIndices = append(extractedIndices, index.Index)
+			sizes[index.Index] = index.Size
 		}
 	}
 
@@ -65,24 +68,31 @@ func runExtractedDelete(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	logger.Info(fmt.Sprintf("Found extracted indices for deletion count=%d", len(extractedIndices)))
-	logger.Info(fmt.Sprintf("Extracted indices to delete %s", strings.Join(extractedIndices, ", ")))
+	logger.WithField("count", len(extractedIndices)).Info("Found extracted indices for deletion")
+	logger.WithField("indices", strings.Join(extractedIndices, ", ")).Info("Extracted indices to delete")
 
 	if cfg.GetDryRun() {
-		logger.Info(fmt.Sprintf("DRY RUN: Would delete extracted indices indices=%v", extractedIndices))
+		logger.WithField("indices", extractedIndices).Info("DRY RUN: Would delete extracted indices")
+		for _, index := range extractedIndices {
+			report.Skipped(index, "dry run")
+		}
 		return nil
 	}
 
 	for _, index := range extractedIndices {
-		logger.Info(fmt.Sprintf("Deleting extracted index index=%s", index))
-		if err := client.DeleteIndex(index); err != nil {
-			logger.Error(fmt.Sprintf("Failed to delete extracted index index=%s error=%v", index, err))
+		logger.WithField("index", index).Info("Deleting extracted index")
+		start := time.Now()
+		if err := client.WithAuditReason("extracted index cleanup").DeleteIndex(index); err != nil {
+			logger.WithFields(logging.Fields{"index": index, "error": err}).Error("Failed to delete extracted index")
+			report.Failed(index, err)
 			continue
 		}
 
-		logger.Info(fmt.Sprintf("Deleted extracted index index=%s", index))
+		logger.WithField("index", index).Info("Deleted extracted index")
+		report.Succeeded(index).Took(time.Since(start))
+		utils.RecordDeletedIndices([]string{index}, sizes)
 	}
 
-	logger.Info(fmt.Sprintf("Extracted indices deletion completed processed=%d", len(extractedIndices)))
+	logger.WithField("processed", len(extractedIndices)).Info("Extracted indices deletion completed")
 	return nil
 }
@@ -7,6 +7,8 @@ import (
 	"osctl/pkg/config"
 	"osctl/pkg/logging"
 	"osctl/pkg/opensearch"
+	"osctl/pkg/report"
+	"osctl/pkg/state"
 	"osctl/pkg/utils"
 	"strings"
 	"time"
@@ -22,13 +24,6 @@ type snapshotFullPrefixPlan struct {
 	sizes   map[string]int64
 }
 
-func fullPrefixRepo(defaultRepo string, ic config.IndexConfig) string {
-	if ic.Repository != "" {
-		return ic.Repository
-	}
-	return defaultRepo
-}
-
 func fullPrefixListPattern(ic config.IndexConfig) string {
 	base := ic.Value
 	if ic.Kind == "regex" {
@@ -83,12 +78,13 @@ func runSnapshotFullPrefixCreate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create OpenSearch client: %v", err)
 	}
 
-	var madisonClient *alerts.Client
-	if cfg.GetMadisonKey() != "" && cfg.GetOSDURL() != "" && cfg.GetMadisonURL() != "" {
-		madisonClient = alerts.NewMadisonClient(cfg.GetMadisonKey(), cfg.GetOSDURL(), cfg.GetMadisonURL())
+	if err := utils.CheckSnapshotRepositories(client, utils.SnapshotRepositoriesFor(defaultRepo, indicesConfig), logger); err != nil {
+		return err
 	}
 
-	logger.Info(fmt.Sprintf("Starting full-prefix snapshot creation date=%s prefixesConfigured=%d es5Compatibility=%t", today, len(indicesConfig), cfg.GetES5Compatibility()))
+	notifier := utils.NewNotifier(cfg)
+
+	logger.WithFields(logging.Fields{"date": today, "prefixesConfigured": len(indicesConfig), "es5Compatibility": cfg.GetES5Compatibility()}).Info("Starting full-prefix snapshot creation")
 
 	var plan []snapshotFullPrefixPlan
 	for _, ic := range indicesConfig {
@@ -98,18 +94,18 @@ func runSnapshotFullPrefixCreate(cmd *cobra.Command, args []string) error {
 
 		indices, sizes, err := utils.ResolveOpenIndicesForPrefix(client, ic)
 		if err != nil {
-			logger.Error(fmt.Sprintf("Failed to resolve indices for prefix value=%s error=%v", ic.Value, err))
+			logger.WithFields(logging.Fields{"value": ic.Value, "error": err}).Error("Failed to resolve indices for prefix")
 			continue
 		}
 		if len(indices) == 0 {
-			logger.Info(fmt.Sprintf("No open indices for prefix, skipping value=%s", ic.Value))
+			logger.WithField("value", ic.Value).Info("No open indices for prefix, skipping")
 			continue
 		}
 
-		repo := fullPrefixRepo(defaultRepo, ic)
+		repo := utils.SourceRepository(defaultRepo, ic)
 		snap := utils.BuildSnapshotNameFromConfig(ic, today)
 		plan = append(plan, snapshotFullPrefixPlan{cfg: ic, repo: repo, snap: snap, indices: indices, sizes: sizes})
-		logger.Info(fmt.Sprintf("Prefix planned value=%s snapshot=%s repo=%s openIndices=%d", ic.Value, snap, repo, len(indices)))
+		logger.WithFields(logging.Fields{"value": ic.Value, "snapshot": snap, "repo": repo, "openIndices": len(indices)}).Info("Prefix planned")
 	}
 
 	if cfg.GetDryRun() {
@@ -117,15 +113,13 @@ func runSnapshotFullPrefixCreate(cmd *cobra.Command, args []string) error {
 		logger.Info("=" + strings.Repeat("=", 50))
 		for _, p := range plan {
 			logger.Info("")
-			logger.Info(fmt.Sprintf("Snapshot: %s (repo %s)", p.snap, p.repo))
-			logger.Info(fmt.Sprintf("Prefix: %s (%s)", p.cfg.Value, p.cfg.Kind))
-			logger.Info(fmt.Sprintf("Indices (%d):", len(p.indices)))
-			for _, index := range p.indices {
-				logger.Info(fmt.Sprintf("  %s", index))
-			}
+			logger.WithFields(logging.Fields{"repo": p.repo, "snapshot": p.snap, "prefix": p.cfg.Value, "kind": p.cfg.Kind, "count": len(p.indices), "indices": strings.Join(p.indices, ", ")}).Info("Snapshot")
 			logger.Info("=" + strings.Repeat("=", 30))
 		}
 		logger.Info("")
+		for _, p := range plan {
+			report.Skipped(fmt.Sprintf("%s (repo=%s)", p.snap, p.repo), "dry run")
+		}
 		logger.Info(fmt.Sprintf("DRY RUN: Would create %d snapshots", len(plan)))
 		return nil
 	}
@@ -134,6 +128,9 @@ func runSnapshotFullPrefixCreate(cmd *cobra.Command, args []string) error {
 	logger.Info(fmt.Sprintf("Waiting %d seconds before starting snapshot creation to distribute load", randomWaitSeconds))
 	time.Sleep(time.Duration(randomWaitSeconds) * time.Second)
 
+	progress := utils.StartSnapshotProgress(utils.NewStateStore(cfg, client), "snapshots", state.ModeFullPrefix, defaultRepo, today, logger)
+	limiter := utils.NewConcurrencyLimiter(cfg, client, "snapshot", cfg.GetMaxConcurrentSnapshots(), logger)
+
 	tasksByRepo := map[string][]utils.SnapshotTask{}
 	for _, p := range plan {
 		snapName := p.snap
@@ -141,7 +138,7 @@ func runSnapshotFullPrefixCreate(cmd *cobra.Command, args []string) error {
 
 		existing, err := utils.GetSnapshotsIgnore404(client, p.repo, p.snap)
 		if err != nil {
-			logger.Error(fmt.Sprintf("Failed to check snapshot repo=%s snapshot=%s error=%v", p.repo, p.snap, err))
+			logger.WithFields(logging.Fields{"repo": p.repo, "snapshot": p.snap, "error": err}).Error("Failed to check snapshot")
 			continue
 		}
 		if existing == nil {
@@ -149,28 +146,30 @@ func runSnapshotFullPrefixCreate(cmd *cobra.Command, args []string) error {
 		}
 
 		if state, ok := utils.GetSnapshotStateByName(p.snap, existing); ok {
-			if state == "IN_PROGRESS" {
-				logger.Info(fmt.Sprintf("Snapshot is currently IN_PROGRESS, skipping snapshot=%s repo=%s", p.snap, p.repo))
+			if state == "IN_PROGRESS" && !progress.Started(p.repo, p.snap) {
+				logger.WithFields(logging.Fields{"snapshot": p.snap, "repo": p.repo}).Info("Snapshot is currently IN_PROGRESS, skipping")
+				report.Skipped(fmt.Sprintf("%s (repo=%s)", p.snap, p.repo), "in progress")
 				continue
 			}
 			if state == "SUCCESS" {
 				missing := missingSnapshotIndices(p.snap, p.indices, existing)
 				if len(missing) == 0 {
-					logger.Info(fmt.Sprintf("Snapshot already exists with all today's indices, skipping snapshot=%s repo=%s indices=%d", p.snap, p.repo, len(p.indices)))
+					logger.WithFields(logging.Fields{"snapshot": p.snap, "repo": p.repo, "indices": len(p.indices)}).Info("Snapshot already exists with all today's indices, skipping")
+					report.Skipped(fmt.Sprintf("%s (repo=%s)", p.snap, p.repo), "already exists")
 					continue
 				}
 				newName := suffixedSnapshotName(p.snap)
-				logger.Info(fmt.Sprintf("Snapshot exists but is missing indices, creating additional snapshot original=%s new=%s missing=%d", p.snap, newName, len(missing)))
+				logger.WithFields(logging.Fields{"original": p.snap, "new": newName, "missing": len(missing)}).Info("Snapshot exists but is missing indices, creating additional snapshot")
 				snapName = newName
 				snapIndices = missing
 			} else {
-				exists, err := utils.CheckAndCleanSnapshot(p.snap, strings.Join(p.indices, ","), existing, client, p.repo, logger)
+				exists, err := progress.CheckAndCleanSnapshot(p.snap, strings.Join(p.indices, ","), existing, client, p.repo, logger)
 				if err != nil {
-					logger.Error(fmt.Sprintf("Failed to check/clean snapshot snapshot=%s error=%v", p.snap, err))
+					logger.WithFields(logging.Fields{"snapshot": p.snap, "error": err}).Error("Failed to check/clean snapshot")
 					continue
 				}
 				if exists {
-					logger.Info(fmt.Sprintf("Valid snapshot already exists snapshot=%s", p.snap))
+					logger.WithField("snapshot", p.snap).Info("Valid snapshot already exists")
 					continue
 				}
 			}
@@ -188,6 +187,7 @@ func runSnapshotFullPrefixCreate(cmd *cobra.Command, args []string) error {
 			DateStr:      today,
 			PollInterval: 60 * time.Second,
 			Size:         totalSize,
+			Metadata:     utils.NewSnapshotMetadataFromConfig("full_prefix_snapshots", p.cfg),
 		})
 	}
 
@@ -197,11 +197,12 @@ func runSnapshotFullPrefixCreate(cmd *cobra.Command, args []string) error {
 		if len(tasks) == 0 {
 			continue
 		}
-		logger.Info(fmt.Sprintf("Creating snapshots repo=%s count=%d", repo, len(tasks)))
-		successful, failed := utils.CreateSnapshotsInParallel(client, tasks, cfg.GetMaxConcurrentSnapshots(), madisonClient, logger, true)
+		logger.WithFields(logging.Fields{"repo": repo, "count": len(tasks)}).Info("Creating snapshots")
+		successful, failed := utils.CreateSnapshotsInParallel(client, tasks, cfg.GetMaxConcurrentSnapshots(), notifier, logger, true, progress, limiter)
 		successfulSnapshots = append(successfulSnapshots, successful...)
 		failedSnapshots = append(failedSnapshots, failed...)
 	}
+	progress.Finish()
 
 	logger.Info(strings.Repeat("=", 60))
 	logger.Info("FULL-PREFIX SNAPSHOT CREATION SUMMARY")
@@ -209,19 +210,22 @@ func runSnapshotFullPrefixCreate(cmd *cobra.Command, args []string) error {
 	if len(successfulSnapshots) > 0 {
 		logger.Info(fmt.Sprintf("Successfully created: %d snapshots", len(successfulSnapshots)))
 		for _, name := range successfulSnapshots {
-			logger.Info(fmt.Sprintf("  ✓ %s", name))
+			logger.WithField("snapshot", name).Info("  ✓")
 		}
 	}
 	if len(failedSnapshots) > 0 {
 		logger.Info(fmt.Sprintf("Failed to create: %d snapshots", len(failedSnapshots)))
 		for _, name := range failedSnapshots {
-			logger.Info(fmt.Sprintf("  ✗ %s", name))
+			logger.WithField("snapshot", name).Info("  ✗")
 		}
 	}
 	if len(successfulSnapshots) == 0 && len(failedSnapshots) == 0 {
 		logger.Info("No snapshots were created")
 	}
 	logger.Info(strings.Repeat("=", 60))
+	if cfg.GetSnapshotTrends() {
+		reportSnapshotTrends(cfg, client, utils.SnapshotRepositoriesFor(defaultRepo, indicesConfig), today, notifier, logger)
+	}
 	logger.Info("Full-prefix snapshot creation completed")
 	return nil
 }
@@ -242,7 +246,7 @@ func runSnapshotsDeleteFullPrefix(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	logger.Info(fmt.Sprintf("Starting full-prefix snapshot deletion (day-based retention) prefixesConfigured=%d defaultDays=%d", len(indicesConfig), s3Config.UnitCount.All))
+	logger.WithFields(logging.Fields{"prefixesConfigured": len(indicesConfig), "defaultDays": s3Config.UnitCount.All}).Info("Starting full-prefix snapshot deletion (day-based retention)")
 
 	var successfulDeletions []string
 	var failedDeletions []string
@@ -252,26 +256,26 @@ func runSnapshotsDeleteFullPrefix(cmd *cobra.Command, args []string) error {
 			continue
 		}
 
-		repo := fullPrefixRepo(defaultRepo, ic)
+		repo := utils.SourceRepository(defaultRepo, ic)
 		days := ic.SnapshotCountS3
 		if days < 1 {
 			days = s3Config.UnitCount.All
 		}
 		if days < 1 {
-			logger.Warn(fmt.Sprintf("No retention days configured for prefix, skipping deletion value=%s", ic.Value))
+			logger.WithField("value", ic.Value).Warn("No retention days configured for prefix, skipping deletion")
 			continue
 		}
 		cutoffDate := utils.FormatDate(time.Now().AddDate(0, 0, -days), cfg.GetDateFormat())
 
 		snaps, err := utils.GetSnapshotsIgnore404(client, repo, fullPrefixListPattern(ic))
 		if err != nil {
-			logger.Error(fmt.Sprintf("Failed to list snapshots for prefix value=%s repo=%s error=%v", ic.Value, repo, err))
+			logger.WithFields(logging.Fields{"value": ic.Value, "repo": repo, "error": err}).Error("Failed to list snapshots for prefix")
 			continue
 		}
 
 		var toDelete []string
 		for _, s := range snaps {
-			if !utils.MatchesSnapshot(s.Snapshot, ic) {
+			if !utils.SnapshotBelongsToRule(s, ic, indicesConfig) {
 				continue
 			}
 			if s.State == "IN_PROGRESS" {
@@ -286,18 +290,18 @@ func runSnapshotsDeleteFullPrefix(cmd *cobra.Command, args []string) error {
 		}
 
 		if len(toDelete) == 0 {
-			logger.Info(fmt.Sprintf("Nothing to delete for prefix value=%s repo=%s days=%d cutoff=%s", ic.Value, repo, days, cutoffDate))
+			logger.WithFields(logging.Fields{"value": ic.Value, "repo": repo, "days": days, "cutoff": cutoffDate}).Info("Nothing to delete for prefix")
 			continue
 		}
 
-		logger.Info(fmt.Sprintf("Prefix retention value=%s repo=%s days=%d cutoff=%s delete=%d: %s", ic.Value, repo, days, cutoffDate, len(toDelete), strings.Join(toDelete, ", ")))
+		logger.WithFields(logging.Fields{"value": ic.Value, "repo": repo, "days": days, "cutoff": cutoffDate, "count": len(toDelete), "snapshots": strings.Join(toDelete, ", ")}).Info("Prefix retention")
 
 		if cfg.GetDryRun() {
-			logger.Info(fmt.Sprintf("DRY RUN: would delete %d snapshots for prefix value=%s", len(toDelete), ic.Value))
+			logger.WithFields(logging.Fields{"value": ic.Value, "repo": repo, "count": len(toDelete)}).Info("DRY RUN: would delete snapshots for prefix")
 			continue
 		}
 
-		successful, failed, _ := utils.BatchDeleteSnapshots(client, toDelete, repo, cfg.GetDryRun(), logger)
+		successful, failed, _ := utils.BatchDeleteSnapshots(client.WithAuditReason("full-prefix snapshot retention period expired"), toDelete, repo, cfg.GetDryRun(), logger)
 		for _, name := range successful {
 			successfulDeletions = append(successfulDeletions, fmt.Sprintf("%s (repo=%s)", name, repo))
 		}
@@ -313,13 +317,13 @@ func runSnapshotsDeleteFullPrefix(cmd *cobra.Command, args []string) error {
 		if len(successfulDeletions) > 0 {
 			logger.Info(fmt.Sprintf("Successfully deleted: %d snapshots", len(successfulDeletions)))
 			for _, name := range successfulDeletions {
-				logger.Info(fmt.Sprintf("  ✓ %s", name))
+				logger.WithField("snapshot", name).Info("  ✓")
 			}
 		}
 		if len(failedDeletions) > 0 {
 			logger.Info(fmt.Sprintf("Failed to delete: %d snapshots", len(failedDeletions)))
 			for _, name := range failedDeletions {
-				logger.Info(fmt.Sprintf("  ✗ %s", name))
+				logger.WithField("snapshot", name).Info("  ✗")
 			}
 		}
 		if len(successfulDeletions) == 0 && len(failedDeletions) == 0 {
@@ -350,7 +354,7 @@ func runSnapshotsCheckerFullPrefix(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create OpenSearch client: %v", err)
 	}
 
-	logger.Info(fmt.Sprintf("Starting full-prefix snapshot checking prefixesConfigured=%d maxAgeDays=%d", len(indicesConfig), fullPrefixStaleMaxDays))
+	logger.WithFields(logging.Fields{"prefixesConfigured": len(indicesConfig), "maxAgeDays": fullPrefixStaleMaxDays}).Info("Starting full-prefix snapshot checking")
 
 	cutoffDate := utils.FormatDate(time.Now().AddDate(0, 0, -fullPrefixStaleMaxDays), cfg.GetDateFormat())
 
@@ -360,18 +364,18 @@ func runSnapshotsCheckerFullPrefix(cmd *cobra.Command, args []string) error {
 			continue
 		}
 
-		repo := fullPrefixRepo(defaultRepo, ic)
+		repo := utils.SourceRepository(defaultRepo, ic)
 
 		openIndices, _, err := utils.ResolveOpenIndicesForPrefix(client, ic)
 		if err != nil {
-			logger.Error(fmt.Sprintf("Failed to resolve indices for prefix value=%s error=%v", ic.Value, err))
+			logger.WithFields(logging.Fields{"value": ic.Value, "error": err}).Error("Failed to resolve indices for prefix")
 			missing = append(missing, ic.Value)
 			continue
 		}
 
 		snaps, err := utils.GetSnapshotsIgnore404(client, repo, fullPrefixListPattern(ic))
 		if err != nil {
-			logger.Error(fmt.Sprintf("Failed to list snapshots for prefix value=%s repo=%s error=%v", ic.Value, repo, err))
+			logger.WithFields(logging.Fields{"value": ic.Value, "repo": repo, "error": err}).Error("Failed to list snapshots for prefix")
 			missing = append(missing, ic.Value)
 			continue
 		}
@@ -379,7 +383,7 @@ func runSnapshotsCheckerFullPrefix(cmd *cobra.Command, args []string) error {
 		covered := map[string]bool{}
 		recentSnapshots := 0
 		for _, s := range snaps {
-			if s.State != "SUCCESS" || !utils.MatchesSnapshot(s.Snapshot, ic) {
+			if s.State != "SUCCESS" || !utils.SnapshotBelongsToRule(s, ic, indicesConfig) {
 				continue
 			}
 			if !utils.HasDateInName(s.Snapshot, cfg.GetDateFormat()) {
@@ -395,7 +399,7 @@ func runSnapshotsCheckerFullPrefix(cmd *cobra.Command, args []string) error {
 		}
 
 		if recentSnapshots == 0 {
-			logger.Warn(fmt.Sprintf("No recent successful snapshot for prefix value=%s repo=%s cutoff=%s", ic.Value, repo, cutoffDate))
+			logger.WithFields(logging.Fields{"value": ic.Value, "repo": repo, "cutoff": cutoffDate}).Warn("No recent successful snapshot for prefix")
 			missing = append(missing, ic.Value)
 			continue
 		}
@@ -407,35 +411,41 @@ func runSnapshotsCheckerFullPrefix(cmd *cobra.Command, args []string) error {
 			}
 		}
 		if len(missingIndices) > 0 {
-			logger.Warn(fmt.Sprintf("Recent snapshot(s) do not cover all open indices prefix=%s repo=%s missing=%d: %s", ic.Value, repo, len(missingIndices), strings.Join(missingIndices, ", ")))
+			logger.WithFields(logging.Fields{"value": ic.Value, "repo": repo, "missingIndicesCount": len(missingIndices), "indices": strings.Join(missingIndices, ", ")}).Warn("Recent snapshots do not cover all open indices")
 			missing = append(missing, missingIndices...)
 			continue
 		}
 
-		logger.Info(fmt.Sprintf("Prefix OK value=%s repo=%s recentSnapshots=%d openIndices=%d", ic.Value, repo, recentSnapshots, len(openIndices)))
+		logger.WithFields(logging.Fields{"value": ic.Value, "repo": repo, "recentSnapshots": recentSnapshots, "openIndices": len(openIndices)}).Info("Prefix OK")
 	}
 
+	notifier := utils.NewNotifier(cfg)
+	if !cfg.GetDryRun() {
+		utils.ResolveAlerts(notifier, alerts.SnapshotsMissing, missing, logger)
+	}
 	if len(missing) == 0 {
 		logger.Info("All prefixes have a recent successful snapshot")
 		logger.Info("Full-prefix snapshot checking completed")
 		return nil
 	}
 
-	logger.Warn(fmt.Sprintf("Prefixes without a recent snapshot count=%d list=%s", len(missing), strings.Join(missing, ", ")))
+	logger.WithFields(logging.Fields{"count": len(missing), "list": strings.Join(missing, ", ")}).Warn("Prefixes without a recent snapshot")
 
 	if cfg.GetDryRun() {
-		logger.Info("DRY RUN: Would send Madison alert for missing snapshots")
+		logger.Info("DRY RUN: Would send alert for missing snapshots")
 		logger.Info("Full-prefix snapshot checking completed")
 		return nil
 	}
 
-	madisonClient := alerts.NewMadisonClient(cfg.GetMadisonKey(), cfg.GetOSDURL(), cfg.GetMadisonURL())
-	response, err := madisonClient.SendMadisonSnapshotMissingAlert(missing, defaultRepo, cfg.GetKubeNamespace(), today)
+	if notifier == nil {
+		return fmt.Errorf("failed to send alert: no alert notifier configured (alert-notifiers)")
+	}
+	response, err := notifier.Notify(alerts.NewSnapshotsMissingEvent(missing, defaultRepo, cfg.GetKubeNamespace(), today))
 	if err != nil {
-		logger.Error(fmt.Sprintf("Failed to send Madison alert error=%v", err))
-		return fmt.Errorf("failed to send Madison alert: %v", err)
+		logger.WithField("error", err).Error("Failed to send alert")
+		return fmt.Errorf("failed to send alert: %v", err)
 	}
-	logger.Info(fmt.Sprintf("Madison alert sent successfully: type=SnapshotMissing response=%s", response))
+	logger.WithFields(logging.Fields{"type": "SnapshotMissing", "notifiers": notifier.Name(), "response": response}).Info("Alert sent successfully")
 
 	logger.Info("Full-prefix snapshot checking completed")
 	return nil
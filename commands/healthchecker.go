@@ -0,0 +1,208 @@
+package commands
+
+import (
+	"fmt"
+	"osctl/pkg/alerts"
+	"osctl/pkg/config"
+	"osctl/pkg/logging"
+	"osctl/pkg/opensearch"
+	"osctl/pkg/report"
+	"osctl/pkg/utils"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var healthCheckerCmd = &cobra.Command{
+	Use:   "healthchecker",
+	Short: "Find red/yellow indices and explain unassigned shards",
+	Long: `Find red and yellow indices and unassigned shards across the cluster, fetch an
+allocation explanation for each shard and group them by reason (disk watermark,
+allocation filter, node left, ...). Sends one deduplicated alert with an
+actionable summary and optionally retries failed allocations (--reroute-failed).`,
+	RunE: runHealthChecker,
+}
+
+func init() {
+	addFlags(healthCheckerCmd)
+}
+
+func runHealthChecker(cmd *cobra.Command, args []string) error {
+	cfg := config.GetConfig()
+	logger := logging.NewLogger()
+	client, err := utils.NewOSClientWithURL(cfg, cfg.GetOpenSearchURL())
+	if err != nil {
+		return fmt.Errorf("failed to create OpenSearch client: %v", err)
+	}
+
+	health, err := client.GetIndicesHealth([]string{"*"})
+	if err != nil {
+		return fmt.Errorf("failed to get indices health: %v", err)
+	}
+	status := "green"
+	var red, yellow []string
+	for name, h := range health {
+		switch h.Status {
+		case "red":
+			red = append(red, name)
+			status = "red"
+		case "yellow":
+			yellow = append(yellow, name)
+			if status == "green" {
+				status = "yellow"
+			}
+		}
+	}
+	sort.Strings(red)
+	sort.Strings(yellow)
+	logger.WithFields(logging.Fields{"total": len(health), "red": len(red), "yellow": len(yellow)}).Info("Indices health")
+
+	shards, err := client.GetUnassignedShards()
+	if err != nil {
+		return fmt.Errorf("failed to get unassigned shards: %v", err)
+	}
+	logger.Info(fmt.Sprintf("Unassigned shards: %d", len(shards)))
+
+	notifier := utils.NewNotifier(cfg)
+	if len(red) == 0 && len(yellow) == 0 && len(shards) == 0 {
+		logger.Info("Cluster is green, nothing to report")
+		if !cfg.GetDryRun() {
+			utils.ResolveAlerts(notifier, alerts.ClusterHealthDegraded, nil, logger)
+		}
+		return nil
+	}
+
+	maxExplain := cfg.GetHealthCheckerMaxExplain()
+	explained := 0
+	seen := make(map[string]bool)
+	groups := make(map[string][]string)
+	groupIndices := make(map[string]map[string]bool)
+	indicesSet := make(map[string]bool)
+	for _, name := range append(red, yellow...) {
+		indicesSet[name] = true
+	}
+	for _, s := range shards {
+		primary := s.Prirep == "p"
+		key := fmt.Sprintf("%s/%s/%t", s.Index, s.Shard, primary)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		indicesSet[s.Index] = true
+
+		reason := utils.ClassifyUnassignedReason(s.UnassignedReason)
+		detail := ""
+		if explained < maxExplain {
+			shardNum, _ := strconv.Atoi(s.Shard)
+			exp, err := client.ExplainAllocation(s.Index, shardNum, primary)
+			explained++
+			if err != nil {
+				logger.WithFields(logging.Fields{"index": s.Index, "shard": s.Shard, "primary": primary, "error": err}).Warn("Failed to explain allocation")
+			} else {
+				reason = utils.ClassifyAllocation(exp)
+				detail = allocationDetail(exp)
+			}
+		}
+		shardDesc := fmt.Sprintf("%s[%s][%s]", s.Index, s.Shard, s.Prirep)
+		logger.WithFields(logging.Fields{"shard": shardDesc, "reason": reason, "unassigned.reason": s.UnassignedReason, "detail": detail}).Info("Unassigned")
+		groups[reason] = append(groups[reason], shardDesc)
+		if groupIndices[reason] == nil {
+			groupIndices[reason] = make(map[string]bool)
+		}
+		groupIndices[reason][s.Index] = true
+	}
+	if explained >= maxExplain && len(seen) > maxExplain {
+		logger.Warn(fmt.Sprintf("Allocation explain limit reached (%d); remaining shards classified by unassigned.reason", maxExplain))
+	}
+
+	reasons := make([]string, 0, len(groups))
+	for r := range groups {
+		reasons = append(reasons, r)
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		if len(groups[reasons[i]]) != len(groups[reasons[j]]) {
+			return len(groups[reasons[i]]) > len(groups[reasons[j]])
+		}
+		return reasons[i] < reasons[j]
+	})
+
+	allocation := make([]alerts.AllocationGroup, 0, len(reasons))
+	for _, r := range reasons {
+		idx := make([]string, 0, len(groupIndices[r]))
+		for name := range groupIndices[r] {
+			idx = append(idx, name)
+		}
+		sort.Strings(idx)
+		allocation = append(allocation, alerts.AllocationGroup{Reason: r, Shards: len(groups[r]), Indices: idx, Advice: utils.AllocationReasonAdvice(r, cfg.GetAlertLanguage())})
+	}
+
+	rerouted := false
+	if cfg.GetHealthCheckerRerouteFailed() && (len(groups[utils.AllocationReasonMaxRetry]) > 0 || len(groups[utils.AllocationReasonAllocationFailed]) > 0) {
+		if cfg.GetDryRun() {
+			logger.Info("DRY RUN: Would run POST _cluster/reroute?retry_failed=true")
+		} else if err := client.RerouteRetryFailed(); err != nil {
+			logger.WithField("error", err).Error("Failed to retry failed allocations")
+		} else {
+			logger.Info("Retried failed allocations via _cluster/reroute?retry_failed=true")
+			rerouted = true
+		}
+	}
+
+	indices := make([]string, 0, len(indicesSet))
+	for name := range indicesSet {
+		indices = append(indices, name)
+	}
+	sort.Strings(indices)
+
+	if !cfg.GetDryRun() {
+		utils.ResolveAlerts(notifier, alerts.ClusterHealthDegraded, indices, logger)
+	}
+	if cfg.GetDryRun() {
+		logger.WithFields(logging.Fields{"status": status, "indices": len(indices)}).Info("DRY RUN: Would send alert for cluster health")
+	} else if notifier != nil {
+		response, err := notifier.Notify(alerts.NewClusterHealthEvent(status, indices, allocation))
+		if err != nil {
+			logger.WithField("error", err).Error("Failed to send alert")
+		} else {
+			logger.WithFields(logging.Fields{"type": "ClusterHealthDegraded", "status": status, "count": len(indices), "notifiers": notifier.Name(), "response": response}).Info("Alert sent successfully")
+		}
+	} else {
+		logger.Warn("No alert notifier is configured (alert-notifiers) — alert skipped")
+	}
+
+	for _, name := range red {
+		report.Failed(name, "health red")
+	}
+	for _, name := range yellow {
+		report.Failed(name, "health yellow")
+	}
+
+	logger.Info(strings.Repeat("=", 60))
+	logger.Info("HEALTHCHECKER SUMMARY")
+	logger.Info(strings.Repeat("=", 60))
+	logger.Info(fmt.Sprintf("Status: %s, red indices: %d, yellow indices: %d, unassigned shards: %d", status, len(red), len(yellow), len(seen)))
+	for _, r := range reasons {
+		logger.WithFields(logging.Fields{"shards": len(groups[r]), "advice": utils.AllocationReasonAdvice(r, "en")}).Info("  ✗ " + r)
+	}
+	if rerouted {
+		logger.Info("  ✓ Failed allocations retried")
+	}
+	logger.Info(strings.Repeat("=", 60))
+	return nil
+}
+
+func allocationDetail(exp *opensearch.AllocationExplanation) string {
+	for _, node := range exp.NodeAllocationDecisions {
+		for _, d := range node.Deciders {
+			if d.Decision == "NO" {
+				return fmt.Sprintf("%s on %s: %s", d.Decider, node.NodeName, d.Explanation)
+			}
+		}
+	}
+	if exp.AllocateExplanation != "" {
+		return exp.AllocateExplanation
+	}
+	return exp.UnassignedInfo.Details
+}
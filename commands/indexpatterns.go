@@ -7,6 +7,7 @@ import (
 	"osctl/pkg/kibana"
 	"osctl/pkg/logging"
 	"osctl/pkg/opensearch"
+	"osctl/pkg/report"
 	"osctl/pkg/utils"
 	"regexp"
 	"strings"
@@ -54,39 +55,43 @@ func runIndexPatterns(cmd *cobra.Command, args []string) error {
 			if err != nil {
 				return err
 			}
-			logger.Info(fmt.Sprintf("Global tenant: will refresh %d existing index-patterns", len(existingIdsTitiles)))
+			logger.WithFields(logging.Fields{"tenant": "global", "count": len(existingIdsTitiles)}).Info("Will refresh existing index-patterns")
 			for ip_id, ip_title := range existingIdsTitiles {
 				if ip_title == "*" {
-					logger.Warn("Global tenant: index-pattern '*' is too general and will be ignored")
+					logger.WithField("tenant", "global").Warn("Index-pattern '*' is too general and will be ignored")
 					continue
 				}
+				plog := logger.WithFields(logging.Fields{"tenant": "global", "id": ip_id, "pattern": ip_title})
 				indices, err := osClient.GetIndicesWithFields(ip_title, "index")
 				if err != nil {
-					logger.Warn(fmt.Sprintf("Global tenant: failed to check indices for pattern %s: %v, will skip refresh", ip_title, err))
+					plog.WithField("error", err).Warn("Failed to check indices for pattern, will skip refresh")
 					continue
 				}
 				if len(indices) == 0 {
-					logger.Info(fmt.Sprintf("Global tenant: skipping index-pattern %s:%s - no matching indices found in cluster", ip_id, ip_title))
+					plog.Info("Skipping index-pattern, no matching indices found in cluster")
 					continue
 				}
 				exists, err := kb.CheckIndexPatternExists("", ip_id)
 				if err != nil {
-					logger.Warn(fmt.Sprintf("Global tenant: failed to check if index-pattern exists %s:%s: %v, will try to refresh anyway", ip_id, ip_title, err))
+					plog.WithField("error", err).Warn("Failed to check if index-pattern exists, will try to refresh anyway")
 				} else if !exists {
-					logger.Info(fmt.Sprintf("Global tenant: index-pattern %s:%s not found in Kibana, skipping refresh", ip_id, ip_title))
+					plog.Info("Index-pattern not found in Kibana, skipping refresh")
 					continue
 				}
 				if cfg.GetDryRun() {
-					logger.Info(fmt.Sprintf("DRY RUN: Would refresh index-pattern %s:%s in tenant global (matches %d indices)", ip_id, ip_title, len(indices)))
+					plog.WithField("matches", len(indices)).Info("DRY RUN: Would refresh index-pattern")
 					refreshedPatterns = append(refreshedPatterns, fmt.Sprintf("%s (tenant=global)", ip_title))
+					report.Skipped(fmt.Sprintf("%s (tenant=global)", ip_title), "dry run")
 				} else {
-					logger.Info(fmt.Sprintf("Refreshing index-pattern %s:%s in tenant global (matches %d indices)", ip_id, ip_title, len(indices)))
+					plog.WithField("matches", len(indices)).Info("Refreshing index-pattern")
 					if err := kb.RefreshIndexPattern("", ip_id, ip_title); err == nil {
-						logger.Info(fmt.Sprintf("Successfully refreshed index-pattern %s:%s in tenant global", ip_id, ip_title))
+						plog.Info("Successfully refreshed index-pattern")
 						refreshedPatterns = append(refreshedPatterns, fmt.Sprintf("%s (tenant=global)", ip_title))
+						report.Succeeded(fmt.Sprintf("%s (tenant=global)", ip_title))
 					} else {
-						logger.Error(fmt.Sprintf("Failed to refresh index-pattern %s:%s in tenant global: %s", ip_id, ip_title, err))
+						plog.WithField("error", err).Error("Failed to refresh index-pattern")
 						failedRefreshedPatterns = append(failedRefreshedPatterns, fmt.Sprintf("%s (tenant=global)", ip_title))
+						report.Failed(fmt.Sprintf("%s (tenant=global)", ip_title), err)
 					}
 				}
 			}
@@ -107,46 +112,50 @@ func runIndexPatterns(cmd *cobra.Command, args []string) error {
 					tenantIndex = aliases[0].Alias
 				}
 				if tenantIndex == "" {
-					logger.Info(fmt.Sprintf("Skip tenant %s: .kibana alias not found", t.Name))
+					logger.WithField("tenant", t.Name).Info("Skip tenant, .kibana alias not found")
 					continue
 				}
 				_, _, existingIdsTitiles, err := getExistingIndexPatternTitles(osClient, tenantIndex)
 				if err != nil {
 					return err
 				}
-				logger.Info(fmt.Sprintf("Tenant %s: will refresh %d existing index-patterns", t.Name, len(existingIdsTitiles)))
+				logger.WithFields(logging.Fields{"tenant": t.Name, "count": len(existingIdsTitiles)}).Info("Will refresh existing index-patterns")
 				for ip_id, ip_title := range existingIdsTitiles {
 					if ip_title == "*" {
-						logger.Warn(fmt.Sprintf("Tenant %s: index-pattern '*' is too general and will be ignored", t.Name))
+						logger.WithField("tenant", t.Name).Warn("Index-pattern '*' is too general and will be ignored")
 						continue
 					}
+					plog := logger.WithFields(logging.Fields{"tenant": t.Name, "id": ip_id, "pattern": ip_title})
 					indices, err := osClient.GetIndicesWithFields(ip_title, "index")
 					if err != nil {
-						logger.Warn(fmt.Sprintf("Tenant %s: failed to check indices for pattern %s: %v, will skip refresh", t.Name, ip_title, err))
+						plog.WithField("error", err).Warn("Failed to check indices for pattern, will skip refresh")
 						continue
 					}
 					if len(indices) == 0 {
-						logger.Info(fmt.Sprintf("Tenant %s: skipping index-pattern %s:%s - no matching indices found in cluster", t.Name, ip_id, ip_title))
+						plog.Info("Skipping index-pattern, no matching indices found in cluster")
 						continue
 					}
 					exists, err := kb.CheckIndexPatternExists(t.Name, ip_id)
 					if err != nil {
-						logger.Warn(fmt.Sprintf("Tenant %s: failed to check if index-pattern exists %s:%s: %v, will try to refresh anyway", t.Name, ip_id, ip_title, err))
+						plog.WithField("error", err).Warn("Failed to check if index-pattern exists, will try to refresh anyway")
 					} else if !exists {
-						logger.Info(fmt.Sprintf("Tenant %s: index-pattern %s:%s not found in Kibana, skipping refresh", t.Name, ip_id, ip_title))
+						plog.Info("Index-pattern not found in Kibana, skipping refresh")
 						continue
 					}
 					if cfg.GetDryRun() {
-						logger.Info(fmt.Sprintf("DRY RUN: Would refresh index-pattern %s:%s in tenant %s (matches %d indices)", ip_id, ip_title, t.Name, len(indices)))
+						plog.WithField("matches", len(indices)).Info("DRY RUN: Would refresh index-pattern")
 						refreshedPatterns = append(refreshedPatterns, fmt.Sprintf("%s (tenant=%s)", ip_title, t.Name))
+						report.Skipped(fmt.Sprintf("%s (tenant=%s)", ip_title, t.Name), "dry run")
 					} else {
-						logger.Info(fmt.Sprintf("Refreshing index-pattern %s:%s in tenant %s (matches %d indices)", ip_id, ip_title, t.Name, len(indices)))
+						plog.WithField("matches", len(indices)).Info("Refreshing index-pattern")
 						if err := kb.RefreshIndexPattern(t.Name, ip_id, ip_title); err == nil {
-							logger.Info(fmt.Sprintf("Successfully refreshed index-pattern %s:%s in tenant %s", ip_id, ip_title, t.Name))
+							plog.Info("Successfully refreshed index-pattern")
 							refreshedPatterns = append(refreshedPatterns, fmt.Sprintf("%s (tenant=%s)", ip_title, t.Name))
+							report.Succeeded(fmt.Sprintf("%s (tenant=%s)", ip_title, t.Name))
 						} else {
-							logger.Error(fmt.Sprintf("Failed to refresh index-pattern %s:%s in tenant %s: %s", ip_id, ip_title, t.Name, err))
+							plog.WithField("error", err).Error("Failed to refresh index-pattern")
 							failedRefreshedPatterns = append(failedRefreshedPatterns, fmt.Sprintf("%s (tenant=%s)", ip_title, t.Name))
+							report.Failed(fmt.Sprintf("%s (tenant=%s)", ip_title, t.Name), err)
 						}
 					}
 				}
@@ -156,39 +165,43 @@ func runIndexPatterns(cmd *cobra.Command, args []string) error {
 			if err != nil {
 				return err
 			}
-			logger.Info(fmt.Sprintf("Will refresh %d existing index-patterns", len(existingIdsTitiles)))
+			logger.WithField("count", len(existingIdsTitiles)).Info("Will refresh existing index-patterns")
 			for ip_id, ip_title := range existingIdsTitiles {
 				if ip_title == "*" {
 					logger.Warn("index-pattern '*' is too general and will be ignored")
 					continue
 				}
+				plog := logger.WithFields(logging.Fields{"id": ip_id, "pattern": ip_title})
 				indices, err := osClient.GetIndicesWithFields(ip_title, "index")
 				if err != nil {
-					logger.Warn(fmt.Sprintf("Failed to check indices for pattern %s: %v, will skip refresh", ip_title, err))
+					plog.WithField("error", err).Warn("Failed to check indices for pattern, will skip refresh")
 					continue
 				}
 				if len(indices) == 0 {
-					logger.Info(fmt.Sprintf("Skipping index-pattern %s:%s - no matching indices found in cluster", ip_id, ip_title))
+					plog.Info("Skipping index-pattern, no matching indices found in cluster")
 					continue
 				}
 				exists, err := kb.CheckIndexPatternExists("", ip_id)
 				if err != nil {
-					logger.Warn(fmt.Sprintf("Failed to check if index-pattern exists %s:%s: %v, will try to refresh anyway", ip_id, ip_title, err))
+					plog.WithField("error", err).Warn("Failed to check if index-pattern exists, will try to refresh anyway")
 				} else if !exists {
-					logger.Info(fmt.Sprintf("Index-pattern %s:%s not found in Kibana, skipping refresh", ip_id, ip_title))
+					plog.Info("Index-pattern not found in Kibana, skipping refresh")
 					continue
 				}
 				if cfg.GetDryRun() {
-					logger.Info(fmt.Sprintf("DRY RUN: Would refresh index-pattern %s:%s (matches %d indices)", ip_id, ip_title, len(indices)))
+					plog.WithField("matches", len(indices)).Info("DRY RUN: Would refresh index-pattern")
 					refreshedPatterns = append(refreshedPatterns, ip_title)
+					report.Skipped(ip_title, "dry run")
 				} else {
-					logger.Info(fmt.Sprintf("Refreshing index-pattern %s:%s (matches %d indices)", ip_id, ip_title, len(indices)))
+					plog.WithField("matches", len(indices)).Info("Refreshing index-pattern")
 					if err := kb.RefreshIndexPattern("", ip_id, ip_title); err == nil {
-						logger.Info(fmt.Sprintf("Successfully refreshed index-pattern %s:%s", ip_id, ip_title))
+						plog.Info("Successfully refreshed index-pattern")
 						refreshedPatterns = append(refreshedPatterns, ip_title)
+						report.Succeeded(ip_title)
 					} else {
-						logger.Error(fmt.Sprintf("Failed to refresh index-pattern %s:%s: %s", ip_id, ip_title, err))
+						plog.WithField("error", err).Error("Failed to refresh index-pattern")
 						failedRefreshedPatterns = append(failedRefreshedPatterns, ip_title)
+						report.Failed(ip_title, err)
 					}
 				}
 			}
@@ -219,38 +232,38 @@ func runIndexPatterns(cmd *cobra.Command, args []string) error {
 				tenantIndex = aliases[0].Alias
 			}
 			if tenantIndex == "" {
-				logger.Info(fmt.Sprintf("Skip tenant %s: .kibana alias not found", t.Name))
+				logger.WithField("tenant", t.Name).Info("Skip tenant, .kibana alias not found")
 				continue
 			}
 			existing, existingTitles, _, err := getExistingIndexPatternTitles(osClient, tenantIndex)
 			if err != nil {
 				return err
 			}
-			logger.Info(fmt.Sprintf("Tenant %s existing index patterns (%d): %s", t.Name, len(existingTitles), strings.Join(existingTitles, ", ")))
+			logger.WithFields(logging.Fields{"tenant": t.Name, "count": len(existingTitles), "patterns": strings.Join(existingTitles, ", ")}).Info("Existing index patterns")
 			toCreate := []string{}
 			seen := map[string]struct{}{}
-			logger.Info(fmt.Sprintf("Tenant %s: checking patterns from config (%d): %s", t.Name, len(t.Patterns), strings.Join(t.Patterns, ", ")))
+			logger.WithFields(logging.Fields{"tenant": t.Name, "count": len(t.Patterns), "patterns": strings.Join(t.Patterns, ", ")}).Info("Checking patterns from config")
 			for _, p := range t.Patterns {
 				pp := strings.TrimSpace(p)
 				if pp == "" {
 					continue
 				}
 				if _, ok := seen[pp]; ok {
-					logger.Info(fmt.Sprintf("Tenant %s: pattern %s already seen, skipping", t.Name, pp))
+					logger.WithFields(logging.Fields{"tenant": t.Name, "pattern": pp}).Info("Pattern already seen, skipping")
 					continue
 				}
 				seen[pp] = struct{}{}
 				if _, ok := existing[pp]; !ok {
-					logger.Info(fmt.Sprintf("Tenant %s: pattern %s not found in existing, will create", t.Name, pp))
+					logger.WithFields(logging.Fields{"tenant": t.Name, "pattern": pp}).Info("Pattern not found in existing, will create")
 					toCreate = append(toCreate, pp)
 				} else {
-					logger.Info(fmt.Sprintf("Tenant %s: pattern %s already exists, skipping", t.Name, pp))
+					logger.WithFields(logging.Fields{"tenant": t.Name, "pattern": pp}).Info("Pattern already exists, skipping")
 				}
 			}
 			if len(toCreate) == 0 {
-				logger.Info(fmt.Sprintf("Tenant %s: no new index patterns to create", t.Name))
+				logger.WithField("tenant", t.Name).Info("No new index patterns to create")
 			} else {
-				logger.Info(fmt.Sprintf("Tenant %s: will create index patterns: %s", t.Name, strings.Join(toCreate, ", ")))
+				logger.WithFields(logging.Fields{"tenant": t.Name, "patterns": strings.Join(toCreate, ", ")}).Info("Will create index patterns")
 			}
 			for _, p := range toCreate {
 				payload := map[string]any{
@@ -262,15 +275,17 @@ func runIndexPatterns(cmd *cobra.Command, args []string) error {
 				}
 				id := fmt.Sprintf("index-pattern:%s", uuid.NewString())
 				if cfg.GetDryRun() {
-					logger.Info(fmt.Sprintf("DRY RUN: Would create index pattern %s in tenant %s", p, t.Name))
+					logger.WithFields(logging.Fields{"tenant": t.Name, "pattern": p}).Info("DRY RUN: Would create index pattern")
 					createdPatterns = append(createdPatterns, fmt.Sprintf("%s (tenant=%s)", p, t.Name))
+					report.Skipped(fmt.Sprintf("%s (tenant=%s)", p, t.Name), "dry run")
 					continue
 				}
 				if err := osClient.CreateDoc(tenantIndex, id, payload); err != nil {
 					return err
 				}
-				logger.Info(fmt.Sprintf("Created index pattern %s in tenant %s", p, t.Name))
+				logger.WithFields(logging.Fields{"tenant": t.Name, "pattern": p}).Info("Created index pattern")
 				createdPatterns = append(createdPatterns, fmt.Sprintf("%s (tenant=%s)", p, t.Name))
+				report.Succeeded(fmt.Sprintf("%s (tenant=%s)", p, t.Name))
 			}
 		}
 	} else {
@@ -297,12 +312,12 @@ func runIndexPatterns(cmd *cobra.Command, args []string) error {
 			}
 		}
 
-		logger.Info(fmt.Sprintf("Required patterns (%d): %s", len(needed), strings.Join(needed, ", ")))
+		logger.WithFields(logging.Fields{"count": len(needed), "patterns": strings.Join(needed, ", ")}).Info("Required patterns")
 		existing, existingTitles, _, err := getExistingIndexPatternTitles(osClient, ".kibana")
 		if err != nil {
 			return err
 		}
-		logger.Info(fmt.Sprintf("Existing index patterns in .kibana (%d): %s", len(existingTitles), strings.Join(existingTitles, ", ")))
+		logger.WithFields(logging.Fields{"count": len(existingTitles), "patterns": strings.Join(existingTitles, ", ")}).Info("Existing index patterns in .kibana")
 		toCreate := []string{}
 		seen := map[string]struct{}{}
 		for _, p := range needed {
@@ -317,7 +332,7 @@ func runIndexPatterns(cmd *cobra.Command, args []string) error {
 		if len(toCreate) == 0 {
 			logger.Info("No new index patterns to create in single-tenant mode")
 		} else {
-			logger.Info(fmt.Sprintf("Will create index patterns: %s", strings.Join(toCreate, ", ")))
+			logger.WithField("patterns", strings.Join(toCreate, ", ")).Info("Will create index patterns")
 		}
 		for _, p := range toCreate {
 			payload := map[string]any{
@@ -329,15 +344,17 @@ func runIndexPatterns(cmd *cobra.Command, args []string) error {
 			}
 			id := fmt.Sprintf("index-pattern:%s", uuid.NewString())
 			if cfg.GetDryRun() {
-				logger.Info(fmt.Sprintf("DRY RUN: Would create index pattern %s", p))
+				logger.WithField("pattern", p).Info("DRY RUN: Would create index pattern")
 				createdPatterns = append(createdPatterns, p)
+				report.Skipped(p, "dry run")
 				continue
 			}
 			if err := osClient.CreateDoc(".kibana", id, payload); err != nil {
 				return err
 			}
-			logger.Info(fmt.Sprintf("Created index pattern %s", p))
+			logger.WithField("pattern", p).Info("Created index pattern")
 			createdPatterns = append(createdPatterns, p)
+			report.Succeeded(p)
 		}
 		if cfg.GetIndexPatternsRecovererEnabled() {
 			frDS, err := osClient.Search(".kibana", "q=type=data-source&size=1000")
@@ -352,7 +369,7 @@ func runIndexPatterns(cmd *cobra.Command, args []string) error {
 					}
 				}
 				if dsId != "" {
-					logger.Info(fmt.Sprintf("Found data-source reference id=%s for title=%s", dsId, config.GetConfig().GetDataSourceName()))
+					logger.WithFields(logging.Fields{"id": dsId, "title": config.GetConfig().GetDataSourceName()}).Info("Found data-source reference")
 					payload := map[string]any{
 						"type": "index-pattern",
 						"index-pattern": map[string]any{
@@ -368,9 +385,11 @@ func runIndexPatterns(cmd *cobra.Command, args []string) error {
 					if cfg.GetDryRun() {
 						logger.Info("DRY RUN: Would create index pattern extracted_* with data-source reference")
 						createdPatterns = append(createdPatterns, "extracted_*")
+						report.Skipped("extracted_*", "dry run")
 					} else if err := osClient.CreateDoc(".kibana", "index-pattern:recoverer-extracted", payload); err == nil {
 						logger.Info("Created index pattern extracted_* with data-source reference")
 						createdPatterns = append(createdPatterns, "extracted_*")
+						report.Succeeded("extracted_*")
 					}
 				}
 			}
@@ -385,7 +404,7 @@ func runIndexPatterns(cmd *cobra.Command, args []string) error {
 		if len(refreshedPatterns) > 0 {
 			logger.Info(fmt.Sprintf("Refreshed: %d index patterns", len(refreshedPatterns)))
 			for _, name := range refreshedPatterns {
-				logger.Info(fmt.Sprintf("  ✓ %s", name))
+				logger.WithField("pattern", name).Info("  ✓")
 			}
 		} else {
 			logger.Info("No index patterns were refreshed")
@@ -394,14 +413,14 @@ func runIndexPatterns(cmd *cobra.Command, args []string) error {
 		if len(failedRefreshedPatterns) > 0 {
 			logger.Info(fmt.Sprintf("Failed while refresh: %d index patterns", len(failedRefreshedPatterns)))
 			for _, name := range failedRefreshedPatterns {
-				logger.Info(fmt.Sprintf("  ✕ %s", name))
+				logger.WithField("pattern", name).Info("  ✕")
 			}
 		}
 
 		if len(createdPatterns) > 0 {
 			logger.Info(fmt.Sprintf("Created: %d index patterns", len(createdPatterns)))
 			for _, name := range createdPatterns {
-				logger.Info(fmt.Sprintf("  ✓ %s", name))
+				logger.WithField("pattern", name).Info("  ✓")
 			}
 		} else {
 			logger.Info("No index patterns were added")
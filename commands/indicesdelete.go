@@ -5,6 +5,7 @@ import (
 	"osctl/pkg/config"
 	"osctl/pkg/logging"
 	"osctl/pkg/opensearch"
+	"osctl/pkg/report"
 	"osctl/pkg/utils"
 	"strings"
 	"time"
@@ -36,20 +37,20 @@ func runIndicesDelete(cmd *cobra.Command, args []string) error {
 	s3Config := cfg.GetOsctlIndicesS3SnapshotsConfig()
 	checkSnapshots := cfg.GetIndicesDeleteCheckSnapshots()
 
-	logger.Info(fmt.Sprintf("Starting indices deletion indicesCount=%d unknownDays=%d checkSnapshots=%t", len(indicesConfig), unknownConfig.DaysCount, checkSnapshots))
+	logger.WithFields(logging.Fields{"indicesCount": len(indicesConfig), "unknownDays": unknownConfig.DaysCount, "checkSnapshots": checkSnapshots}).Info("Starting indices deletion")
 
 	client, err := utils.NewOSClientWithURL(cfg, cfg.GetOpenSearchURL())
 	if err != nil {
 		return fmt.Errorf("failed to create OpenSearch client: %v", err)
 	}
 
-	allIndices, err := client.GetIndicesWithFields("*", "index,cd", "index:asc")
+	allIndices, err := client.GetIndicesWithFields("*", "index,cd,ss", "index:asc")
 	if err != nil {
 		return fmt.Errorf("failed to get all indices: %v", err)
 	}
 	allIndexNames := utils.IndexInfosToNames(allIndices)
 	if len(allIndexNames) > 0 {
-		logger.Info(fmt.Sprintf("Found indices %s", strings.Join(allIndexNames, ", ")))
+		logger.WithField("indices", strings.Join(allIndexNames, ", ")).Info("Found indices")
 	} else {
 		logger.Info("Found indices none")
 	}
@@ -118,17 +119,17 @@ func runIndicesDelete(cmd *cobra.Command, args []string) error {
 	}
 
 	if len(indicesWithoutDateForLog) > 0 {
-		logger.Info(fmt.Sprintf("Indices skipped (no date in name) count=%d list=%s", len(indicesWithoutDateForLog), strings.Join(indicesWithoutDateForLog, ", ")))
+		logger.WithFields(logging.Fields{"count": len(indicesWithoutDateForLog), "list": strings.Join(indicesWithoutDateForLog, ", ")}).Info("Indices skipped (no date in name)")
 	}
 
 	if len(indicesOlderThanRetentionPeriod) > 0 {
-		logger.Info(fmt.Sprintf("Indices older than retention period (days_count) count=%d list=%s", len(indicesOlderThanRetentionPeriod), strings.Join(indicesOlderThanRetentionPeriod, ", ")))
+		logger.WithFields(logging.Fields{"count": len(indicesOlderThanRetentionPeriod), "list": strings.Join(indicesOlderThanRetentionPeriod, ", ")}).Info("Indices older than retention period (days_count)")
 	} else {
 		logger.Info("Indices older than retention period (days_count): none")
 	}
 
 	if len(indicesRequiringSnapshotCheck) > 0 {
-		logger.Info(fmt.Sprintf("Indices requiring snapshot check (older than days_count but not older than snapshot_count_s3) count=%d list=%s", len(indicesRequiringSnapshotCheck), strings.Join(indicesRequiringSnapshotCheck, ", ")))
+		logger.WithFields(logging.Fields{"count": len(indicesRequiringSnapshotCheck), "list": strings.Join(indicesRequiringSnapshotCheck, ", ")}).Info("Indices requiring snapshot check (older than days_count but not older than snapshot_count_s3)")
 	} else {
 		logger.Info("Indices requiring snapshot check: none")
 	}
@@ -144,7 +145,7 @@ func runIndicesDelete(cmd *cobra.Command, args []string) error {
 				return fmt.Errorf("snap-repo is required when indicesdelete-check-snapshots is true")
 			}
 
-			logger.Info(fmt.Sprintf("Getting all snapshots from repository repo=%s", snapRepo))
+			logger.WithField("repo", snapRepo).Info("Getting all snapshots from repository")
 			snapshots, err = utils.GetSnapshotsIgnore404(client, snapRepo, "*")
 			if err != nil {
 				return fmt.Errorf("failed to get snapshots: %v", err)
@@ -160,7 +161,7 @@ func runIndicesDelete(cmd *cobra.Command, args []string) error {
 				}
 			}
 			if len(snapshotNames) > 0 {
-				logger.Info(fmt.Sprintf("Found successful snapshots count=%d", len(snapshotNames)))
+				logger.WithField("count", len(snapshotNames)).Info("Found successful snapshots")
 			} else {
 				logger.Info("Found snapshots none")
 			}
@@ -168,11 +169,12 @@ func runIndicesDelete(cmd *cobra.Command, args []string) error {
 			for _, indexName := range indicesRequiringSnapshotCheck {
 				hasSnapshot := utils.HasValidSnapshot(indexName, snapshots)
 				if hasSnapshot {
-					logger.Info(fmt.Sprintf("Index has valid snapshot index=%s", indexName))
+					logger.WithField("index", indexName).Info("Index has valid snapshot")
 					indicesToDeleteFinal = append(indicesToDeleteFinal, indexName)
 				} else {
-					logger.Warn(fmt.Sprintf("Index has no valid snapshot, skipping deletion index=%s", indexName))
+					logger.WithField("index", indexName).Warn("Index has no valid snapshot, skipping deletion")
 					indicesWithoutSnapshot = append(indicesWithoutSnapshot, indexName)
+					report.Skipped(indexName, "no valid snapshot")
 				}
 			}
 
@@ -194,21 +196,26 @@ func runIndicesDelete(cmd *cobra.Command, args []string) error {
 	}
 
 	if len(indicesWithoutSnapshot) > 0 {
-		logger.Warn(fmt.Sprintf("Indices skipped (no valid snapshot) count=%d list=%s", len(indicesWithoutSnapshot), strings.Join(indicesWithoutSnapshot, ", ")))
+		logger.WithFields(logging.Fields{"count": len(indicesWithoutSnapshot), "list": strings.Join(indicesWithoutSnapshot, ", ")}).Warn("Indices skipped (no valid snapshot)")
 	}
 
 	var successfulDeletions []string
 	var failedDeletions []string
 
 	if len(indicesToDeleteFinal) > 0 {
-		logger.Info(fmt.Sprintf("Indices to delete (final list) count=%d list=%s", len(indicesToDeleteFinal), strings.Join(indicesToDeleteFinal, ", ")))
-		logger.Info(fmt.Sprintf("Deleting indices count=%d", len(indicesToDeleteFinal)))
-		successful, failed, err := utils.BatchDeleteIndices(client, indicesToDeleteFinal, cfg.GetDryRun(), logger)
+		logger.WithFields(logging.Fields{"count": len(indicesToDeleteFinal), "list": strings.Join(indicesToDeleteFinal, ", ")}).Info("Indices to delete (final list)")
+		logger.WithField("count", len(indicesToDeleteFinal)).Info("Deleting indices")
+		successful, failed, err := utils.BatchDeleteIndices(client.WithAuditReason("retention period expired"), indicesToDeleteFinal, cfg.GetDryRun(), logger)
 		if err != nil {
-			logger.Error(fmt.Sprintf("Failed to delete indices error=%v", err))
+			logger.WithField("error", err).Error("Failed to delete indices")
 		}
 		successfulDeletions = successful
 		failedDeletions = failed
+		sizes := make(map[string]string, len(allIndices))
+		for _, idx := range allIndices {
+			sizes[idx.Index] = idx.Size
+		}
+		utils.RecordDeletedIndices(successful, sizes)
 	} else {
 		logger.Info("No indices for deletion")
 	}
@@ -220,21 +227,21 @@ func runIndicesDelete(cmd *cobra.Command, args []string) error {
 		if len(successfulDeletions) > 0 {
 			logger.Info(fmt.Sprintf("Successfully deleted: %d indices", len(successfulDeletions)))
 			for _, name := range successfulDeletions {
-				logger.Info(fmt.Sprintf("  ✓ %s", name))
+				logger.WithField("index", name).Info("  ✓")
 			}
 		}
 		if len(failedDeletions) > 0 {
 			logger.Info("")
 			logger.Info(fmt.Sprintf("Failed to delete: %d indices", len(failedDeletions)))
 			for _, name := range failedDeletions {
-				logger.Info(fmt.Sprintf("  ✗ %s", name))
+				logger.WithField("index", name).Info("  ✗")
 			}
 		}
 		if len(indicesWithoutSnapshot) > 0 {
 			logger.Info("")
 			logger.Info(fmt.Sprintf("Skipped (no valid snapshot): %d indices", len(indicesWithoutSnapshot)))
 			for _, name := range indicesWithoutSnapshot {
-				logger.Info(fmt.Sprintf("  - %s", name))
+				logger.WithField("index", name).Info("  -")
 			}
 		}
 		if len(successfulDeletions) == 0 && len(failedDeletions) == 0 && len(indicesWithoutSnapshot) == 0 {
@@ -0,0 +1,270 @@
+package commands
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"osctl/pkg/config"
+	"osctl/pkg/logging"
+	"osctl/pkg/utils"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var inventoryCmd = &cobra.Command{
+	Use:   "inventory",
+	Short: "Export a per-prefix snapshot catalog",
+	Long: `Walk every configured snapshot repository (snap-repo and each repository from
+osctl-indices-config), read snapshot lists and _status sizes and build a per-prefix
+calendar: covered dates, gaps, snapshot states, total and incremental sizes.
+The report is written as a table, JSON, CSV or a static HTML page (--inventory-format).`,
+	RunE: runInventory,
+}
+
+func init() {
+	addFlags(inventoryCmd)
+}
+
+func runInventory(cmd *cobra.Command, args []string) error {
+	cfg := config.GetConfig()
+	logger := logging.NewLogger()
+	client, err := utils.NewOSClientWithURL(cfg, cfg.GetOpenSearchURL())
+	if err != nil {
+		return fmt.Errorf("failed to create OpenSearch client: %v", err)
+	}
+
+	indicesConfig, err := cfg.GetOsctlIndices()
+	if err != nil {
+		return fmt.Errorf("failed to get osctl indices: %v", err)
+	}
+
+	format := cfg.GetInventoryFormat()
+	dateFormat := cfg.GetDateFormat()
+	repos := utils.SnapshotAndCopyRepositoriesFor(cfg.GetSnapshotRepo(), indicesConfig)
+	logger.WithFields(logging.Fields{"repos": strings.Join(repos, ","), "format": format, "sizes": cfg.GetInventorySizes()}).Info("Starting inventory")
+
+	var report []utils.PrefixInventory
+	var trends inventoryTrends
+	sizes := make(map[string][2]int64)
+	for _, repo := range repos {
+		snapshots, err := client.GetSnapshotsDetailed(repo, "*")
+		if err != nil {
+			return fmt.Errorf("failed to get snapshots repo=%s: %v", repo, err)
+		}
+		logger.WithFields(logging.Fields{"repo": repo, "count": len(snapshots)}).Info("Snapshots in repo")
+
+		items := make([]utils.InventorySnapshot, 0, len(snapshots))
+		for _, s := range snapshots {
+			item := utils.InventorySnapshot{
+				Snapshot:        s.Snapshot,
+				UUID:            s.UUID,
+				Date:            utils.ExtractDateFromIndex(s.Snapshot, dateFormat),
+				State:           s.State,
+				Indices:         len(s.Indices),
+				StartTimeMillis: s.StartTimeInMillis,
+				DurationMillis:  s.DurationInMillis,
+			}
+			if cached, ok := sizes[s.UUID]; ok && s.UUID != "" {
+				item.TotalBytes, item.IncrementalBytes = cached[0], cached[1]
+			} else if cfg.GetInventorySizes() {
+				total, incremental, err := utils.SnapshotStatusSizes(client, repo, s.Snapshot)
+				if err != nil {
+					logger.WithFields(logging.Fields{"repo": repo, "snapshot": s.Snapshot, "error": err}).Warn("Failed to get snapshot sizes")
+				} else if s.UUID != "" {
+					sizes[s.UUID] = [2]int64{total, incremental}
+				}
+				item.TotalBytes = total
+				item.IncrementalBytes = incremental
+			}
+			items = append(items, item)
+		}
+		prefixes := utils.BuildPrefixInventory(repo, items, dateFormat)
+		for _, inv := range prefixes {
+			trends.Anomalies = append(trends.Anomalies, utils.DetectTrendAnomalies(inv, cfg.GetTrendBaselineDays(), cfg.GetTrendDurationFactor(), cfg.GetTrendRatioFactor())...)
+		}
+		trends.Forecasts = append(trends.Forecasts, utils.ForecastSnapshotWindow(repo, items, cfg.GetSnapshotWindow(), trendForecastHistoryDays, dateFormat))
+		report = append(report, prefixes...)
+	}
+	for _, a := range trends.Anomalies {
+		logger.Warn("Snapshot trend anomaly: " + a.String())
+	}
+	for _, f := range trends.Forecasts {
+		logger.Info("Snapshot window forecast: " + f.String())
+	}
+
+	out := io.Writer(os.Stdout)
+	if path := cfg.GetInventoryOutput(); path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed to create inventory output file: %v", err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	totals := utils.SumInventory(report)
+	switch format {
+	case "json":
+		err = writeInventoryJSON(out, report, totals, trends)
+	case "csv":
+		err = writeInventoryCSV(out, report, trends)
+	case "html":
+		err = writeInventoryHTML(out, report, totals, trends)
+	default:
+		err = writeInventoryTable(out, report, totals, trends)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write inventory: %v", err)
+	}
+	logger.WithFields(logging.Fields{"prefixes": len(report), "snapshots": totals.Snapshots, "total": utils.FormatSize(totals.TotalBytes), "output": cfg.GetInventoryOutput()}).Info("Inventory written")
+	return nil
+}
+
+type inventoryTrends struct {
+	Anomalies []utils.TrendAnomaly   `json:"anomalies"`
+	Forecasts []utils.WindowForecast `json:"forecasts"`
+}
+
+func inventoryStates(states map[string]int) string {
+	keys := make([]string, 0, len(states))
+	for k := range states {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%d", k, states[k]))
+	}
+	return strings.Join(parts, " ")
+}
+
+func writeInventoryTable(w io.Writer, report []utils.PrefixInventory, totals utils.InventoryTotals, trends inventoryTrends) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "REPOSITORY\tPREFIX\tFIRST\tLAST\tDAYS\tGAPS\tSTATES\tTOTAL\tINCREMENTAL\tLAST DURATION\tAVG MB/S")
+	for _, p := range report {
+		gaps := strconv.Itoa(len(p.Gaps))
+		if len(p.Gaps) > 0 && len(p.Gaps) <= 3 {
+			gaps += " (" + strings.Join(p.Gaps, ", ") + ")"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%s\t%s\t%s\t%s\t%s\t%.1f\n", p.Repo, p.Prefix, p.FirstDate, p.LastDate, len(p.CoveredDates), gaps, inventoryStates(p.States), utils.FormatSize(p.TotalBytes), utils.FormatSize(p.IncrementalBytes), inventoryDuration(p.LastDurationMillis), p.AvgMBps)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "")
+	fmt.Fprintf(w, "TOTAL %d unique snapshots: %s, incremental %s\n", totals.Snapshots, utils.FormatSize(totals.TotalBytes), utils.FormatSize(totals.IncrementalBytes))
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "WINDOW FORECAST")
+	for _, f := range trends.Forecasts {
+		fmt.Fprintln(w, "  "+f.String())
+	}
+	if len(trends.Anomalies) > 0 {
+		fmt.Fprintln(w, "")
+		fmt.Fprintln(w, "TREND ANOMALIES")
+		for _, a := range trends.Anomalies {
+			fmt.Fprintln(w, "  "+a.String())
+		}
+	}
+	return nil
+}
+
+func writeInventoryJSON(w io.Writer, report []utils.PrefixInventory, totals utils.InventoryTotals, trends inventoryTrends) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(map[string]any{
+		"generated_at": time.Now().UTC().Format(time.RFC3339),
+		"prefixes":     report,
+		"totals":       totals,
+		"trends":       trends,
+	})
+}
+
+func writeInventoryCSV(w io.Writer, report []utils.PrefixInventory, trends inventoryTrends) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"repository", "prefix", "snapshot", "date", "state", "indices", "total_bytes", "incremental_bytes", "duration_in_millis", "mb_per_sec"}); err != nil {
+		return err
+	}
+	for _, p := range report {
+		for _, s := range p.Snapshots {
+			if err := cw.Write([]string{p.Repo, p.Prefix, s.Snapshot, s.Date, s.State, strconv.Itoa(s.Indices), strconv.FormatInt(s.TotalBytes, 10), strconv.FormatInt(s.IncrementalBytes, 10), strconv.FormatInt(s.DurationMillis, 10), strconv.FormatFloat(s.MBps, 'f', 1, 64)}); err != nil {
+				return err
+			}
+		}
+		for _, gap := range p.Gaps {
+			if err := cw.Write([]string{p.Repo, p.Prefix, "", gap, "MISSING", "0", "0", "0", "0", "0"}); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+var inventoryHTMLTemplate = template.Must(template.New("inventory").Funcs(template.FuncMap{
+	"size":     utils.FormatSize,
+	"states":   inventoryStates,
+	"join":     strings.Join,
+	"duration": inventoryDuration,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Snapshot inventory</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+th { background: #f0f0f0; }
+.SUCCESS { color: #2e7d32; }
+.PARTIAL, .FAILED, .INCOMPATIBLE { color: #c62828; }
+.gap { color: #c62828; }
+</style>
+</head>
+<body>
+<h1>Snapshot inventory</h1>
+<p>Generated at {{.GeneratedAt}}</p>
+<table>
+<tr><th>Repository</th><th>Prefix</th><th>First</th><th>Last</th><th>Days</th><th>Gaps</th><th>States</th><th>Total</th><th>Incremental</th><th>Last duration</th><th>Avg MB/s</th></tr>
+{{range .Prefixes}}<tr><td>{{.Repo}}</td><td><a href="#{{.Repo}}-{{.Prefix}}">{{.Prefix}}</a></td><td>{{.FirstDate}}</td><td>{{.LastDate}}</td><td>{{len .CoveredDates}}</td><td class="gap">{{join .Gaps ", "}}</td><td>{{states .States}}</td><td>{{size .TotalBytes}}</td><td>{{size .IncrementalBytes}}</td><td>{{duration .LastDurationMillis}}</td><td>{{printf "%.1f" .AvgMBps}}</td></tr>
+{{end}}</table>
+<p>Total: {{.Totals.Snapshots}} unique snapshots, {{size .Totals.TotalBytes}}, incremental {{size .Totals.IncrementalBytes}}</p>
+<h2>Window forecast</h2>
+<ul>
+{{range .Trends.Forecasts}}<li>{{.}}</li>
+{{end}}</ul>
+{{if .Trends.Anomalies}}<h2>Trend anomalies</h2>
+<ul>
+{{range .Trends.Anomalies}}<li class="gap">{{.}}</li>
+{{end}}</ul>
+{{end}}{{range .Prefixes}}<h2 id="{{.Repo}}-{{.Prefix}}">{{.Repo}} / {{.Prefix}}</h2>
+<table>
+<tr><th>Date</th><th>Snapshot</th><th>State</th><th>Indices</th><th>Total</th><th>Incremental</th><th>Duration</th><th>MB/s</th></tr>
+{{range .Snapshots}}<tr><td>{{.Date}}</td><td>{{.Snapshot}}</td><td class="{{.State}}">{{.State}}</td><td>{{.Indices}}</td><td>{{size .TotalBytes}}</td><td>{{size .IncrementalBytes}}</td><td>{{duration .DurationMillis}}</td><td>{{printf "%.1f" .MBps}}</td></tr>
+{{end}}</table>
+{{end}}</body>
+</html>
+`))
+
+func writeInventoryHTML(w io.Writer, report []utils.PrefixInventory, totals utils.InventoryTotals, trends inventoryTrends) error {
+	return inventoryHTMLTemplate.Execute(w, map[string]any{
+		"GeneratedAt": time.Now().UTC().Format(time.RFC3339),
+		"Prefixes":    report,
+		"Totals":      totals,
+		"Trends":      trends,
+	})
+}
+
+func inventoryDuration(millis int64) string {
+	if millis <= 0 {
+		return "-"
+	}
+	return utils.FormatDuration(time.Duration(millis) * time.Millisecond)
+}
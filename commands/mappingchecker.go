@@ -0,0 +1,224 @@
+package commands
+
+import (
+	"fmt"
+	"osctl/pkg/alerts"
+	"osctl/pkg/config"
+	"osctl/pkg/logging"
+	"osctl/pkg/report"
+	"osctl/pkg/utils"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var mappingCheckerCmd = &cobra.Command{
+	Use:   "mappingchecker",
+	Short: "Monitor mapping field counts against total_fields.limit",
+	Long: `Read mappings of today's indices, count fields per index and compare them with the
+effective mapping.total_fields.limit. Sends an alert for prefixes that cross
+the configured share of the limit, lists the fastest-growing top-level fields and
+optionally raises the limit in the index template that manages the prefix.`,
+	RunE: runMappingChecker,
+}
+
+func init() {
+	addFlags(mappingCheckerCmd)
+}
+
+type mappingUsage struct {
+	prefix   string
+	index    string
+	fields   int
+	limit    int
+	percent  float64
+	topGrown []string
+	template string
+}
+
+func runMappingChecker(cmd *cobra.Command, args []string) error {
+	cfg := config.GetConfig()
+	logger := logging.NewLogger()
+	client, err := utils.NewOSClientWithURL(cfg, cfg.GetOpenSearchURL())
+	if err != nil {
+		return fmt.Errorf("failed to create OpenSearch client: %v", err)
+	}
+
+	threshold := cfg.GetMappingCheckerThreshold()
+	topN := cfg.GetMappingCheckerTopFields()
+	dateFormat := cfg.GetDateFormat()
+	today := utils.FormatDate(time.Now(), dateFormat)
+	yesterday := utils.GetYesterdayFormatted(dateFormat)
+	logger.WithFields(logging.Fields{"today": today, "threshold": fmt.Sprintf("%.1f%%", threshold), "topFields": topN, "raiseLimit": cfg.GetMappingCheckerRaiseLimit(), "dryRun": cfg.GetDryRun()}).Info("Starting mappingchecker")
+
+	todayPattern := fmt.Sprintf("*-%s*,-.*", today)
+	mappings, err := client.GetMappings(todayPattern)
+	if err != nil {
+		return fmt.Errorf("failed to get mappings for today's indices: %v", err)
+	}
+	limits, err := client.GetTotalFieldsLimits(todayPattern)
+	if err != nil {
+		return fmt.Errorf("failed to get total_fields.limit for today's indices: %v", err)
+	}
+
+	yesterdayTopLevel := make(map[string]map[string]int)
+	if prev, err := client.GetMappings(fmt.Sprintf("*-%s*,-.*", yesterday)); err != nil {
+		logger.WithField("error", err).Warn("Failed to get mappings for yesterday's indices, growth will be computed from zero")
+	} else {
+		for index, m := range prev {
+			if utils.ShouldSkipIndex(index) {
+				continue
+			}
+			_, topLevel := utils.CountMappingFields(m.Mappings)
+			prefix := utils.IndexPrefixForDate(index, yesterday)
+			if _, ok := yesterdayTopLevel[prefix]; !ok {
+				yesterdayTopLevel[prefix] = topLevel
+			}
+		}
+	}
+
+	usages := make(map[string]*mappingUsage)
+	todayTopLevel := make(map[string]map[string]int)
+	for index, m := range mappings {
+		if utils.ShouldSkipIndex(index) {
+			continue
+		}
+		limit := limits[index]
+		if limit <= 0 {
+			logger.WithField("index", index).Warn("Unknown total_fields.limit, skipping")
+			continue
+		}
+		fields, topLevel := utils.CountMappingFields(m.Mappings)
+		percent := float64(fields) * 100 / float64(limit)
+		prefix := utils.IndexPrefixForDate(index, today)
+		logger.WithFields(logging.Fields{"index": index, "fields": fields, "limit": limit, "usage": fmt.Sprintf("%.1f%%", percent)}).Info("Mapping usage")
+		if u, ok := usages[prefix]; !ok || percent > u.percent {
+			usages[prefix] = &mappingUsage{prefix: prefix, index: index, fields: fields, limit: limit, percent: percent}
+			todayTopLevel[prefix] = topLevel
+		}
+	}
+
+	var over []*mappingUsage
+	for prefix, u := range usages {
+		if u.percent < threshold {
+			continue
+		}
+		u.topGrown = topGrownFields(todayTopLevel[prefix], yesterdayTopLevel[prefix], topN)
+		over = append(over, u)
+	}
+	sort.Slice(over, func(i, j int) bool { return over[i].percent > over[j].percent })
+	if !cfg.GetMappingCheckerRaiseLimit() {
+		for _, u := range over {
+			report.Failed(u.prefix, fmt.Sprintf("index=%s fields=%d limit=%d usage=%.1f%%", u.index, u.fields, u.limit, u.percent))
+		}
+	}
+
+	var raised, raiseFailed []string
+	if cfg.GetMappingCheckerRaiseLimit() && len(over) > 0 {
+		candidates, err := utils.CollectTemplateCandidates(client)
+		if err != nil {
+			return err
+		}
+		step := cfg.GetMappingCheckerLimitStep()
+		for _, u := range over {
+			res := utils.ResolveTemplateForIndex(u.index, candidates)
+			if res.Winner == nil || res.Winner.Legacy {
+				logger.WithField("prefix", u.prefix).Warn("No composable template manages prefix, cannot raise limit")
+				raiseFailed = append(raiseFailed, u.prefix)
+				report.Failed(u.prefix, "no composable template manages the prefix")
+				continue
+			}
+			u.template = res.Winner.Name
+			newLimit := u.limit + step
+			if cfg.GetDryRun() {
+				logger.WithFields(logging.Fields{"template": u.template, "prefix": u.prefix, "from": u.limit, "to": newLimit}).Info("DRY RUN: Would raise mapping.total_fields.limit")
+				report.Skipped(u.prefix, "dry run")
+				continue
+			}
+			if err := utils.SetTemplateTotalFieldsLimit(client, u.template, newLimit); err != nil {
+				logger.WithFields(logging.Fields{"template": u.template, "prefix": u.prefix, "error": err}).Error("Failed to raise mapping.total_fields.limit")
+				raiseFailed = append(raiseFailed, u.prefix)
+				report.Failed(u.prefix, err)
+				continue
+			}
+			logger.WithFields(logging.Fields{"template": u.template, "prefix": u.prefix, "from": u.limit, "to": newLimit}).Info("Raised mapping.total_fields.limit")
+			raised = append(raised, fmt.Sprintf("%s (%s: %d→%d)", u.prefix, u.template, u.limit, newLimit))
+			report.Succeeded(u.prefix)
+		}
+	}
+
+	indices := make([]string, 0, len(over))
+	details := make([]alerts.MappingUsage, 0, len(over))
+	for _, u := range over {
+		indices = append(indices, u.index)
+		details = append(details, alerts.MappingUsage{Index: u.index, Fields: u.fields, Limit: u.limit, Percent: u.percent, Grown: u.topGrown})
+	}
+	notifier := utils.NewNotifier(cfg)
+	if !cfg.GetDryRun() {
+		utils.ResolveAlerts(notifier, alerts.MappingFieldsLimit, indices, logger)
+	}
+	if len(over) > 0 {
+		if cfg.GetDryRun() {
+			logger.WithField("count", len(over)).Info("DRY RUN: Would send alert for mapping fields limit")
+		} else if notifier != nil {
+			response, err := notifier.Notify(alerts.NewMappingFieldsLimitEvent(details))
+			if err != nil {
+				logger.WithField("error", err).Error("Failed to send alert")
+			} else {
+				logger.WithFields(logging.Fields{"type": "MappingFieldsLimit", "count": len(over), "notifiers": notifier.Name(), "response": response}).Info("Alert sent successfully")
+			}
+		} else {
+			logger.Warn("No alert notifier is configured (alert-notifiers) — alert skipped")
+		}
+	}
+
+	logger.Info(strings.Repeat("=", 60))
+	logger.Info("MAPPINGCHECKER SUMMARY")
+	logger.Info(strings.Repeat("=", 60))
+	logger.Info(fmt.Sprintf("Prefixes checked: %d", len(usages)))
+	if len(over) > 0 {
+		logger.Info(fmt.Sprintf("Prefixes over %.1f%% of the limit: %d", threshold, len(over)))
+		for _, u := range over {
+			logger.WithFields(logging.Fields{"index": u.index, "fields": u.fields, "limit": u.limit, "usage": fmt.Sprintf("%.1f%%", u.percent), "growing": strings.Join(u.topGrown, ", ")}).Info("  ✗ " + u.prefix)
+		}
+	} else {
+		logger.Info("  ✓ All prefixes are below the threshold")
+	}
+	for _, r := range raised {
+		logger.Info("  ✓ Limit raised: " + r)
+	}
+	for _, r := range raiseFailed {
+		logger.Info("  ✗ Limit not raised: " + r)
+	}
+	logger.Info(strings.Repeat("=", 60))
+	return nil
+}
+
+func topGrownFields(today, yesterday map[string]int, limit int) []string {
+	type growth struct {
+		field string
+		delta int
+	}
+	var grown []growth
+	for field, n := range today {
+		if delta := n - yesterday[field]; delta > 0 {
+			grown = append(grown, growth{field, delta})
+		}
+	}
+	sort.Slice(grown, func(i, j int) bool {
+		if grown[i].delta != grown[j].delta {
+			return grown[i].delta > grown[j].delta
+		}
+		return grown[i].field < grown[j].field
+	})
+	if len(grown) > limit {
+		grown = grown[:limit]
+	}
+	out := make([]string, 0, len(grown))
+	for _, g := range grown {
+		out = append(out, fmt.Sprintf("%s(+%d)", g.field, g.delta))
+	}
+	return out
+}
@@ -0,0 +1,169 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"osctl/pkg/audit"
+	"osctl/pkg/config"
+	"osctl/pkg/logging"
+	"osctl/pkg/metrics"
+	"osctl/pkg/report"
+	"osctl/pkg/utils"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+func instrumentActions(cmds []*cobra.Command) {
+	for _, cmd := range cmds {
+		action := cmd.Name()
+		run := cmd.RunE
+		if run == nil || config.ValidateAction(action) != nil {
+			continue
+		}
+		cmd.RunE = func(c *cobra.Command, args []string) error {
+			return runAction(action, func() error { return run(c, args) })
+		}
+	}
+}
+
+func runAction(action string, run func() error) error {
+	cfg := config.GetConfig()
+	logger := logging.NewLogger()
+	logging.SetContext("action", action)
+	utils.ConfigureAudit(cfg, logger)
+	if cfg.GetDaemonInterval() > 0 {
+		return runDaemon(cfg, action, run, logger)
+	}
+	err := runMeasured(action, run)
+	exportReport(cfg, logger)
+	exportMetrics(cfg, action, logger)
+	return err
+}
+
+func runMeasured(action string, run func() error) error {
+	runID := uuid.NewString()
+	logging.SetContext("runId", runID)
+	audit.SetRun(action, runID)
+	cfg := config.GetConfig()
+	rep := report.Start(action, runID)
+	rep.Version = appVersion
+	rep.Cluster = utils.ClusterName(cfg)
+	rep.DryRun = cfg.GetDryRun()
+	start := time.Now()
+	err := run()
+	report.Finish(err)
+	metrics.RunDuration.Set(time.Since(start).Seconds(), action)
+	metrics.LastRunTimestamp.SetToCurrentTime(action)
+	if err != nil {
+		metrics.RunsTotal.Inc(action, metrics.ResultFailure)
+		return err
+	}
+	metrics.RunsTotal.Inc(action, metrics.ResultSuccess)
+	metrics.LastSuccessTimestamp.SetToCurrentTime(action)
+	return nil
+}
+
+func runDaemon(cfg *config.Config, action string, run func() error, logger *logging.Logger) error {
+	if addr := cfg.GetMetricsListen(); addr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Default.Handler())
+		mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+		go func() {
+			if err := http.ListenAndServe(addr, mux); err != nil {
+				logger.WithFields(logging.Fields{"addr": addr, "error": err}).Error("Metrics server stopped")
+			}
+		}()
+		logger.WithFields(logging.Fields{"addr": addr, "path": "/metrics"}).Info("Serving metrics")
+	}
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	interval := cfg.GetDaemonInterval()
+	for {
+		if err := runMeasured(action, run); err != nil {
+			logger.WithField("error", err).Error("Action run failed")
+		}
+		exportReport(cfg, logger)
+		exportMetrics(cfg, action, logger)
+		logger.WithField("in", interval).Info("Next run")
+		select {
+		case <-time.After(interval):
+		case sig := <-stop:
+			logger.WithField("signal", sig).Info("Daemon stopped")
+			return nil
+		}
+	}
+}
+
+func exportMetrics(cfg *config.Config, action string, logger *logging.Logger) {
+	if dir := cfg.GetMetricsTextfileDir(); dir != "" {
+		name := "osctl_" + strings.ReplaceAll(action, "-", "_")
+		keepLastSuccess(filepath.Join(dir, name+".prom"), action)
+		if err := metrics.Default.WriteTextfile(dir, name); err != nil {
+			logger.WithFields(logging.Fields{"dir": dir, "error": err}).Error("Failed to write metrics textfile")
+		} else {
+			logger.WithField("file", filepath.Join(dir, name+".prom")).Info("Metrics written")
+		}
+	}
+	if gateway := cfg.GetMetricsPushgatewayURL(); gateway != "" {
+		if err := metrics.Default.Push(gateway, cfg.GetMetricsJob(), map[string]string{"action": action}); err != nil {
+			logger.WithFields(logging.Fields{"url": gateway, "error": err}).Error("Failed to push metrics")
+		} else {
+			logger.WithFields(logging.Fields{"url": gateway, "job": cfg.GetMetricsJob(), "action": action}).Info("Metrics pushed")
+		}
+	}
+}
+
+func exportReport(cfg *config.Config, logger *logging.Logger) {
+	rep := report.Current()
+	if path := cfg.GetReportFile(); path != "" {
+		if err := rep.Write(path); err != nil {
+			logger.WithField("error", err).Error("Failed to write run report")
+		} else if path != "-" {
+			logger.WithField("file", path).Info("Run report written")
+		}
+	}
+	if cfg.GetReportIndex() {
+		client, err := utils.NewOSClientWithURL(cfg, cfg.GetOpenSearchURL())
+		if err == nil {
+			err = client.CreateDoc(rep.IndexName(), rep.RunID, rep)
+		}
+		if err != nil {
+			logger.WithFields(logging.Fields{"index": rep.IndexName(), "error": err}).Error("Failed to index run report")
+		} else {
+			logger.WithField("index", rep.IndexName()).Info("Run report indexed")
+		}
+	}
+}
+
+func keepLastSuccess(path, action string) {
+	if metrics.LastSuccessTimestamp.Has(action) {
+		return
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	prefix := fmt.Sprintf("osctl_last_success_timestamp_seconds{action=%q} ", action)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if value, ok := strings.CutPrefix(scanner.Text(), prefix); ok {
+			if ts, err := strconv.ParseFloat(value, 64); err == nil {
+				metrics.LastSuccessTimestamp.Set(ts, action)
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package commands
+
+import (
+	"fmt"
+	"osctl/pkg/config"
+	"osctl/pkg/logging"
+	"osctl/pkg/opensearch"
+	"osctl/pkg/report"
+	"osctl/pkg/utils"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var repositoriesCmd = &cobra.Command{
+	Use:   "repositories",
+	Short: "Register, update and verify snapshot repositories",
+	Long: `Manage snapshot repositories declared in the repositories section of the config:
+register missing repositories, update repositories whose settings drifted from the
+config and run _snapshot/<repo>/_verify to report nodes that cannot reach them.
+When the section is empty all registered repositories are verified.`,
+	RunE: runRepositories,
+}
+
+func init() {
+	addFlags(repositoriesCmd)
+}
+
+func runRepositories(cmd *cobra.Command, args []string) error {
+	cfg := config.GetConfig()
+	logger := logging.NewLogger()
+	client, err := utils.NewOSClientWithURL(cfg, cfg.GetOpenSearchURL())
+	if err != nil {
+		return fmt.Errorf("failed to create OpenSearch client: %v", err)
+	}
+
+	desired := cfg.GetRepositories()
+	verifyOnly := cfg.GetRepositoriesVerifyOnly()
+	logger.WithFields(logging.Fields{"configured": len(desired), "verifyOnly": verifyOnly, "dryRun": cfg.GetDryRun()}).Info("Starting repositories")
+
+	registered, err := client.GetRepositories()
+	if err != nil {
+		return fmt.Errorf("failed to get snapshot repositories: %v", err)
+	}
+
+	var nodeNames []string
+	if nodes, err := client.GetAllocation(); err != nil {
+		logger.WithField("error", err).Warn("Failed to get cluster nodes, unreachable nodes will not be listed")
+	} else {
+		for _, n := range nodes {
+			if strings.ContainsAny(n.NodeRole, "dm") {
+				nodeNames = append(nodeNames, n.Name)
+			}
+		}
+	}
+
+	var toVerify []string
+	var created, updated, failed, skipped []string
+	for _, rc := range desired {
+		settings := rc.DesiredSettings()
+		current, exists := registered[rc.Name]
+		repo := opensearch.Repository{Type: rc.Type, Settings: make(map[string]any, len(settings))}
+		if exists && current.Type == rc.Type {
+			for k, v := range current.Settings {
+				repo.Settings[k] = v
+			}
+		}
+		for k, v := range settings {
+			repo.Settings[k] = v
+		}
+		action := ""
+		var diffs []string
+		switch {
+		case !exists:
+			action = "register"
+		case current.Type != rc.Type:
+			action = "update"
+			diffs = append(diffs, fmt.Sprintf("type: %s → %s", current.Type, rc.Type))
+			diffs = append(diffs, utils.RepositorySettingsDrift(settings, current)...)
+		default:
+			diffs = utils.RepositorySettingsDrift(settings, current)
+			if len(diffs) > 0 {
+				action = "update"
+			}
+		}
+
+		if action == "" {
+			logger.WithFields(logging.Fields{"repo": rc.Name, "type": rc.Type}).Info("Repository is up to date")
+			toVerify = append(toVerify, rc.Name)
+			continue
+		}
+		if len(diffs) > 0 {
+			logger.WithFields(logging.Fields{"repo": rc.Name, "diff": strings.Join(diffs, "; ")}).Info("Repository settings drifted")
+		}
+		if verifyOnly {
+			logger.WithFields(logging.Fields{"repo": rc.Name, "action": action}).Warn("Repository needs changes but verify-only mode is enabled")
+			skipped = append(skipped, fmt.Sprintf("%s: needs %s", rc.Name, action))
+			report.Skipped(rc.Name, "needs "+action)
+			if exists {
+				toVerify = append(toVerify, rc.Name)
+			}
+			continue
+		}
+		if cfg.GetDryRun() {
+			logger.WithFields(logging.Fields{"repo": rc.Name, "type": rc.Type, "action": action}).Info("DRY RUN: Would change repository")
+			skipped = append(skipped, fmt.Sprintf("%s: would %s", rc.Name, action))
+			report.Skipped(rc.Name, "dry run")
+			if exists {
+				toVerify = append(toVerify, rc.Name)
+			}
+			continue
+		}
+		if err := client.PutRepository(rc.Name, repo); err != nil {
+			logger.WithFields(logging.Fields{"repo": rc.Name, "action": action, "error": err}).Error("Failed to change repository")
+			failed = append(failed, fmt.Sprintf("%s: %s failed: %v", rc.Name, action, err))
+			report.Failed(rc.Name, fmt.Sprintf("%s failed: %v", action, err))
+			continue
+		}
+		if action == "register" {
+			logger.WithFields(logging.Fields{"repo": rc.Name, "type": rc.Type}).Info("Repository registered")
+			created = append(created, rc.Name)
+		} else {
+			logger.WithFields(logging.Fields{"repo": rc.Name, "type": rc.Type}).Info("Repository updated")
+			updated = append(updated, fmt.Sprintf("%s (%s)", rc.Name, strings.Join(diffs, "; ")))
+		}
+		toVerify = append(toVerify, rc.Name)
+	}
+
+	if len(desired) == 0 {
+		for name := range registered {
+			toVerify = append(toVerify, name)
+		}
+		sort.Strings(toVerify)
+	}
+
+	var verified, unreachable []string
+	for _, name := range toVerify {
+		nodes, err := client.VerifyRepository(name)
+		if err != nil {
+			logger.WithFields(logging.Fields{"repo": name, "error": err}).Error("Repository verification failed")
+			unreachable = append(unreachable, fmt.Sprintf("%s: %v", name, err))
+			report.Failed(name, err)
+			continue
+		}
+		missing := missingNodes(nodeNames, nodes)
+		if len(missing) > 0 {
+			logger.WithFields(logging.Fields{"repo": name, "nodes": strings.Join(missing, ",")}).Error("Repository is not reachable from all data nodes")
+			unreachable = append(unreachable, fmt.Sprintf("%s: unreachable from %s", name, strings.Join(missing, ", ")))
+			report.Failed(name, "unreachable from "+strings.Join(missing, ", "))
+			continue
+		}
+		logger.WithFields(logging.Fields{"repo": name, "nodes": len(nodes)}).Info("Repository verified")
+		verified = append(verified, name)
+		report.Succeeded(name)
+	}
+
+	logger.Info(strings.Repeat("=", 60))
+	logger.Info("REPOSITORIES SUMMARY")
+	logger.Info(strings.Repeat("=", 60))
+	for _, r := range created {
+		logger.Info("  ✓ Registered: " + r)
+	}
+	for _, r := range updated {
+		logger.Info("  ✓ Updated: " + r)
+	}
+	for _, r := range verified {
+		logger.Info("  ✓ Verified: " + r)
+	}
+	for _, r := range skipped {
+		logger.Info("  - " + r)
+	}
+	for _, r := range failed {
+		logger.Info("  ✗ " + r)
+	}
+	for _, r := range unreachable {
+		logger.Info("  ✗ " + r)
+	}
+	logger.Info(strings.Repeat("=", 60))
+
+	if len(failed) > 0 || len(unreachable) > 0 {
+		return fmt.Errorf("repositories check failed: %d failed, %d not verified", len(failed), len(unreachable))
+	}
+	return nil
+}
+
+func missingNodes(all, verified []string) []string {
+	ok := make(map[string]bool, len(verified))
+	for _, n := range verified {
+		ok[n] = true
+	}
+	var missing []string
+	for _, n := range all {
+		if !ok[n] {
+			missing = append(missing, n)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
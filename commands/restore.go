@@ -20,9 +20,12 @@ var restoreCmd = &cobra.Command{
 	Short: "Restore indices from today's snapshots",
 	Long: `Restore indices from today's snapshots in the configured repository.
 Snapshots are processed largest-first, in parallel (max_concurrent_snapshots workers).
-Only indices matching --index-filter are restored (the rest of a snapshot is ignored).
+Only indices matching --index-filter or --prefix are restored (the rest of a snapshot is ignored).
+A date range can be given with --date-from/--date-to. With --restore-target=recoverer
+indices are restored into opensearch_recoverer_url under --restore-rename-template
+(extracted_<index>_<restore-date> by default), to be cleaned up later by extracteddelete.
 SUCCESS snapshots are restored immediately; IN_PROGRESS ones are waited for and restored
-once they become SUCCESS; FAILED ones and failed restores raise Madison alerts but do not
+once they become SUCCESS; FAILED ones and failed restores raise alerts but do not
 abort the job.`,
 	RunE: runRestore,
 }
@@ -43,23 +46,35 @@ func runRestore(cmd *cobra.Command, args []string) error {
 	dateFormat := cfg.GetDateFormat()
 	today := utils.FormatDate(time.Now(), dateFormat)
 	filter := cfg.GetRestoreIndexFilter()
+	for _, prefix := range cfg.GetRestorePrefixes() {
+		filter = append(filter, prefix+"-*")
+	}
 	namespace := cfg.GetKubeNamespace()
+	target := cfg.GetRestoreTarget()
+	renameTemplate := cfg.GetRestoreRenameTemplate()
+	restoreDate := utils.FormatDate(time.Now(), cfg.GetRecovererDateFormat())
+	slotFilter := filter
+	if renameTemplate != "" {
+		slotFilter = []string{utils.RenameTemplateGlob(renameTemplate)}
+	}
 
-	logger.Info(fmt.Sprintf("Starting restore repo=%s today=%s daysCount=%d date=%q maxConcurrent=%d filters=%d dryRun=%t", repo, today, cfg.GetRestoreDaysCount(), cfg.GetRestoreDate(), maxConcurrent, len(filter), cfg.GetDryRun()))
+	logger.WithFields(logging.Fields{"repo": repo, "target": target, "today": today, "daysCount": cfg.GetRestoreDaysCount(), "date": cfg.GetRestoreDate(), "dateFrom": cfg.GetRestoreDateFrom(), "dateTo": cfg.GetRestoreDateTo(), "maxConcurrent": maxConcurrent, "filters": len(filter), "renameTemplate": renameTemplate, "dryRun": cfg.GetDryRun()}).Info("Starting restore")
 	if len(filter) > 0 {
 		logger.Info("Index filter patterns: " + strings.Join(filter, ", "))
 	} else {
 		logger.Info("Index filter patterns: none (all indices in the snapshots will be restored)")
 	}
 
-	var madisonClient *alerts.Client
-	if cfg.GetMadisonKey() != "" && cfg.GetOSDURL() != "" && cfg.GetMadisonURL() != "" {
-		madisonClient = alerts.NewMadisonClient(cfg.GetMadisonKey(), cfg.GetOSDURL(), cfg.GetMadisonURL())
-	} else {
-		logger.Warn("Madison is not fully configured (madison-key/osd-url/madison-url) — alerts will be skipped")
+	notifier := utils.NewNotifier(cfg)
+	if notifier == nil {
+		logger.Warn("No alert notifier is configured (alert-notifiers) — alerts will be skipped")
 	}
 
-	client, err := utils.NewOSClientWithURL(cfg, cfg.GetOpenSearchURL())
+	clusterURL := cfg.GetOpenSearchURL()
+	if target == "recoverer" {
+		clusterURL = cfg.GetOpenSearchRecovererURL()
+	}
+	client, err := utils.NewOSClientWithURL(cfg, clusterURL)
 	if err != nil {
 		return fmt.Errorf("failed to create OpenSearch client: %v", err)
 	}
@@ -68,47 +83,51 @@ func runRestore(cmd *cobra.Command, args []string) error {
 
 	activeIdx, aerr := client.ActiveSnapshotRecoveryIndices()
 	if aerr != nil {
-		logger.Warn(fmt.Sprintf("Preflight: failed to list active restores error=%v", aerr))
+		logger.WithField("error", aerr).Warn("Preflight: failed to list active restores")
 	}
 	failedIdx, ferr := client.RestoreFailedPrimaryIndices()
 	if ferr != nil {
-		logger.Warn(fmt.Sprintf("Preflight: failed to list failed restores error=%v", ferr))
+		logger.WithField("error", ferr).Warn("Preflight: failed to list failed restores")
 	}
-	ourActive := utils.FilterIndices(activeIdx, filter)
-	ourFailed := utils.FilterIndices(failedIdx, filter)
-	foreign := foreignRestores(activeIdx, failedIdx, filter)
-	logger.Info(fmt.Sprintf("Preflight restores: ourActive=%d ourFailed=%d foreign=%d", len(ourActive), len(ourFailed), len(foreign)))
+	ourActive := utils.FilterIndices(activeIdx, slotFilter)
+	ourFailed := utils.FilterIndices(failedIdx, slotFilter)
+	foreign := foreignRestores(activeIdx, failedIdx, slotFilter)
+	logger.WithFields(logging.Fields{"ourActive": len(ourActive), "ourFailed": len(ourFailed), "foreign": len(foreign)}).Info("Preflight restores")
 	if len(ourActive) > 0 {
 		logger.Info("Our restores in progress: " + strings.Join(ourActive, ", "))
 	}
 
 	if !cfg.GetDryRun() {
 		if len(foreign) >= maxConcurrent {
-			logger.Error(fmt.Sprintf("Foreign restores at/over concurrency, aborting count=%d max=%d list=%s", len(foreign), maxConcurrent, strings.Join(foreign, ", ")))
-			if madisonClient != nil {
-				if _, e := madisonClient.SendMadisonForeignRestoreAlert(foreign, namespace, today); e != nil {
-					logger.Error(fmt.Sprintf("Failed to send Madison foreign-restore alert error=%v", e))
+			logger.WithFields(logging.Fields{"count": len(foreign), "max": maxConcurrent, "list": strings.Join(foreign, ", ")}).Error("Foreign restores at/over concurrency, aborting")
+			if notifier != nil {
+				if _, e := notifier.Notify(alerts.NewRestoreForeignEvent(foreign, namespace, today)); e != nil {
+					logger.WithField("error", e).Error("Failed to send foreign-restore alert")
 				} else {
-					logger.Info("Madison alert sent: type=SnapshotRestoreForeign")
+					logger.WithField("type", "SnapshotRestoreForeign").Info("Alert sent")
 				}
 			}
 			return fmt.Errorf("aborting: %d foreign restores in progress (>= max %d)", len(foreign), maxConcurrent)
 		} else if len(foreign) > 0 {
-			logger.Warn(fmt.Sprintf("Foreign restores in progress (not in filter, continuing): %s", strings.Join(foreign, ", ")))
+			logger.WithField("indices", strings.Join(foreign, ", ")).Warn("Foreign restores in progress (not in filter, continuing)")
 		}
 
 		slots := maxConcurrent - len(ourActive)
+		if renameTemplate != "" && len(ourFailed) > 0 {
+			logger.Info(fmt.Sprintf("Renamed restores cannot be repaired from restore source; %d failed indices will be deleted and restored again when their snapshot is processed", len(ourFailed)))
+			ourFailed = nil
+		}
 		for _, idx := range ourFailed {
 			if slots <= 0 {
 				logger.Info("No restore slot free for more repairs this run; remaining failed restores will be handled on a later run")
 				break
 			}
-			if rerr := utils.RepairFailedRestore(client, idx, filter, maxConcurrent, restorePendingPollInterval, logger); rerr != nil {
+			if rerr := utils.RepairFailedRestore(client, idx, slotFilter, maxConcurrent, restorePendingPollInterval, logger); rerr != nil {
 				problems = true
-				logger.Error(fmt.Sprintf("Failed to repair failed restore index=%s error=%v", idx, rerr))
-				if madisonClient != nil {
-					if _, e := madisonClient.SendMadisonRestoreFailedAlert("(repair)", idx, repo, namespace, today); e != nil {
-						logger.Error(fmt.Sprintf("Failed to send Madison restore-failed alert error=%v", e))
+				logger.WithFields(logging.Fields{"index": idx, "error": rerr}).Error("Failed to repair failed restore")
+				if notifier != nil {
+					if _, e := notifier.Notify(alerts.NewRestoreFailedEvent("(repair)", idx, repo, namespace, today)); e != nil {
+						logger.WithField("error", e).Error("Failed to send restore-failed alert")
 					}
 				}
 				continue
@@ -120,9 +139,10 @@ func runRestore(cmd *cobra.Command, args []string) error {
 	dates := restoreDates(cfg)
 	logger.Info("Restore target dates: " + strings.Join(dates, ", "))
 
+	limiter := utils.NewConcurrencyLimiter(cfg, client, "restore", maxConcurrent, logger)
 	var successful, failed []string
 	for _, date := range dates {
-		succ, fail, prob := restoreForDate(client, repo, date, filter, maxConcurrent, madisonClient, namespace, cfg.GetDryRun(), logger)
+		succ, fail, prob := restoreForDate(client, repo, date, filter, slotFilter, renameTemplate, restoreDate, maxConcurrent, limiter, notifier, namespace, cfg.GetDryRun(), logger)
 		successful = append(successful, succ...)
 		failed = append(failed, fail...)
 		if prob {
@@ -140,14 +160,14 @@ func runRestore(cmd *cobra.Command, args []string) error {
 	if len(successful) > 0 {
 		logger.Info(fmt.Sprintf("Successfully restored: %d snapshots", len(successful)))
 		for _, name := range successful {
-			logger.Info(fmt.Sprintf("  ✓ %s", name))
+			logger.WithField("snapshot", name).Info("  ✓")
 		}
 	}
 	if len(failed) > 0 {
 		logger.Info("")
 		logger.Info(fmt.Sprintf("Restored with errors: %d snapshots", len(failed)))
 		for _, name := range failed {
-			logger.Info(fmt.Sprintf("  ✗ %s", name))
+			logger.WithField("snapshot", name).Info("  ✗")
 		}
 	}
 	logger.Info(strings.Repeat("=", 60))
@@ -161,6 +181,19 @@ func runRestore(cmd *cobra.Command, args []string) error {
 }
 
 func restoreDates(cfg *config.Config) []string {
+	df := cfg.GetDateFormat()
+	if from, to := cfg.GetRestoreDateFrom(), cfg.GetRestoreDateTo(); from != "" && to != "" {
+		goFormat := utils.ConvertDateFormat(df)
+		start, errFrom := time.Parse(goFormat, from)
+		end, errTo := time.Parse(goFormat, to)
+		if errFrom == nil && errTo == nil {
+			var out []string
+			for d := end; !d.Before(start); d = d.AddDate(0, 0, -1) {
+				out = append(out, d.Format(goFormat))
+			}
+			return out
+		}
+	}
 	if d := cfg.GetRestoreDate(); d != "" {
 		return []string{d}
 	}
@@ -168,7 +201,6 @@ func restoreDates(cfg *config.Config) []string {
 	if n < 1 {
 		n = 1
 	}
-	df := cfg.GetDateFormat()
 	now := time.Now()
 	out := make([]string, 0, n)
 	for i := 0; i < n; i++ {
@@ -177,21 +209,21 @@ func restoreDates(cfg *config.Config) []string {
 	return out
 }
 
-func restoreForDate(client *opensearch.Client, repo, date string, filter []string, maxConcurrent int, madisonClient *alerts.Client, namespace string, dryRun bool, logger *logging.Logger) ([]string, []string, bool) {
+func restoreForDate(client *opensearch.Client, repo, date string, filter, slotFilter []string, renameTemplate, restoreDate string, maxConcurrent int, limiter *utils.ConcurrencyLimiter, notifier alerts.Notifier, namespace string, dryRun bool, logger *logging.Logger) ([]string, []string, bool) {
 	problems := false
 	pattern := "*" + date + "*"
-	logger.Info(fmt.Sprintf("Listing snapshots for date=%s via filter pattern=%s", date, pattern))
+	logger.WithFields(logging.Fields{"date": date, "pattern": pattern}).Info("Listing snapshots for date via filter pattern")
 	snapshots, err := client.GetSnapshotsDetailed(repo, pattern)
 	if err != nil {
 		if strings.Contains(err.Error(), "snapshot_missing_exception") || strings.Contains(err.Error(), "404") {
-			logger.Info(fmt.Sprintf("No snapshots found for date=%s", date))
+			logger.WithField("date", date).Info("No snapshots found for date")
 			return nil, nil, false
 		}
-		logger.Error(fmt.Sprintf("Failed to list snapshots for date=%s error=%v", date, err))
+		logger.WithFields(logging.Fields{"date": date, "error": err}).Error("Failed to list snapshots for date")
 		return nil, nil, true
 	}
 	if len(snapshots) == 0 {
-		logger.Info(fmt.Sprintf("No snapshots found for date=%s", date))
+		logger.WithField("date", date).Info("No snapshots found for date")
 		return nil, nil, false
 	}
 
@@ -200,46 +232,50 @@ func restoreForDate(client *opensearch.Client, repo, date string, filter []strin
 	for _, s := range snapshots {
 		matched := utils.FilterIndices(s.Indices, filter)
 		if len(matched) == 0 {
-			logger.Info(fmt.Sprintf("Snapshot has no indices matching filter, skipping snapshot=%s state=%s indicesInSnapshot=%d", s.Snapshot, s.State, len(s.Indices)))
+			logger.WithFields(logging.Fields{"snapshot": s.Snapshot, "state": s.State, "indicesInSnapshot": len(s.Indices)}).Info("Snapshot has no indices matching filter, skipping")
 			continue
 		}
 		switch s.State {
 		case "SUCCESS":
-			readyTasks = append(readyTasks, buildRestoreTask(client, repo, s.Snapshot, matched, logger))
+			readyTasks = append(readyTasks, buildRestoreTask(client, repo, s.Snapshot, matched, renameTemplate, restoreDate, logger))
 		case "IN_PROGRESS", "STARTED":
-			logger.Info(fmt.Sprintf("Snapshot IN_PROGRESS, deferred to end of queue snapshot=%s matchedIndices=%d", s.Snapshot, len(matched)))
+			logger.WithFields(logging.Fields{"snapshot": s.Snapshot, "matchedIndices": len(matched)}).Info("Snapshot IN_PROGRESS, deferred to end of queue")
 			pending = append(pending, s.Snapshot)
 		default:
 			problems = true
-			logger.Error(fmt.Sprintf("Snapshot in non-restorable state, alerting snapshot=%s state=%s", s.Snapshot, s.State))
-			sendSnapshotStateFailed(madisonClient, s.Snapshot, s.State, repo, namespace, date, logger)
+			logger.WithFields(logging.Fields{"snapshot": s.Snapshot, "state": s.State}).Error("Snapshot in non-restorable state, alerting")
+			sendSnapshotStateFailed(notifier, s.Snapshot, s.State, repo, namespace, date, logger)
 		}
 	}
 
 	if dryRun {
 		sorted := utils.SortRestoreTasksBySizeDesc(readyTasks)
-		logger.Info(fmt.Sprintf("DRY RUN date=%s: restore plan (largest first)", date))
+		logger.WithField("date", date).Info("DRY RUN: restore plan (largest first)")
 		for i, t := range sorted {
-			logger.Info(fmt.Sprintf("Restore %d: snapshot=%s matchedIndices=%d indices=%s", i+1, t.SnapshotName, len(t.Indices), strings.Join(t.Indices, ",")))
+			targets := make([]string, 0, len(t.Indices))
+			for _, idx := range t.Indices {
+				targets = append(targets, t.TargetIndex(idx))
+			}
+			logger.WithFields(logging.Fields{"snapshot": t.SnapshotName, "matchedIndices": len(t.Indices), "indices": strings.Join(targets, ",")}).Info(fmt.Sprintf("Restore %d", i+1))
 		}
 		if len(pending) > 0 {
-			logger.Info(fmt.Sprintf("Would wait for IN_PROGRESS snapshots: %s", strings.Join(pending, ", ")))
+			logger.WithField("snapshots", strings.Join(pending, ", ")).Info("Would wait for IN_PROGRESS snapshots")
 		}
-		logger.Info(fmt.Sprintf("DRY RUN date=%s: would restore %d snapshots (+%d pending)", date, len(sorted), len(pending)))
+		logger.WithFields(logging.Fields{"date": date, "snapshots": len(sorted), "pending": len(pending)}).Info("DRY RUN: would restore snapshots")
 		return nil, nil, problems
 	}
 
 	var successful, failed []string
 	if len(readyTasks) > 0 {
-		succ, fail := utils.RestoreSnapshotsInParallel(client, readyTasks, maxConcurrent, madisonClient, namespace, date, filter, logger)
+		succ, fail := utils.RestoreSnapshotsInParallel(client, readyTasks, maxConcurrent, notifier, namespace, date, slotFilter, logger, limiter)
 		successful = append(successful, succ...)
 		failed = append(failed, fail...)
 	} else {
-		logger.Info(fmt.Sprintf("No SUCCESS snapshots ready to restore for date=%s", date))
+		logger.WithField("date", date).Info("No SUCCESS snapshots ready to restore for date")
 	}
 
 	for len(pending) > 0 {
-		logger.Info(fmt.Sprintf("Waiting for %d IN_PROGRESS snapshots (date=%s) before rechecking: %s", len(pending), date, strings.Join(pending, ", ")))
+		logger.WithFields(logging.Fields{"date": date, "count": len(pending), "snapshots": strings.Join(pending, ", ")}).Info("Waiting for IN_PROGRESS snapshots before rechecking")
 		time.Sleep(restorePendingPollInterval)
 
 		var stillPending []string
@@ -247,12 +283,12 @@ func restoreForDate(client *opensearch.Client, repo, date string, filter []strin
 		for _, name := range pending {
 			snaps, err := client.GetSnapshotsDetailed(repo, name)
 			if err != nil {
-				logger.Warn(fmt.Sprintf("Failed to recheck pending snapshot, keeping in queue snapshot=%s error=%v", name, err))
+				logger.WithFields(logging.Fields{"snapshot": name, "error": err}).Warn("Failed to recheck pending snapshot, keeping in queue")
 				stillPending = append(stillPending, name)
 				continue
 			}
 			if len(snaps) == 0 {
-				logger.Warn(fmt.Sprintf("Pending snapshot disappeared from repo snapshot=%s", name))
+				logger.WithField("snapshot", name).Warn("Pending snapshot disappeared from repo")
 				problems = true
 				continue
 			}
@@ -261,23 +297,23 @@ func restoreForDate(client *opensearch.Client, repo, date string, filter []strin
 			case "SUCCESS":
 				matched := utils.FilterIndices(s.Indices, filter)
 				if len(matched) == 0 {
-					logger.Info(fmt.Sprintf("Pending snapshot became SUCCESS but no matching indices snapshot=%s", name))
+					logger.WithField("snapshot", name).Info("Pending snapshot became SUCCESS but no matching indices")
 					continue
 				}
-				logger.Info(fmt.Sprintf("Pending snapshot became SUCCESS, will restore snapshot=%s matchedIndices=%d", name, len(matched)))
-				nowReady = append(nowReady, buildRestoreTask(client, repo, name, matched, logger))
+				logger.WithFields(logging.Fields{"snapshot": name, "matchedIndices": len(matched)}).Info("Pending snapshot became SUCCESS, will restore")
+				nowReady = append(nowReady, buildRestoreTask(client, repo, name, matched, renameTemplate, restoreDate, logger))
 			case "IN_PROGRESS", "STARTED":
 				stillPending = append(stillPending, name)
 			default:
 				problems = true
-				logger.Error(fmt.Sprintf("Pending snapshot ended in non-restorable state, alerting snapshot=%s state=%s", name, s.State))
-				sendSnapshotStateFailed(madisonClient, name, s.State, repo, namespace, date, logger)
+				logger.WithFields(logging.Fields{"snapshot": name, "state": s.State}).Error("Pending snapshot ended in non-restorable state, alerting")
+				sendSnapshotStateFailed(notifier, name, s.State, repo, namespace, date, logger)
 			}
 		}
 		pending = stillPending
 
 		if len(nowReady) > 0 {
-			succ, fail := utils.RestoreSnapshotsInParallel(client, nowReady, maxConcurrent, madisonClient, namespace, date, filter, logger)
+			succ, fail := utils.RestoreSnapshotsInParallel(client, nowReady, maxConcurrent, notifier, namespace, date, slotFilter, logger, limiter)
 			successful = append(successful, succ...)
 			failed = append(failed, fail...)
 		}
@@ -299,27 +335,29 @@ func foreignRestores(active, failed, filter []string) []string {
 	return out
 }
 
-func buildRestoreTask(client *opensearch.Client, repo, snapshot string, indices []string, logger *logging.Logger) utils.RestoreTask {
+func buildRestoreTask(client *opensearch.Client, repo, snapshot string, indices []string, renameTemplate, restoreDate string, logger *logging.Logger) utils.RestoreTask {
 	size, err := utils.GetSnapshotSize(client, repo, snapshot)
 	if err != nil {
-		logger.Warn(fmt.Sprintf("Failed to get snapshot size, using 0 for ordering snapshot=%s error=%v", snapshot, err))
+		logger.WithFields(logging.Fields{"snapshot": snapshot, "error": err}).Warn("Failed to get snapshot size, using 0 for ordering")
 	}
 	return utils.RestoreTask{
-		SnapshotName: snapshot,
-		Indices:      indices,
-		Repo:         repo,
-		Size:         size,
-		PollInterval: 30 * time.Second,
+		SnapshotName:   snapshot,
+		Indices:        indices,
+		Repo:           repo,
+		Size:           size,
+		PollInterval:   30 * time.Second,
+		RenameTemplate: renameTemplate,
+		RestoreDate:    restoreDate,
 	}
 }
 
-func sendSnapshotStateFailed(madisonClient *alerts.Client, snapshot, state, repo, namespace, dateStr string, logger *logging.Logger) {
-	if madisonClient == nil {
+func sendSnapshotStateFailed(notifier alerts.Notifier, snapshot, state, repo, namespace, dateStr string, logger *logging.Logger) {
+	if notifier == nil {
 		return
 	}
-	if _, err := madisonClient.SendMadisonSnapshotStateFailedAlert(snapshot, state, repo, namespace, dateStr); err != nil {
-		logger.Error(fmt.Sprintf("Failed to send Madison snapshot-state alert snapshot=%s error=%v", snapshot, err))
+	if _, err := notifier.Notify(alerts.NewSnapshotStateFailedEvent(snapshot, state, repo, namespace, dateStr)); err != nil {
+		logger.WithFields(logging.Fields{"snapshot": snapshot, "error": err}).Error("Failed to send snapshot-state alert")
 	} else {
-		logger.Info(fmt.Sprintf("Madison alert sent: type=SnapshotStateFailed snapshot=%s state=%s", snapshot, state))
+		logger.WithFields(logging.Fields{"type": "SnapshotStateFailed", "snapshot": snapshot, "state": state}).Info("Alert sent")
 	}
 }
@@ -4,8 +4,11 @@ import (
 	"fmt"
 	"osctl/pkg/config"
 	"osctl/pkg/logging"
+	"osctl/pkg/metrics"
 	"osctl/pkg/opensearch"
+	"osctl/pkg/report"
 	"osctl/pkg/utils"
+	"strconv"
 	"strings"
 	"time"
 
@@ -44,7 +47,7 @@ func runRetention(cmd *cobra.Command, args []string) error {
 	}
 
 	logger := logging.NewLogger()
-	logger.Info(fmt.Sprintf("Starting retention process threshold=%.2f retentionDaysCount=%d checkSnapshots=%t checkNodesDown=%t snapRepo=%s dryRun=%t", threshold, retentionDaysCount, checkSnapshots, checkNodesDown, snapRepo, cfg.GetDryRun()))
+	logger.WithFields(logging.Fields{"threshold": fmt.Sprintf("%.2f", threshold), "retentionDaysCount": retentionDaysCount, "checkSnapshots": checkSnapshots, "checkNodesDown": checkNodesDown, "snapRepo": snapRepo, "dryRun": cfg.GetDryRun()}).Info("Starting retention process")
 
 	client, err := utils.NewOSClientWithURL(cfg, cfg.GetOpenSearchURL())
 	if err != nil {
@@ -56,7 +59,7 @@ func runRetention(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to get utilization: %v", err)
 	}
-	logger.Info(fmt.Sprintf("Current disk utilization utilization=%d threshold=%.2f", avgUtil, threshold))
+	logger.WithFields(logging.Fields{"utilization": avgUtil, "threshold": fmt.Sprintf("%.2f", threshold)}).Info("Current disk utilization")
 
 	var nodesDiff int
 	nodesDiff, err = utils.CheckNodesDown(client, logger, checkNodesDown, kubeNamespace, true)
@@ -64,12 +67,12 @@ func runRetention(cmd *cobra.Command, args []string) error {
 		if checkNodesDown {
 			return fmt.Errorf("failed to check nodes: %v", err)
 		} else {
-			logger.Warn(fmt.Sprintf("Failed to check nodes (check disabled) error=%v", err))
+			logger.WithField("error", err).Warn("Failed to check nodes (check disabled)")
 		}
 	}
 
 	if checkNodesDown && nodesDiff != 0 {
-		logger.Info(fmt.Sprintf("Cannot run retention: nodes are down (difference=%d)", nodesDiff))
+		logger.WithField("difference", nodesDiff).Info("Cannot run retention: nodes are down")
 		return nil
 	}
 
@@ -79,7 +82,7 @@ func runRetention(cmd *cobra.Command, args []string) error {
 	}
 
 	cutoffDate := utils.FormatDate(time.Now().AddDate(0, 0, -retentionDaysCount), dateFormat)
-	logger.Info(fmt.Sprintf("Cutoff date for retention cutoffDate=%s retentionDaysCount=%d", cutoffDate, retentionDaysCount))
+	logger.WithFields(logging.Fields{"cutoffDate": cutoffDate, "retentionDaysCount": retentionDaysCount}).Info("Cutoff date for retention")
 
 	allIndices, err := client.GetIndicesWithFields("*", "index,ss", "ss:desc")
 	if err != nil {
@@ -121,7 +124,7 @@ func runRetention(cmd *cobra.Command, args []string) error {
 		if utils.IsOlderThanCutoff(indexName, cutoffDate, dateFormat) {
 			filteredIndices = append(filteredIndices, idx)
 		} else {
-			logger.Info(fmt.Sprintf("Skipping index: newer than cutoff date index=%s cutoffDate=%s", indexName, cutoffDate))
+			logger.WithFields(logging.Fields{"index": indexName, "cutoffDate": cutoffDate}).Info("Skipping index: newer than cutoff date")
 		}
 	}
 
@@ -131,7 +134,7 @@ func runRetention(cmd *cobra.Command, args []string) error {
 	}
 
 	found := utils.IndexInfosToNames(filteredIndices)
-	logger.Info(fmt.Sprintf("Found indices to evaluate count=%d indices=%s", len(filteredIndices), strings.Join(found, ", ")))
+	logger.WithFields(logging.Fields{"count": len(filteredIndices), "indices": strings.Join(found, ", ")}).Info("Found indices to evaluate")
 
 	var snapshots []opensearch.Snapshot
 	if checkSnapshots {
@@ -142,7 +145,7 @@ func runRetention(cmd *cobra.Command, args []string) error {
 		if snapshots == nil {
 			snapshots = []opensearch.Snapshot{}
 		}
-		logger.Info(fmt.Sprintf("Found snapshots count=%d", len(snapshots)))
+		logger.WithField("count", len(snapshots)).Info("Found snapshots")
 	}
 
 	var indicesToDelete []opensearch.IndexInfo
@@ -153,10 +156,11 @@ func runRetention(cmd *cobra.Command, args []string) error {
 
 		if checkSnapshots {
 			if !utils.HasValidSnapshot(idx.Index, snapshots) {
-				logger.Warn(fmt.Sprintf("No valid snapshots found index=%s", idx.Index))
+				logger.WithField("index", idx.Index).Warn("No valid snapshots found")
+				report.Skipped(idx.Index, "no valid snapshot")
 				continue
 			}
-			logger.Info(fmt.Sprintf("Valid snapshot found index=%s", idx.Index))
+			logger.WithField("index", idx.Index).Info("Valid snapshot found")
 		}
 
 		indicesToDelete = append(indicesToDelete, idx)
@@ -165,13 +169,14 @@ func runRetention(cmd *cobra.Command, args []string) error {
 	if cfg.GetDryRun() {
 		logger.Info("DRY RUN: Indices that would be deleted")
 		logger.Info("=" + strings.Repeat("=", 50))
-		count := 0
 		for _, idx := range indicesToDelete {
-			if count >= 5 {
+			report.Skipped(idx.Index, "dry run")
+		}
+		for i, idx := range indicesToDelete {
+			if i >= 5 {
 				break
 			}
-			logger.Info(fmt.Sprintf("%d. %s (size: %s)", count+1, idx.Index, idx.Size))
-			count++
+			logger.WithFields(logging.Fields{"index": idx.Index, "size": idx.Size}).Info(fmt.Sprintf("%d.", i+1))
 		}
 		if len(indicesToDelete) > 5 {
 			logger.Info(fmt.Sprintf("... and %d more indices", len(indicesToDelete)-5))
@@ -185,39 +190,46 @@ func runRetention(cmd *cobra.Command, args []string) error {
 
 	if len(indicesToDelete) > 0 {
 		delNames := utils.IndexInfosToNames(indicesToDelete)
-		logger.Info(fmt.Sprintf("Indices selected for deletion %s", strings.Join(delNames, ", ")))
+		logger.WithField("indices", strings.Join(delNames, ", ")).Info("Indices selected for deletion")
 	}
 	for _, idx := range indicesToDelete {
-		if err := client.DeleteIndex(idx.Index); err != nil {
-			logger.Error(fmt.Sprintf("Failed to delete index index=%s error=%v", idx.Index, err))
+		start := time.Now()
+		if err := client.WithAuditReason(fmt.Sprintf("disk utilization %d%% above threshold %.0f%%", avgUtil, threshold)).DeleteIndex(idx.Index); err != nil {
+			logger.WithFields(logging.Fields{"index": idx.Index, "error": err}).Error("Failed to delete index")
 			failedDeletions = append(failedDeletions, idx.Index)
+			report.Failed(idx.Index, err)
 			continue
 		}
+		report.Succeeded(idx.Index).Took(time.Since(start))
 
-		logger.Info(fmt.Sprintf("Deleted index index=%s", idx.Index))
+		logger.WithField("index", idx.Index).Info("Deleted index")
 		successfulDeletions = append(successfulDeletions, idx.Index)
+		metrics.IndicesDeleted.Inc()
+		if size, err := strconv.ParseFloat(idx.Size, 64); err == nil {
+			metrics.BytesFreed.Add(size)
+		}
 
 		time.Sleep(15 * time.Second)
 
 		avgUtil, err = utils.GetAverageUtilization(client, logger, false)
 		if err != nil {
-			logger.Error(fmt.Sprintf("Failed to get utilization after deletion error=%v", err))
+			logger.WithField("error", err).Error("Failed to get utilization after deletion")
 			break
 		}
-		logger.Info(fmt.Sprintf("Current disk utilization after deletion utilization=%d threshold=%.2f", avgUtil, threshold))
+		logger.WithFields(logging.Fields{"utilization": avgUtil, "threshold": fmt.Sprintf("%.2f", threshold)}).Info("Current disk utilization after deletion")
 
 		nodesDiff, err = utils.CheckNodesDown(client, logger, checkNodesDown, kubeNamespace, false)
 		if err != nil {
 			if checkNodesDown {
-				logger.Error(fmt.Sprintf("Failed to check nodes after deletion error=%v", err))
+				logger.WithField("error", err).Error("Failed to check nodes after deletion")
 				break
 			} else {
-				logger.Warn(fmt.Sprintf("Failed to check nodes after deletion (check disabled) error=%v", err))
+				logger.WithField("error", err).Warn("Failed to check nodes after deletion (check disabled)")
 			}
 		}
 
 		if checkNodesDown && nodesDiff != 0 {
-			logger.Info(fmt.Sprintf("Cannot continue retention: nodes are down (difference=%d)", nodesDiff))
+			logger.WithField("difference", nodesDiff).Info("Cannot continue retention: nodes are down")
 			break
 		}
 
@@ -236,14 +248,14 @@ func runRetention(cmd *cobra.Command, args []string) error {
 			logger.Info("")
 			logger.Info(fmt.Sprintf("Successfully deleted: %d indices", len(successfulDeletions)))
 			for _, name := range successfulDeletions {
-				logger.Info(fmt.Sprintf("  ✓ %s", name))
+				logger.WithField("index", name).Info("  ✓")
 			}
 		}
 		if len(failedDeletions) > 0 {
 			logger.Info("")
 			logger.Info(fmt.Sprintf("Failed to delete: %d indices", len(failedDeletions)))
 			for _, name := range failedDeletions {
-				logger.Info(fmt.Sprintf("  ✗ %s", name))
+				logger.WithField("index", name).Info("  ✗")
 			}
 		}
 		if len(successfulDeletions) == 0 && len(failedDeletions) == 0 {
@@ -252,6 +264,6 @@ func runRetention(cmd *cobra.Command, args []string) error {
 		logger.Info(strings.Repeat("=", 60))
 	}
 
-	logger.Info(fmt.Sprintf("Retention completed finalUtilization=%d", avgUtil))
+	logger.WithField("finalUtilization", avgUtil).Info("Retention completed")
 	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"osctl/pkg/config"
 	"osctl/pkg/logging"
+	"osctl/pkg/utils"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -56,12 +57,13 @@ func Execute(version string) error {
 		appVersion = "dev"
 	}
 	rootCmd.Version = appVersion
+	utils.OsctlVersion = appVersion
 
 	logger := logging.NewLogger()
-	logger.Info(fmt.Sprintf("osctl version=%s", appVersion))
+	logger.WithField("version", appVersion).Info("osctl")
 
 	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
-		commandName := cmd.Name()
+		commandName := commandConfigName(cmd)
 		commandPath := cmd.CommandPath()
 		if commandName == "completion" || commandName == "help" ||
 			commandPath == "osctl completion" || strings.HasPrefix(commandPath, "osctl completion") {
@@ -119,6 +121,22 @@ func executeActionCommand(action string, args []string) error {
 		targetCmd = dataSourceCmd
 	case "restore":
 		targetCmd = restoreCmd
+	case "mappingchecker":
+		targetCmd = mappingCheckerCmd
+	case "healthchecker":
+		targetCmd = healthCheckerCmd
+	case "repositories":
+		targetCmd = repositoriesCmd
+	case "snapshotverify":
+		targetCmd = snapshotVerifyCmd
+	case "inventory":
+		targetCmd = inventoryCmd
+	case "extract":
+		targetCmd = extractCmd
+	case "snapshotcopy":
+		targetCmd = snapshotCopyCmd
+	case "status":
+		targetCmd = statusCmd
 	default:
 		return fmt.Errorf("unknown action: %s", action)
 	}
@@ -148,11 +166,24 @@ func init() {
 		coldStorageCmd,
 		extractedDeleteCmd,
 		restoreCmd,
+		templatesCmd,
+		alertsCmd,
+		mappingCheckerCmd,
+		healthCheckerCmd,
+		repositoriesCmd,
+		snapshotVerifyCmd,
+		inventoryCmd,
+		extractCmd,
+		snapshotCopyCmd,
+		statusCmd,
+		explainCmd,
+		configCmd,
 	}
 	for _, cmd := range commands {
 		cmd.SilenceUsage = true
 		rootCmd.AddCommand(cmd)
 	}
+	instrumentActions(commands)
 }
 
 func addFlags(cmd *cobra.Command) {
@@ -168,8 +199,40 @@ func addFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().String("madison-url", "", "Madison API URL")
 	cmd.PersistentFlags().String("osd-url", "", "OpenSearch Dashboards URL")
 	cmd.PersistentFlags().String("madison-key", "", "Madison API key")
+	cmd.PersistentFlags().String("alert-notifiers", "", "Comma-separated alert notifiers: madison, alertmanager, webhook, slack")
+	cmd.PersistentFlags().String("alertmanager-url", "", "Prometheus Alertmanager URL")
+	cmd.PersistentFlags().String("alert-webhook-url", "", "Generic alert webhook URL")
+	cmd.PersistentFlags().String("alert-webhook-template", "", "Go template for the generic alert webhook JSON body")
+	cmd.PersistentFlags().String("slack-webhook-url", "", "Slack-compatible incoming webhook URL")
+	cmd.PersistentFlags().String("alert-state-store", "", "Where alert state and silences are kept: index, file or none")
+	cmd.PersistentFlags().String("alert-state-index", "", "Index for alert state when alert-state-store=index")
+	cmd.PersistentFlags().String("alert-state-file", "", "File for alert state when alert-state-store=file")
+	cmd.PersistentFlags().Duration("alert-dedup-window", 0, "Do not repeat an alert for the same trigger and subject within this window")
+	cmd.PersistentFlags().Bool("alert-resolve", true, "Send resolve notifications when an alert condition clears")
+	cmd.PersistentFlags().String("alert-language", "ru", "Language of built-in alert texts: ru or en")
+	cmd.PersistentFlags().String("alert-templates-dir", "", "Directory with <Trigger>.tmpl files overriding built-in alert texts")
+	cmd.PersistentFlags().String("alert-cluster-name", "", "Cluster name shown in alert texts (default: OpenSearch host)")
+	cmd.PersistentFlags().String("alert-run-link", "", "Go template for a link to the run shown in alert texts (e.g. logs URL with {{.Namespace}} and {{.Pod}})")
+	cmd.PersistentFlags().String("metrics-listen", "", "Address for the /metrics endpoint in daemon mode (e.g. :9102)")
+	cmd.PersistentFlags().String("metrics-pushgateway-url", "", "Prometheus Pushgateway URL to push metrics to after each run")
+	cmd.PersistentFlags().String("metrics-job", "", "Job name for Pushgateway metrics")
+	cmd.PersistentFlags().String("metrics-textfile-dir", "", "node_exporter textfile collector directory for osctl_<action>.prom")
+	cmd.PersistentFlags().Duration("daemon-interval", 0, "Run the action repeatedly with this interval instead of once (daemon mode)")
+	cmd.PersistentFlags().String("report-file", "", "Write a JSON run report to this file (- for stdout)")
+	cmd.PersistentFlags().Bool("report-index", false, "Index the run report into osctl-runs-YYYY.MM.DD")
+	cmd.PersistentFlags().String("audit-file", "", "Append audit records of mutating operations to this JSONL file")
+	cmd.PersistentFlags().String("audit-index", "", "Write audit records of mutating operations to this index")
+	cmd.PersistentFlags().String("log-level", "info", "Log level: debug, info, warn or error")
+	cmd.PersistentFlags().String("log-format", "json", "Log format: json or text")
 	cmd.PersistentFlags().Bool("dry-run", false, "Show what would be done without executing")
 
-	commandName := cmd.Name()
+	commandName := commandConfigName(cmd)
 	config.AddCommandFlags(cmd, commandName)
 }
+
+func commandConfigName(cmd *cobra.Command) string {
+	if cmd.HasParent() && cmd.Parent() != rootCmd {
+		return cmd.Parent().Name() + "-" + cmd.Name()
+	}
+	return cmd.Name()
+}
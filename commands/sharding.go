@@ -6,6 +6,7 @@ import (
 	"math"
 	"osctl/pkg/config"
 	"osctl/pkg/logging"
+	"osctl/pkg/report"
 	"osctl/pkg/utils"
 	"regexp"
 	"strconv"
@@ -53,7 +54,7 @@ func runSharding(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	logger.Info(fmt.Sprintf("Indices discovered: total=%d, today=%d", len(indicesAll), len(indicesToday)))
+	logger.WithFields(logging.Fields{"total": len(indicesAll), "today": len(indicesToday)}).Info("Indices discovered")
 
 	sizes := map[string]int64{}
 	for _, ii := range indicesAll {
@@ -63,7 +64,7 @@ func runSharding(cmd *cobra.Command, args []string) error {
 		if b, err := strconv.ParseInt(ii.PriStoreSize, 10, 64); err == nil {
 			sizes[ii.Index] = b
 		} else {
-			logger.Info(fmt.Sprintf("DEBUG: Failed to parse size as int for index %s: %s", ii.Index, ii.PriStoreSize))
+			logger.WithFields(logging.Fields{"index": ii.Index, "size": ii.PriStoreSize}).Debug("Failed to parse size as int")
 			sizes[ii.Index] = 0
 		}
 	}
@@ -99,7 +100,7 @@ func runSharding(cmd *cobra.Command, args []string) error {
 	for _, it := range indicesToday {
 		name := it.Index
 		if strings.HasPrefix(name, ".") {
-			logger.Info(fmt.Sprintf("Skip system index %s", name))
+			logger.WithField("index", name).Info("Skip system index")
 			continue
 		}
 		base := name
@@ -110,7 +111,7 @@ func runSharding(cmd *cobra.Command, args []string) error {
 		}
 		pattern := base + "-*"
 		if excludeRe != nil && excludeRe.MatchString(pattern) {
-			logger.Info(fmt.Sprintf("Skip excluded pattern %s", pattern))
+			logger.WithField("pattern", pattern).Info("Skip excluded pattern")
 			continue
 		}
 		if pi, ok := patterns[pattern]; ok {
@@ -135,18 +136,18 @@ func runSharding(cmd *cobra.Command, args []string) error {
 
 	allTemplates, err := client.GetAllIndexTemplates()
 	if err == nil {
-		logger.Info(fmt.Sprintf("DEBUG: Found %d existing index templates", len(allTemplates.IndexTemplates)))
+		logger.Debug(fmt.Sprintf("Found %d existing index templates", len(allTemplates.IndexTemplates)))
 		for _, t := range allTemplates.IndexTemplates {
 			patternsStr := strings.Join(t.IndexTemplate.IndexPatterns, ", ")
-			logger.Info(fmt.Sprintf("DEBUG: Template=%s patterns=[%s] priority=%d", t.Name, patternsStr, t.IndexTemplate.Priority))
+			logger.WithFields(logging.Fields{"template": t.Name, "patterns": patternsStr, "priority": t.IndexTemplate.Priority}).Debug("Existing index template")
 		}
 	} else {
-		logger.Info(fmt.Sprintf("DEBUG: Failed to get all templates: %v", err))
+		logger.WithField("error", err).Debug("Failed to get all templates")
 	}
 
 	defaultTemplateExists, err := utils.TemplateExists(client, "default_template")
 	if err != nil {
-		logger.Warn(fmt.Sprintf("Failed to check default_template existence: %v", err))
+		logger.WithField("error", err).Warn("Failed to check default_template existence")
 		defaultTemplateExists = false
 	}
 
@@ -160,19 +161,19 @@ func runSharding(cmd *cobra.Command, args []string) error {
 			maxSize = pi.maxSize
 		}
 		shards := computeShardCount(maxSize, targetBytes, dataNodes, pi.indices[0], logger)
-		logger.Info(fmt.Sprintf("Evaluate pattern=%s template=%s indices=%d maxSize=%dB targetBytes=%dB shards=%d dataNodes=%d priority=%d", pattern, templateName, len(pi.indices), maxSize, targetBytes, shards, dataNodes, priority))
+		logger.WithFields(logging.Fields{"pattern": pattern, "template": templateName, "indices": len(pi.indices), "maxSize": maxSize, "targetBytes": targetBytes, "shards": shards, "dataNodes": dataNodes, "priority": priority}).Info("Evaluate pattern")
 
-		logger.Info(fmt.Sprintf("DEBUG: Checking for existing template with pattern=%s", pattern))
+		logger.WithField("pattern", pattern).Debug("Checking for existing template")
 		normalizedPattern := strings.TrimSuffix(pattern, "*")
-		logger.Info(fmt.Sprintf("DEBUG: normalizedPattern=%s", normalizedPattern))
+		logger.WithFields(logging.Fields{"pattern": pattern, "normalizedPattern": normalizedPattern}).Debug("Normalized pattern")
 		existing, err := client.FindIndexTemplateByPattern(pattern)
 		if err != nil {
 			return err
 		}
 		if existing != "" {
-			logger.Info(fmt.Sprintf("DEBUG: Found existing template=%s for pattern=%s", existing, pattern))
+			logger.WithFields(logging.Fields{"template": existing, "pattern": pattern}).Debug("Found existing template")
 		} else {
-			logger.Info(fmt.Sprintf("DEBUG: No existing template found for pattern=%s (normalized: %s)", pattern, normalizedPattern))
+			logger.WithFields(logging.Fields{"pattern": pattern, "normalizedPattern": normalizedPattern}).Debug("No existing template found")
 		}
 		replicas := 1
 		if dataNodes <= 1 {
@@ -216,16 +217,19 @@ func runSharding(cmd *cobra.Command, args []string) error {
 				priority: priority,
 			}
 			if cfg.GetDryRun() {
-				logger.Info(fmt.Sprintf("DRY RUN: Would create index template %s for pattern %s with shards=%d replicas=%d priority=%d", templateName, pattern, shards, replicas, priority))
+				logger.WithFields(logging.Fields{"template": templateName, "pattern": pattern, "shards": shards, "replicas": replicas, "priority": priority}).Info("DRY RUN: Would create index template")
 				changes = append(changes, ch)
+				report.Skipped(ch.template, "dry run")
 			} else {
-				logger.Info(fmt.Sprintf("Create index template %s for pattern %s with %d shards", templateName, pattern, shards))
+				logger.WithFields(logging.Fields{"template": templateName, "pattern": pattern, "shards": shards}).Info("Create index template")
 				if err := client.PutIndexTemplate(templateName, template); err != nil {
-					logger.Error(fmt.Sprintf("Failed to create index template template=%s pattern=%s error=%v", templateName, pattern, err))
+					logger.WithFields(logging.Fields{"template": templateName, "pattern": pattern, "error": err}).Error("Failed to create index template")
 					failedChanges = append(failedChanges, ch)
+					report.Failed(ch.template, err)
 					continue
 				}
 				successfulChanges = append(successfulChanges, ch)
+				report.Succeeded(ch.template)
 			}
 		} else {
 			curShards := 1
@@ -234,9 +238,10 @@ func runSharding(cmd *cobra.Command, args []string) error {
 					curShards = s
 				}
 			}
-			logger.Info(fmt.Sprintf("DEBUG: Template %s: current shards=%d, target shards=%d", existing, curShards, shards))
+			logger.WithFields(logging.Fields{"template": existing, "current": curShards, "target": shards}).Debug("Template shards")
 			if curShards == shards {
-				logger.Info(fmt.Sprintf("Template %s already has correct shards: %d", existing, shards))
+				logger.WithFields(logging.Fields{"template": existing, "shards": shards}).Info("Template already has correct shards")
+				report.Skipped(existing, "already has correct shards")
 				continue
 			}
 			ch := templateChange{
@@ -249,10 +254,11 @@ func runSharding(cmd *cobra.Command, args []string) error {
 				oldReplicas: replicas,
 			}
 			if cfg.GetDryRun() {
-				logger.Info(fmt.Sprintf("DRY RUN: Would update template %s: shards %d to %d", existing, curShards, shards))
+				logger.WithFields(logging.Fields{"template": existing, "from": curShards, "to": shards}).Info("DRY RUN: Would update template shards")
 				changes = append(changes, ch)
+				report.Skipped(ch.template, "dry run")
 			} else {
-				logger.Info(fmt.Sprintf("Update existing template %s: set number_of_shards=%d", existing, shards))
+				logger.WithFields(logging.Fields{"template": existing, "shards": shards}).Info("Update existing template number_of_shards")
 				var current map[string]any
 				if tpl, err := client.GetIndexTemplate(existing); err == nil && len(tpl.IndexTemplates) > 0 {
 					it := tpl.IndexTemplates[0].IndexTemplate
@@ -298,11 +304,13 @@ func runSharding(cmd *cobra.Command, args []string) error {
 					}
 				}
 				if err := client.PutIndexTemplate(existing, current); err != nil {
-					logger.Error(fmt.Sprintf("Failed to update index template template=%s pattern=%s error=%v", existing, pattern, err))
+					logger.WithFields(logging.Fields{"template": existing, "pattern": pattern, "error": err}).Error("Failed to update index template")
 					failedChanges = append(failedChanges, ch)
+					report.Failed(ch.template, err)
 					continue
 				}
 				successfulChanges = append(successfulChanges, ch)
+				report.Succeeded(ch.template)
 			}
 		}
 	}
@@ -315,9 +323,9 @@ func runSharding(cmd *cobra.Command, args []string) error {
 		logger.Info("")
 		for _, ch := range changes {
 			if ch.action == "create" {
-				logger.Info(fmt.Sprintf("CREATE: template=%s pattern=%s shards=%d replicas=%d priority=%d", ch.template, ch.pattern, ch.shards, ch.replicas, ch.priority))
+				logger.WithFields(logging.Fields{"template": ch.template, "pattern": ch.pattern, "shards": ch.shards, "replicas": ch.replicas, "priority": ch.priority}).Info("CREATE")
 			} else {
-				logger.Info(fmt.Sprintf("UPDATE: template=%s pattern=%s shards %d to %d", ch.template, ch.pattern, ch.oldShards, ch.shards))
+				logger.WithFields(logging.Fields{"template": ch.template, "pattern": ch.pattern, "from": ch.oldShards, "to": ch.shards}).Info("UPDATE")
 			}
 		}
 		logger.Info("")
@@ -332,9 +340,9 @@ func runSharding(cmd *cobra.Command, args []string) error {
 			logger.Info(fmt.Sprintf("Successfully changed: %d templates", len(successfulChanges)))
 			for _, ch := range successfulChanges {
 				if ch.action == "create" {
-					logger.Info(fmt.Sprintf("  ✓ Created: %s (pattern=%s, shards=%d, replicas=%d)", ch.template, ch.pattern, ch.shards, ch.replicas))
+					logger.WithFields(logging.Fields{"pattern": ch.pattern, "shards": ch.shards, "replicas": ch.replicas}).WithField("template", ch.template).Info("  ✓ Created")
 				} else {
-					logger.Info(fmt.Sprintf("  ✓ Updated: %s (pattern=%s, shards %d→%d)", ch.template, ch.pattern, ch.oldShards, ch.shards))
+					logger.WithFields(logging.Fields{"pattern": ch.pattern, "from": ch.oldShards, "to": ch.shards}).WithField("template", ch.template).Info("  ✓ Updated")
 				}
 			}
 		}
@@ -343,9 +351,9 @@ func runSharding(cmd *cobra.Command, args []string) error {
 			logger.Info(fmt.Sprintf("Failed to change: %d templates", len(failedChanges)))
 			for _, ch := range failedChanges {
 				if ch.action == "create" {
-					logger.Info(fmt.Sprintf("  ✗ Failed to create: %s (pattern=%s)", ch.template, ch.pattern))
+					logger.WithField("pattern", ch.pattern).WithField("template", ch.template).Info("  ✗ Failed to create")
 				} else {
-					logger.Info(fmt.Sprintf("  ✗ Failed to update: %s (pattern=%s)", ch.template, ch.pattern))
+					logger.WithField("pattern", ch.pattern).WithField("template", ch.template).Info("  ✗ Failed to update")
 				}
 			}
 		}
@@ -362,7 +370,7 @@ func computeMaxSizeForPattern(sizes map[string]int64, base string, todaySizeStr
 	normalizedBase := strings.TrimSuffix(base, "-")
 	searchPrefix := normalizedBase + "-"
 
-	logger.Info(fmt.Sprintf("DEBUG computeMaxSize: base=%s normalizedBase=%s searchPrefix=%s pattern=%s", base, normalizedBase, searchPrefix, pattern))
+	logger.WithFields(logging.Fields{"base": base, "normalizedBase": normalizedBase, "searchPrefix": searchPrefix, "pattern": pattern}).Debug("computeMaxSize")
 
 	maxSize := int64(0)
 	foundCount := 0
@@ -381,23 +389,23 @@ func computeMaxSizeForPattern(sizes map[string]int64, base string, todaySizeStr
 	if len(foundIndices) >= 5 {
 		foundIndices = append(foundIndices, fmt.Sprintf("... and %d more", foundCount-5))
 	}
-	logger.Info(fmt.Sprintf("DEBUG computeMaxSize: pattern=%s foundCount=%d maxSize=%dB foundIndices=%v", pattern, foundCount, maxSize, foundIndices))
+	logger.WithFields(logging.Fields{"pattern": pattern, "foundCount": foundCount, "maxSize": maxSize, "foundIndices": strings.Join(foundIndices, ", ")}).Debug("computeMaxSize: scanned indices")
 
 	var szToday int64
 	if b, err := strconv.ParseInt(todaySizeStr, 10, 64); err == nil {
 		szToday = b
 	} else {
-		logger.Info(fmt.Sprintf("DEBUG computeMaxSize: pattern=%s todaySizeStr=%s parse error=%v", pattern, todaySizeStr, err))
+		logger.WithFields(logging.Fields{"pattern": pattern, "todaySize": todaySizeStr, "error": err}).Debug("computeMaxSize: failed to parse today size")
 		szToday = 0
 	}
 	if szToday > maxSize {
-		logger.Info(fmt.Sprintf("DEBUG computeMaxSize: pattern=%s todaySizeStr=%s todaySize=%dB (using today size)", pattern, todaySizeStr, szToday))
+		logger.WithFields(logging.Fields{"pattern": pattern, "todaySize": szToday}).Debug("computeMaxSize: using today size")
 		maxSize = szToday
 	} else if szToday == 0 && todaySizeStr != "" && todaySizeStr != "0" {
-		logger.Info(fmt.Sprintf("DEBUG computeMaxSize: pattern=%s todaySizeStr=%s could not parse size", pattern, todaySizeStr))
+		logger.WithFields(logging.Fields{"pattern": pattern, "todaySize": todaySizeStr}).Debug("computeMaxSize: could not parse size")
 	}
 
-	logger.Info(fmt.Sprintf("DEBUG computeMaxSize: pattern=%s final maxSize=%dB", pattern, maxSize))
+	logger.WithFields(logging.Fields{"pattern": pattern, "maxSize": maxSize}).Debug("computeMaxSize: final")
 	return maxSize
 }
 
@@ -406,7 +414,7 @@ func computeShardCount(maxSize int64, targetBytes int64, dataNodes int, indexNam
 	if maxSize > targetBytes {
 		shards = int(math.Floor(float64(maxSize)/float64(targetBytes))) + 1
 		if shards > dataNodes {
-			logger.Warn(fmt.Sprintf("Index %s needs %d primary shards, but cluster has %d data nodes. Reducing to %d", indexName, shards, dataNodes, dataNodes))
+			logger.WithFields(logging.Fields{"index": indexName, "shards": shards, "dataNodes": dataNodes}).Warn("Index needs more primary shards than data nodes, reducing to data nodes count")
 			shards = dataNodes
 		}
 	}
@@ -2,10 +2,10 @@ package commands
 
 import (
 	"fmt"
-	"osctl/pkg/alerts"
 	"osctl/pkg/config"
 	"osctl/pkg/logging"
 	"osctl/pkg/opensearch"
+	"osctl/pkg/report"
 	"osctl/pkg/utils"
 	"strings"
 	"time"
@@ -54,17 +54,14 @@ func runSnapshotManual(cmd *cobra.Command, args []string) error {
 		system = utils.IsSystem(tempConfig, value)
 	}
 
-	logger.Info(fmt.Sprintf("Starting manual snapshot creation kind=%s value=%s name=%s system=%t (auto-detected=%t)", kind, value, name, system, cfg.SnapshotManualSystem == ""))
+	logger.WithFields(logging.Fields{"kind": kind, "value": value, "name": name, "system": system, "autoDetected": cfg.SnapshotManualSystem == ""}).Info("Starting manual snapshot creation")
 
 	client, err := utils.NewOSClientWithURL(cfg, cfg.GetOpenSearchURL())
 	if err != nil {
 		return err
 	}
 
-	var madisonClient *alerts.Client
-	if cfg.GetMadisonKey() != "" && cfg.GetOSDURL() != "" && cfg.GetMadisonURL() != "" {
-		madisonClient = alerts.NewMadisonClient(cfg.GetMadisonKey(), cfg.GetOSDURL(), cfg.GetMadisonURL())
-	}
+	notifier := utils.NewNotifier(cfg)
 
 	yesterday := utils.FormatDate(time.Now().AddDate(0, 0, -1), cfg.GetDateFormat())
 	today := utils.FormatDate(time.Now(), cfg.GetDateFormat())
@@ -82,7 +79,7 @@ func runSnapshotManual(cmd *cobra.Command, args []string) error {
 
 	allNames := utils.IndexInfosToNames(allIndices)
 	if len(allNames) > 0 {
-		logger.Info(fmt.Sprintf("Found indices %s", strings.Join(allNames, ", ")))
+		logger.WithField("indices", strings.Join(allNames, ", ")).Info("Found indices")
 	} else {
 		logger.Info("Found indices none")
 	}
@@ -105,7 +102,7 @@ func runSnapshotManual(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	logger.Info(fmt.Sprintf("Matched indices %s", strings.Join(matchingIndices, ", ")))
+	logger.WithField("indices", strings.Join(matchingIndices, ", ")).Info("Matched indices")
 
 	snapshotName := utils.BuildSnapshotName(kind, name, value, today)
 
@@ -114,29 +111,28 @@ func runSnapshotManual(cmd *cobra.Command, args []string) error {
 		repoToUse = cfg.GetSnapshotManualRepo()
 	}
 
+	if err := utils.CheckSnapshotRepositories(client, []string{repoToUse}, logger); err != nil {
+		return err
+	}
+
 	if cfg.GetDryRun() {
 		if state, ok, _ := utils.CheckSnapshotStateInRepo(client, repoToUse, snapshotName); ok && state == "SUCCESS" {
-			logger.Info(fmt.Sprintf("Valid snapshot already exists snapshot=%s", snapshotName))
+			logger.WithField("snapshot", snapshotName).Info("Valid snapshot already exists")
 			return nil
 		}
 		if state, ok, _ := utils.CheckSnapshotStateInRepo(client, repoToUse, snapshotName); ok && state == "IN_PROGRESS" {
-			logger.Info(fmt.Sprintf("Snapshot is currently IN_PROGRESS snapshot=%s repo=%s", snapshotName, repoToUse))
+			logger.WithFields(logging.Fields{"snapshot": snapshotName, "repo": repoToUse}).Info("Snapshot is currently IN_PROGRESS")
 			return nil
 		}
 		logger.Info("DRY RUN: Manual snapshot creation plan")
 		logger.Info("=" + strings.Repeat("=", 50))
 
 		logger.Info("")
-		logger.Info(fmt.Sprintf("Snapshot (repo %s): %s", repoToUse, snapshotName))
-		logger.Info(fmt.Sprintf("Pattern: %s (%s)", value, kind))
-		logger.Info(fmt.Sprintf("Indices (%d):", len(matchingIndices)))
-
-		for _, index := range matchingIndices {
-			logger.Info(fmt.Sprintf("  %s", index))
-		}
+		logger.WithFields(logging.Fields{"repo": repoToUse, "snapshot": snapshotName, "pattern": value, "kind": kind, "count": len(matchingIndices), "indices": strings.Join(matchingIndices, ", ")}).Info("Snapshot")
 
 		logger.Info("")
 		logger.Info("DRY RUN: Would create 1 manual snapshot")
+		report.Skipped(fmt.Sprintf("%s (repo=%s)", snapshotName, repoToUse), "dry run")
 		return nil
 	}
 
@@ -155,28 +151,27 @@ func runSnapshotManual(cmd *cobra.Command, args []string) error {
 
 	existNames := utils.SnapshotsToNames(allSnapshots)
 	if len(existNames) > 0 {
-		logger.Info(fmt.Sprintf("Existing snapshots today %s", strings.Join(existNames, ", ")))
+		logger.WithField("snapshots", strings.Join(existNames, ", ")).Info("Existing snapshots today")
 	} else {
 		logger.Info("Existing snapshots today none")
 	}
 
 	exists, err := utils.CheckAndCleanSnapshot(snapshotName, strings.Join(matchingIndices, ","), allSnapshots, client, repoToUse, logger)
 	if err != nil {
-		logger.Error(fmt.Sprintf("Failed to check/clean snapshot snapshot=%s error=%v", snapshotName, err))
+		logger.WithFields(logging.Fields{"snapshot": snapshotName, "error": err}).Error("Failed to check/clean snapshot")
 		return err
 	}
 
 	if exists {
-		logger.Info(fmt.Sprintf("Valid snapshot already exists snapshot=%s", snapshotName))
+		logger.WithField("snapshot", snapshotName).Info("Valid snapshot already exists")
 		return nil
 	}
 
 	indicesStr := strings.Join(matchingIndices, ",")
-	logger.Info(fmt.Sprintf("Creating snapshot %s", snapshotName))
-	logger.Info(fmt.Sprintf("Snapshot indices %s", indicesStr))
-	err = utils.CreateSnapshotWithRetry(client, snapshotName, indicesStr, repoToUse, cfg.GetKubeNamespace(), today, madisonClient, logger, 60*time.Second, cfg.GetMaxConcurrentSnapshots(), 0)
+	logger.WithFields(logging.Fields{"snapshot": snapshotName, "indices": indicesStr}).Info("Creating snapshot")
+	err = utils.CreateSnapshotWithRetry(client, snapshotName, indicesStr, repoToUse, cfg.GetKubeNamespace(), today, notifier, logger, 60*time.Second, cfg.GetMaxConcurrentSnapshots(), 0, nil, utils.NewSnapshotMetadata("snapshot-manual", kind, value, name))
 	if err != nil {
-		logger.Error(fmt.Sprintf("Failed to create snapshot after retries snapshot=%s error=%v", snapshotName, err))
+		logger.WithFields(logging.Fields{"snapshot": snapshotName, "error": err}).Error("Failed to create snapshot after retries")
 		return err
 	}
 
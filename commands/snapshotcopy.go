@@ -0,0 +1,294 @@
+package commands
+
+import (
+	"fmt"
+	"osctl/pkg/alerts"
+	"osctl/pkg/config"
+	"osctl/pkg/logging"
+	"osctl/pkg/opensearch"
+	"osctl/pkg/report"
+	"osctl/pkg/utils"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var snapshotCopyCmd = &cobra.Command{
+	Use:   "snapshotcopy",
+	Short: "Copy snapshots of selected prefixes into a secondary repository",
+	Long: `Mirror SUCCESS snapshots of rules with secondary_repository into that repository.
+When the source and secondary repositories share a store the snapshot is cloned
+(_clone into <snapshot>-copy), otherwise its indices are restored on a scratch cluster
+(os-scratch-url) and snapshotted again into the secondary repository under the same name.
+Copies older than secondary_snapshot_count days are not made; snapshotsdelete removes them.`,
+	RunE: runSnapshotCopy,
+}
+
+func init() {
+	addFlags(snapshotCopyCmd)
+}
+
+type snapshotCopyTask struct {
+	sourceRepo    string
+	secondaryRepo string
+	snapshot      opensearch.Snapshot
+	copyName      string
+	clone         bool
+}
+
+func runSnapshotCopy(cmd *cobra.Command, args []string) error {
+	cfg := config.GetConfig()
+	logger := logging.NewLogger()
+	client, err := utils.NewOSClientWithURL(cfg, cfg.GetOpenSearchURL())
+	if err != nil {
+		return fmt.Errorf("failed to create OpenSearch client: %v", err)
+	}
+
+	indicesConfig, err := cfg.GetOsctlIndices()
+	if err != nil {
+		return fmt.Errorf("failed to get osctl indices: %v", err)
+	}
+
+	rules := utils.SecondaryCopyRules(indicesConfig)
+	mode := cfg.GetSnapshotCopyMode()
+	dateFormat := cfg.GetDateFormat()
+	s3Config := cfg.GetOsctlIndicesS3SnapshotsConfig()
+	logger.WithFields(logging.Fields{"rules": len(rules), "mode": mode, "maxCopies": cfg.GetSnapshotCopyMaxCopies(), "dryRun": cfg.GetDryRun()}).Info("Starting snapshotcopy")
+	if len(rules) == 0 {
+		logger.Info("No rules with secondary_repository, nothing to copy")
+		return nil
+	}
+
+	registered, err := client.GetRepositories()
+	if err != nil {
+		return fmt.Errorf("failed to get snapshot repositories: %v", err)
+	}
+
+	var tasks []snapshotCopyTask
+	var skipped []string
+	snapshotsByRepo := make(map[string][]opensearch.Snapshot)
+	listRepo := func(repo string) ([]opensearch.Snapshot, error) {
+		if snaps, ok := snapshotsByRepo[repo]; ok {
+			return snaps, nil
+		}
+		snaps, err := utils.GetSnapshotsIgnore404(client, repo, "*")
+		if err != nil {
+			return nil, err
+		}
+		snapshotsByRepo[repo] = snaps
+		return snaps, nil
+	}
+
+	for _, rule := range rules {
+		sourceRepo := utils.SourceRepository(cfg.GetSnapshotRepo(), rule)
+		secondaryRepo := rule.SecondaryRepository
+		if secondaryRepo == sourceRepo {
+			skipped = append(skipped, fmt.Sprintf("%s: secondary_repository equals the source repository %s", rule.Value, sourceRepo))
+			continue
+		}
+		if _, ok := registered[secondaryRepo]; !ok {
+			skipped = append(skipped, fmt.Sprintf("%s: secondary repository %s is not registered", rule.Value, secondaryRepo))
+			continue
+		}
+		shared := utils.RepositoriesShareStore(registered, sourceRepo, secondaryRepo)
+		clone := mode == "clone" || (mode == "auto" && shared)
+		if clone && !shared {
+			skipped = append(skipped, fmt.Sprintf("%s: snapshotcopy-mode=clone, but %s and %s do not share a store", rule.Value, sourceRepo, secondaryRepo))
+			continue
+		}
+		if !clone && cfg.GetSnapshotCopyScratchURL() == "" {
+			skipped = append(skipped, fmt.Sprintf("%s: %s and %s do not share a store and os-scratch-url is not set", rule.Value, sourceRepo, secondaryRepo))
+			continue
+		}
+
+		sourceSnapshots, err := listRepo(sourceRepo)
+		if err != nil {
+			return fmt.Errorf("failed to get snapshots repo=%s: %v", sourceRepo, err)
+		}
+		copies, err := listRepo(secondaryRepo)
+		if err != nil {
+			return fmt.Errorf("failed to get snapshots repo=%s: %v", secondaryRepo, err)
+		}
+
+		cutoff := utils.FormatDate(time.Now().AddDate(0, 0, -utils.SecondaryRetentionDays(rule, s3Config)), dateFormat)
+		for _, s := range sourceSnapshots {
+			if s.State != "SUCCESS" || utils.IsSnapshotCopy(s.Snapshot) || !utils.HasDateInName(s.Snapshot, dateFormat) {
+				continue
+			}
+			if !utils.SnapshotBelongsToRule(s, rule, indicesConfig) {
+				continue
+			}
+			if utils.IsOlderThanCutoff(s.Snapshot, cutoff, dateFormat) || utils.HasSnapshotCopy(s.Snapshot, copies) {
+				continue
+			}
+			tasks = append(tasks, snapshotCopyTask{
+				sourceRepo:    sourceRepo,
+				secondaryRepo: secondaryRepo,
+				snapshot:      s,
+				copyName:      utils.SnapshotCopyName(s.Snapshot, shared),
+				clone:         clone,
+			})
+		}
+	}
+	for _, s := range skipped {
+		logger.Warn("Skipping rule: " + s)
+		name, reason, _ := strings.Cut(s, ": ")
+		report.Skipped(name, reason)
+	}
+
+	if maxCopies := cfg.GetSnapshotCopyMaxCopies(); len(tasks) > maxCopies {
+		logger.Info(fmt.Sprintf("Snapshots without a copy: %d, copying %d this run", len(tasks), maxCopies))
+		tasks = tasks[:maxCopies]
+	}
+	logger.Info(fmt.Sprintf("Snapshots to copy: %d", len(tasks)))
+
+	if cfg.GetDryRun() {
+		for i, t := range tasks {
+			method := "restore+snapshot via scratch cluster"
+			if t.clone {
+				method = "_clone"
+			}
+			logger.WithFields(logging.Fields{"repo": t.sourceRepo, "snapshot": t.snapshot.Snapshot, "targetRepo": t.secondaryRepo, "target": t.copyName, "method": method}).Info(fmt.Sprintf("DRY RUN: Would copy %d", i+1))
+			report.Skipped(fmt.Sprintf("%s/%s → %s/%s", t.sourceRepo, t.snapshot.Snapshot, t.secondaryRepo, t.copyName), "dry run")
+		}
+		return nil
+	}
+
+	var scratch *opensearch.Client
+	var copied, failed, details []string
+	for _, t := range tasks {
+		start := time.Now()
+		var err error
+		if t.clone {
+			err = cloneSnapshotCopy(client, t, cfg.GetSnapshotCopyTimeout(), logger)
+		} else {
+			if scratch == nil {
+				scratch, err = utils.NewOSClientWithURL(cfg, cfg.GetSnapshotCopyScratchURL())
+				if err != nil {
+					return fmt.Errorf("failed to create OpenSearch scratch client: %v", err)
+				}
+				if err := utils.CheckSnapshotRepositories(scratch, snapshotCopyRepos(tasks), logger); err != nil {
+					return err
+				}
+			}
+			err = resnapshotCopy(scratch, t, cfg.GetSnapshotCopyTimeout(), logger)
+		}
+		line := fmt.Sprintf("%s/%s → %s/%s", t.sourceRepo, t.snapshot.Snapshot, t.secondaryRepo, t.copyName)
+		if err != nil {
+			logger.WithFields(logging.Fields{"repo": t.sourceRepo, "snapshot": t.snapshot.Snapshot, "targetRepo": t.secondaryRepo, "target": t.copyName, "error": err}).Error("Snapshot copy failed")
+			failed = append(failed, t.snapshot.Snapshot)
+			details = append(details, fmt.Sprintf("- %s: %v", line, err))
+			report.Failed(line, err).Took(time.Since(start))
+			continue
+		}
+		logger.WithFields(logging.Fields{"repo": t.sourceRepo, "snapshot": t.snapshot.Snapshot, "targetRepo": t.secondaryRepo, "target": t.copyName}).Info("Snapshot copied")
+		copied = append(copied, line)
+		report.Succeeded(line).Took(time.Since(start))
+	}
+
+	logger.Info(strings.Repeat("=", 60))
+	logger.Info("SNAPSHOTCOPY SUMMARY")
+	logger.Info(strings.Repeat("=", 60))
+	for _, line := range copied {
+		logger.Info("  ✓ " + line)
+	}
+	for _, line := range skipped {
+		logger.Info("  - " + line)
+	}
+	for _, line := range details {
+		logger.Info("  ✗ " + strings.TrimPrefix(line, "- "))
+	}
+	logger.Info(strings.Repeat("=", 60))
+
+	if len(failed) == 0 {
+		return nil
+	}
+	if notifier := utils.NewNotifier(cfg); notifier != nil {
+		response, err := notifier.Notify(alerts.NewSnapshotCopyFailedEvent(failed, details))
+		if err != nil {
+			logger.WithField("error", err).Error("Failed to send alert")
+		} else {
+			logger.WithFields(logging.Fields{"type": "SnapshotCopyFailed", "count": len(failed), "notifiers": notifier.Name(), "response": response}).Info("Alert sent successfully")
+		}
+	} else {
+		logger.Warn("No alert notifier is configured (alert-notifiers) — alert skipped")
+	}
+	return fmt.Errorf("snapshot copy failed for %d snapshots", len(failed))
+}
+
+func snapshotCopyRepos(tasks []snapshotCopyTask) []string {
+	seen := make(map[string]bool)
+	var repos []string
+	for _, t := range tasks {
+		for _, repo := range []string{t.sourceRepo, t.secondaryRepo} {
+			if !t.clone && !seen[repo] {
+				seen[repo] = true
+				repos = append(repos, repo)
+			}
+		}
+	}
+	sort.Strings(repos)
+	return repos
+}
+
+func cloneSnapshotCopy(client *opensearch.Client, t snapshotCopyTask, timeout time.Duration, logger *logging.Logger) error {
+	logger.WithFields(logging.Fields{"repo": t.secondaryRepo, "snapshot": t.snapshot.Snapshot, "target": t.copyName, "indices": len(t.snapshot.Indices)}).Info("Cloning snapshot")
+	if err := client.CloneSnapshot(t.secondaryRepo, t.snapshot.Snapshot, t.copyName, t.snapshot.Indices); err != nil {
+		return fmt.Errorf("clone request failed: %v", err)
+	}
+	return utils.WaitForSnapshotInRepo(client, t.secondaryRepo, t.copyName, 30*time.Second, timeout, logger)
+}
+
+func resnapshotCopy(scratch *opensearch.Client, t snapshotCopyTask, timeout time.Duration, logger *logging.Logger) error {
+	var indices []string
+	for _, idx := range t.snapshot.Indices {
+		if !strings.HasPrefix(idx, ".") {
+			indices = append(indices, idx)
+		}
+	}
+	if len(indices) == 0 {
+		return fmt.Errorf("snapshot has no indices to copy")
+	}
+
+	for _, idx := range indices {
+		if exists, err := scratch.IndexExists(idx); err == nil && exists {
+			logger.WithField("index", idx).Warn("Index left on scratch cluster from a previous run, deleting")
+			if err := scratch.DeleteIndex(idx); err != nil {
+				return fmt.Errorf("failed to delete stale scratch index %s: %v", idx, err)
+			}
+		}
+	}
+
+	logger.WithFields(logging.Fields{"repo": t.sourceRepo, "snapshot": t.snapshot.Snapshot, "indices": len(indices)}).Info("Restoring snapshot on scratch cluster")
+	if err := scratch.RestoreSnapshot(t.sourceRepo, t.snapshot.Snapshot, utils.RestoreBodyForIndices(indices)); err != nil {
+		return fmt.Errorf("restore request failed: %v", err)
+	}
+	defer func() {
+		if err := scratch.DeleteIndices(indices); err != nil {
+			logger.WithFields(logging.Fields{"snapshot": t.snapshot.Snapshot, "error": err}).Error("Failed to delete scratch indices")
+		} else {
+			logger.WithFields(logging.Fields{"snapshot": t.snapshot.Snapshot, "count": len(indices)}).Info("Scratch indices deleted")
+		}
+	}()
+	for _, idx := range indices {
+		if err := utils.WaitForVerifyRestore(scratch, idx, 30*time.Second, timeout, logger); err != nil {
+			return err
+		}
+	}
+
+	logger.WithFields(logging.Fields{"repo": t.secondaryRepo, "snapshot": t.copyName}).Info("Creating snapshot on scratch cluster")
+	body := map[string]any{
+		"indices":              strings.Join(indices, ","),
+		"ignore_unavailable":   false,
+		"include_global_state": false,
+	}
+	if len(t.snapshot.Metadata) > 0 {
+		body["metadata"] = t.snapshot.Metadata
+	}
+	if err := scratch.CreateSnapshot(t.secondaryRepo, t.copyName, body); err != nil {
+		return fmt.Errorf("snapshot request failed: %v", err)
+	}
+	return utils.WaitForSnapshotInRepo(scratch, t.secondaryRepo, t.copyName, 30*time.Second, timeout, logger)
+}
@@ -3,10 +3,11 @@ package commands
 import (
 	"fmt"
 	"math/rand"
-	"osctl/pkg/alerts"
 	"osctl/pkg/config"
 	"osctl/pkg/logging"
 	"osctl/pkg/opensearch"
+	"osctl/pkg/report"
+	"osctl/pkg/state"
 	"osctl/pkg/utils"
 	"sort"
 	"strconv"
@@ -42,18 +43,19 @@ func runSnapshot(cmd *cobra.Command, args []string) error {
 
 	unknownConfig := cfg.GetOsctlIndicesUnknownConfig()
 
-	logger.Info(fmt.Sprintf("Starting snapshot creation indicesCount=%d unknownSnapshot=%t", len(indicesConfig), unknownConfig.Snapshot))
+	logger.WithFields(logging.Fields{"indicesCount": len(indicesConfig), "unknownSnapshot": unknownConfig.Snapshot}).Info("Starting snapshot creation")
 
 	client, err := utils.NewOSClientWithURL(cfg, cfg.GetOpenSearchURL())
 	if err != nil {
 		return fmt.Errorf("failed to create OpenSearch client: %v", err)
 	}
 
-	var madisonClient *alerts.Client
-	if cfg.GetMadisonKey() != "" && cfg.GetOSDURL() != "" && cfg.GetMadisonURL() != "" {
-		madisonClient = alerts.NewMadisonClient(cfg.GetMadisonKey(), cfg.GetOSDURL(), cfg.GetMadisonURL())
+	if err := utils.CheckSnapshotRepositories(client, utils.SnapshotRepositoriesFor(defaultRepo, indicesConfig), logger); err != nil {
+		return err
 	}
 
+	notifier := utils.NewNotifier(cfg)
+
 	yesterday := utils.FormatDate(time.Now().AddDate(0, 0, -1), cfg.GetDateFormat())
 	today := utils.FormatDate(time.Now(), cfg.GetDateFormat())
 
@@ -80,7 +82,7 @@ func runSnapshot(cmd *cobra.Command, args []string) error {
 		}
 		sysNames := utils.IndexInfosToNames(allSystemIndices)
 		if len(sysNames) > 0 {
-			logger.Info(fmt.Sprintf("Found system indices %s", strings.Join(sysNames, ", ")))
+			logger.WithField("indices", strings.Join(sysNames, ", ")).Info("Found system indices")
 		} else {
 			logger.Info("Found system indices none")
 		}
@@ -104,7 +106,7 @@ func runSnapshot(cmd *cobra.Command, args []string) error {
 		}
 		regNames := utils.IndexInfosToNames(allRegularIndices)
 		if len(regNames) > 0 {
-			logger.Info(fmt.Sprintf("Found regular indices %s", strings.Join(regNames, ", ")))
+			logger.WithField("indices", strings.Join(regNames, ", ")).Info("Found regular indices")
 		} else {
 			logger.Info("Found regular indices none")
 		}
@@ -127,7 +129,7 @@ func runSnapshot(cmd *cobra.Command, args []string) error {
 
 	snapshotGroups := utils.GroupIndicesForSnapshots(indicesToSnapshot, indicesConfig, today)
 	if len(indicesToSnapshot) > 0 {
-		logger.Info(fmt.Sprintf("Indices to snapshot %s", strings.Join(indicesToSnapshot, ", ")))
+		logger.WithField("indices", strings.Join(indicesToSnapshot, ", ")).Info("Indices to snapshot")
 	} else {
 		logger.Info("Indices to snapshot none")
 	}
@@ -136,7 +138,7 @@ func runSnapshot(cmd *cobra.Command, args []string) error {
 		for k := range repoGroups {
 			repoKeys = append(repoKeys, k)
 		}
-		logger.Info(fmt.Sprintf("Repo-specific snapshot groups count=%d keys=%s", len(repoGroups), strings.Join(repoKeys, ", ")))
+		logger.WithFields(logging.Fields{"count": len(repoGroups), "keys": strings.Join(repoKeys, ", ")}).Info("Repo-specific snapshot groups")
 	}
 
 	if unknownConfig.Snapshot && !unknownConfig.ManualSnapshot && len(unknownIndices) > 0 {
@@ -168,13 +170,13 @@ func runSnapshot(cmd *cobra.Command, args []string) error {
 			existingMain = []opensearch.Snapshot{}
 		}
 		filteredMain := make([]utils.SnapshotGroup, 0, len(snapshotGroups))
-		inProgressMain := make([]string, 0)
+		inProgressMain := make([]logging.Fields, 0)
 		for _, g := range snapshotGroups {
 			if state, ok := utils.GetSnapshotStateByName(g.SnapshotName, existingMain); ok && state == "SUCCESS" {
 				continue
 			}
 			if state, ok := utils.GetSnapshotStateByName(g.SnapshotName, existingMain); ok && state == "IN_PROGRESS" {
-				inProgressMain = append(inProgressMain, fmt.Sprintf("repo=%s snapshot=%s", defaultRepo, g.SnapshotName))
+				inProgressMain = append(inProgressMain, logging.Fields{"repo": defaultRepo, "snapshot": g.SnapshotName})
 				continue
 			}
 			filteredMain = append(filteredMain, g)
@@ -187,7 +189,7 @@ func runSnapshot(cmd *cobra.Command, args []string) error {
 			perRepo[repo] = append(perRepo[repo], g)
 		}
 		filteredPerRepo := map[string][]utils.SnapshotGroup{}
-		inProgressPerRepo := make([]string, 0)
+		inProgressPerRepo := make([]logging.Fields, 0)
 		for repo, groups := range perRepo {
 			existing, err := utils.GetSnapshotsIgnore404(client, repo, "*"+today+"*")
 			if err != nil {
@@ -201,7 +203,7 @@ func runSnapshot(cmd *cobra.Command, args []string) error {
 					continue
 				}
 				if state, ok := utils.GetSnapshotStateByName(g.SnapshotName, existing); ok && state == "IN_PROGRESS" {
-					inProgressPerRepo = append(inProgressPerRepo, fmt.Sprintf("repo=%s snapshot=%s", repo, g.SnapshotName))
+					inProgressPerRepo = append(inProgressPerRepo, logging.Fields{"repo": repo, "snapshot": g.SnapshotName})
 					continue
 				}
 				filteredPerRepo[repo] = append(filteredPerRepo[repo], g)
@@ -214,23 +216,18 @@ func runSnapshot(cmd *cobra.Command, args []string) error {
 		if len(inProgressMain)+len(inProgressPerRepo) > 0 {
 			logger.Info("")
 			logger.Info("Currently IN_PROGRESS snapshots:")
-			for _, msg := range inProgressMain {
-				logger.Info(fmt.Sprintf("  %s", msg))
+			for _, fields := range inProgressMain {
+				logger.WithFields(fields).Info("  IN_PROGRESS")
 			}
-			for _, msg := range inProgressPerRepo {
-				logger.Info(fmt.Sprintf("  %s", msg))
+			for _, fields := range inProgressPerRepo {
+				logger.WithFields(fields).Info("  IN_PROGRESS")
 			}
 			logger.Info("=" + strings.Repeat("=", 30))
 		}
 
 		for i, group := range filteredMain {
 			logger.Info("")
-			logger.Info(fmt.Sprintf("Snapshot %d (repo %s): %s", i+1, defaultRepo, group.SnapshotName))
-			logger.Info(fmt.Sprintf("Pattern: %s (%s)", group.Pattern, group.Kind))
-			logger.Info(fmt.Sprintf("Indices (%d):", len(group.Indices)))
-			for _, index := range group.Indices {
-				logger.Info(fmt.Sprintf("  %s", index))
-			}
+			logger.WithFields(logging.Fields{"repo": defaultRepo, "snapshot": group.SnapshotName, "pattern": group.Pattern, "kind": group.Kind, "count": len(group.Indices), "indices": strings.Join(group.Indices, ", ")}).Info(fmt.Sprintf("Snapshot %d", i+1))
 			logger.Info("=" + strings.Repeat("=", 30))
 		}
 
@@ -238,20 +235,21 @@ func runSnapshot(cmd *cobra.Command, args []string) error {
 			for repo, groups := range filteredPerRepo {
 				for _, g := range groups {
 					logger.Info("")
-					logger.Info(fmt.Sprintf("Snapshot (repo %s): %s", repo, g.SnapshotName))
-					logger.Info(fmt.Sprintf("Pattern: %s (%s)", g.Pattern, g.Kind))
-					logger.Info(fmt.Sprintf("Indices (%d):", len(g.Indices)))
-					for _, index := range g.Indices {
-						logger.Info(fmt.Sprintf("  %s", index))
-					}
+					logger.WithFields(logging.Fields{"repo": repo, "snapshot": g.SnapshotName, "pattern": g.Pattern, "kind": g.Kind, "count": len(g.Indices), "indices": strings.Join(g.Indices, ", ")}).Info("Snapshot")
 					logger.Info("=" + strings.Repeat("=", 30))
 				}
 			}
 		}
 
 		total := len(filteredMain)
-		for _, groups := range filteredPerRepo {
+		for _, g := range filteredMain {
+			report.Skipped(fmt.Sprintf("%s (repo=%s)", g.SnapshotName, defaultRepo), "dry run")
+		}
+		for repo, groups := range filteredPerRepo {
 			total += len(groups)
+			for _, g := range groups {
+				report.Skipped(fmt.Sprintf("%s (repo=%s)", g.SnapshotName, repo), "dry run")
+			}
 		}
 		logger.Info("")
 		logger.Info(fmt.Sprintf("DRY RUN: Would create %d snapshots", total))
@@ -267,6 +265,9 @@ func runSnapshot(cmd *cobra.Command, args []string) error {
 		logger.Info(fmt.Sprintf("Waiting %d seconds before starting snapshot creation to distribute load", randomWaitSeconds))
 		time.Sleep(randomWaitDuration)
 
+		progress := utils.StartSnapshotProgress(utils.NewStateStore(cfg, client), "snapshots", state.ModeDaily, defaultRepo, today, logger)
+		limiter := utils.NewConcurrencyLimiter(cfg, client, "snapshot", cfg.GetMaxConcurrentSnapshots(), logger)
+
 		allSnapshots, err := utils.GetSnapshotsIgnore404(client, defaultRepo, "*"+today+"*")
 		if err != nil {
 			return fmt.Errorf("failed to get snapshots: %v", err)
@@ -276,7 +277,7 @@ func runSnapshot(cmd *cobra.Command, args []string) error {
 		}
 		existingNames := utils.SnapshotsToNames(allSnapshots)
 		if len(existingNames) > 0 {
-			logger.Info(fmt.Sprintf("Existing snapshots today %s", strings.Join(existingNames, ", ")))
+			logger.WithField("snapshots", strings.Join(existingNames, ", ")).Info("Existing snapshots today")
 		} else {
 			logger.Info("Existing snapshots today none")
 		}
@@ -304,7 +305,7 @@ func runSnapshot(cmd *cobra.Command, args []string) error {
 						}
 					}
 					if len(missingIndices) == 0 {
-						logger.Info(fmt.Sprintf("Valid snapshot already exists with all indices snapshot=%s", group.SnapshotName))
+						logger.WithField("snapshot", group.SnapshotName).Info("Valid snapshot already exists with all indices")
 						continue
 					}
 					randomSuffix := utils.GenerateRandomAlphanumericString(6)
@@ -313,7 +314,7 @@ func runSnapshot(cmd *cobra.Command, args []string) error {
 						datePart := parts[len(parts)-1]
 						baseName := strings.Join(parts[:len(parts)-1], "-")
 						newSnapshotName := baseName + "-" + randomSuffix + "-" + datePart
-						logger.Info(fmt.Sprintf("Some indices missing in existing snapshot, creating additional snapshot original=%s new=%s missingIndicesCount=%d", group.SnapshotName, newSnapshotName, len(missingIndices)))
+						logger.WithFields(logging.Fields{"original": group.SnapshotName, "new": newSnapshotName, "missingIndicesCount": len(missingIndices)}).Info("Some indices missing in existing snapshot, creating additional snapshot")
 						indicesStr := strings.Join(missingIndices, ",")
 						var totalSize int64
 						for _, idx := range missingIndices {
@@ -327,24 +328,25 @@ func runSnapshot(cmd *cobra.Command, args []string) error {
 							DateStr:      today,
 							PollInterval: 60 * time.Second,
 							Size:         totalSize,
+							Metadata:     group.Metadata("snapshots"),
 						})
 					}
 					continue
 				}
-				if state == "IN_PROGRESS" {
-					logger.Info(fmt.Sprintf("Snapshot is currently IN_PROGRESS snapshot=%s repo=%s", group.SnapshotName, defaultRepo))
+				if state == "IN_PROGRESS" && !progress.Started(defaultRepo, group.SnapshotName) {
+					logger.WithFields(logging.Fields{"snapshot": group.SnapshotName, "repo": defaultRepo}).Info("Snapshot is currently IN_PROGRESS")
 					continue
 				}
 			}
 
-			exists, err := utils.CheckAndCleanSnapshot(group.SnapshotName, strings.Join(group.Indices, ","), allSnapshots, client, defaultRepo, logger)
+			exists, err := progress.CheckAndCleanSnapshot(group.SnapshotName, strings.Join(group.Indices, ","), allSnapshots, client, defaultRepo, logger)
 			if err != nil {
-				logger.Error(fmt.Sprintf("Failed to check/clean snapshot snapshot=%s error=%v", group.SnapshotName, err))
+				logger.WithFields(logging.Fields{"snapshot": group.SnapshotName, "error": err}).Error("Failed to check/clean snapshot")
 				continue
 			}
 
 			if exists {
-				logger.Info(fmt.Sprintf("Valid snapshot already exists snapshot=%s", group.SnapshotName))
+				logger.WithField("snapshot", group.SnapshotName).Info("Valid snapshot already exists")
 				continue
 			}
 
@@ -361,11 +363,12 @@ func runSnapshot(cmd *cobra.Command, args []string) error {
 				DateStr:      today,
 				PollInterval: 60 * time.Second,
 				Size:         totalSize,
+				Metadata:     group.Metadata("snapshots"),
 			})
 		}
 
 		if len(snapshotTasks) > 0 {
-			successful, failed := utils.CreateSnapshotsInParallel(client, snapshotTasks, cfg.GetMaxConcurrentSnapshots(), madisonClient, logger, true)
+			successful, failed := utils.CreateSnapshotsInParallel(client, snapshotTasks, cfg.GetMaxConcurrentSnapshots(), notifier, logger, true, progress, limiter)
 			successfulSnapshots = append(successfulSnapshots, successful...)
 			failedSnapshots = append(failedSnapshots, failed...)
 		}
@@ -391,7 +394,7 @@ func runSnapshot(cmd *cobra.Command, args []string) error {
 				})
 				existing, err := utils.GetSnapshotsIgnore404(client, repo, "*"+today+"*")
 				if err != nil {
-					logger.Error(fmt.Sprintf("Failed to get snapshots from repo repo=%s error=%v", repo, err))
+					logger.WithFields(logging.Fields{"repo": repo, "error": err}).Error("Failed to get snapshots from repo")
 					continue
 				}
 				if existing == nil {
@@ -419,7 +422,7 @@ func runSnapshot(cmd *cobra.Command, args []string) error {
 								}
 							}
 							if len(missingIndices) == 0 {
-								logger.Info(fmt.Sprintf("Valid snapshot already exists with all indices repo=%s snapshot=%s", repo, g.SnapshotName))
+								logger.WithFields(logging.Fields{"repo": repo, "snapshot": g.SnapshotName}).Info("Valid snapshot already exists with all indices")
 								continue
 							}
 							randomSuffix := utils.GenerateRandomAlphanumericString(6)
@@ -428,7 +431,7 @@ func runSnapshot(cmd *cobra.Command, args []string) error {
 								datePart := parts[len(parts)-1]
 								baseName := strings.Join(parts[:len(parts)-1], "-")
 								newSnapshotName := baseName + "-" + randomSuffix + "-" + datePart
-								logger.Info(fmt.Sprintf("Some indices missing in existing snapshot, creating additional snapshot repo=%s original=%s new=%s missingIndicesCount=%d", repo, g.SnapshotName, newSnapshotName, len(missingIndices)))
+								logger.WithFields(logging.Fields{"repo": repo, "original": g.SnapshotName, "new": newSnapshotName, "missingIndicesCount": len(missingIndices)}).Info("Some indices missing in existing snapshot, creating additional snapshot")
 								indicesStr := strings.Join(missingIndices, ",")
 								var totalSize int64
 								for _, idx := range missingIndices {
@@ -442,22 +445,23 @@ func runSnapshot(cmd *cobra.Command, args []string) error {
 									DateStr:      today,
 									PollInterval: 60 * time.Second,
 									Size:         totalSize,
+									Metadata:     g.Metadata("snapshots"),
 								})
 							}
 							continue
 						}
-						if state == "IN_PROGRESS" {
-							logger.Info(fmt.Sprintf("Snapshot is currently IN_PROGRESS repo=%s snapshot=%s", repo, g.SnapshotName))
+						if state == "IN_PROGRESS" && !progress.Started(repo, g.SnapshotName) {
+							logger.WithFields(logging.Fields{"repo": repo, "snapshot": g.SnapshotName}).Info("Snapshot is currently IN_PROGRESS")
 							continue
 						}
 					}
-					exists, err := utils.CheckAndCleanSnapshot(g.SnapshotName, strings.Join(g.Indices, ","), existing, client, repo, logger)
+					exists, err := progress.CheckAndCleanSnapshot(g.SnapshotName, strings.Join(g.Indices, ","), existing, client, repo, logger)
 					if err != nil {
-						logger.Error(fmt.Sprintf("Failed to check/clean snapshot repo=%s snapshot=%s error=%v", repo, g.SnapshotName, err))
+						logger.WithFields(logging.Fields{"repo": repo, "snapshot": g.SnapshotName, "error": err}).Error("Failed to check/clean snapshot")
 						continue
 					}
 					if exists {
-						logger.Info(fmt.Sprintf("Valid snapshot already exists repo=%s snapshot=%s", repo, g.SnapshotName))
+						logger.WithFields(logging.Fields{"repo": repo, "snapshot": g.SnapshotName}).Info("Valid snapshot already exists")
 						continue
 					}
 					indicesStr := strings.Join(g.Indices, ",")
@@ -473,15 +477,17 @@ func runSnapshot(cmd *cobra.Command, args []string) error {
 						DateStr:      today,
 						PollInterval: 60 * time.Second,
 						Size:         totalSize,
+						Metadata:     g.Metadata("snapshots"),
 					})
 				}
 			}
 			if len(repoSnapshotTasks) > 0 {
-				successful, failed := utils.CreateSnapshotsInParallel(client, repoSnapshotTasks, cfg.GetMaxConcurrentSnapshots(), madisonClient, logger, true)
+				successful, failed := utils.CreateSnapshotsInParallel(client, repoSnapshotTasks, cfg.GetMaxConcurrentSnapshots(), notifier, logger, true, progress, limiter)
 				successfulSnapshots = append(successfulSnapshots, successful...)
 				failedSnapshots = append(failedSnapshots, failed...)
 			}
 		}
+		progress.Finish()
 	}
 
 	if !cfg.GetDryRun() {
@@ -491,14 +497,14 @@ func runSnapshot(cmd *cobra.Command, args []string) error {
 		if len(successfulSnapshots) > 0 {
 			logger.Info(fmt.Sprintf("Successfully created: %d snapshots", len(successfulSnapshots)))
 			for _, name := range successfulSnapshots {
-				logger.Info(fmt.Sprintf("  ✓ %s", name))
+				logger.WithField("snapshot", name).Info("  ✓")
 			}
 		}
 		if len(failedSnapshots) > 0 {
 			logger.Info("")
 			logger.Info(fmt.Sprintf("Failed to create: %d snapshots", len(failedSnapshots)))
 			for _, name := range failedSnapshots {
-				logger.Info(fmt.Sprintf("  ✗ %s", name))
+				logger.WithField("snapshot", name).Info("  ✗")
 			}
 		}
 		if len(successfulSnapshots) == 0 && len(failedSnapshots) == 0 {
@@ -507,6 +513,9 @@ func runSnapshot(cmd *cobra.Command, args []string) error {
 		logger.Info(strings.Repeat("=", 60))
 	}
 
+	if cfg.GetSnapshotTrends() {
+		reportSnapshotTrends(cfg, client, utils.SnapshotRepositoriesFor(defaultRepo, indicesConfig), today, notifier, logger)
+	}
 	logger.Info("Snapshot creation completed")
 	return nil
 }
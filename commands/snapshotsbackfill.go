@@ -7,6 +7,7 @@ import (
 	"osctl/pkg/config"
 	"osctl/pkg/logging"
 	"osctl/pkg/opensearch"
+	"osctl/pkg/state"
 	"osctl/pkg/utils"
 	"sort"
 	"strconv"
@@ -44,18 +45,19 @@ func runSnapshotsBackfill(cmd *cobra.Command, args []string) error {
 	unknownConfig := cfg.GetOsctlIndicesUnknownConfig()
 	s3Config := cfg.GetOsctlIndicesS3SnapshotsConfig()
 
-	logger.Info(fmt.Sprintf("Starting snapshots backfill indicesCountConfig=%d unknownSnapshot=%t", len(indicesConfig), unknownConfig.Snapshot))
+	logger.WithFields(logging.Fields{"indicesCountConfig": len(indicesConfig), "unknownSnapshot": unknownConfig.Snapshot}).Info("Starting snapshots backfill")
 
 	client, err := utils.NewOSClientWithURL(cfg, cfg.GetOpenSearchURL())
 	if err != nil {
 		return fmt.Errorf("failed to create OpenSearch client: %v", err)
 	}
 
-	var madisonClient *alerts.Client
-	if cfg.GetMadisonKey() != "" && cfg.GetOSDURL() != "" && cfg.GetMadisonURL() != "" {
-		madisonClient = alerts.NewMadisonClient(cfg.GetMadisonKey(), cfg.GetOSDURL(), cfg.GetMadisonURL())
+	if err := utils.CheckSnapshotRepositories(client, utils.SnapshotRepositoriesFor(defaultRepo, indicesConfig), logger); err != nil {
+		return err
 	}
 
+	notifier := utils.NewNotifier(cfg)
+
 	indicesListFlag := cfg.GetSnapshotsBackfillIndicesList()
 
 	var indicesToProcess []string
@@ -65,12 +67,12 @@ func runSnapshotsBackfill(cmd *cobra.Command, args []string) error {
 		for i := range indicesToProcess {
 			indicesToProcess[i] = strings.TrimSpace(indicesToProcess[i])
 		}
-		logger.Info(fmt.Sprintf("Processing indices from --indices-list count=%d", len(indicesToProcess)))
+		logger.WithField("count", len(indicesToProcess)).Info("Processing indices from --indices-list")
 	} else {
 		yesterday := utils.FormatDate(time.Now().AddDate(0, 0, -1), cfg.GetDateFormat())
 		dayBeforeYesterday := utils.FormatDate(time.Now().AddDate(0, 0, -2), cfg.GetDateFormat())
 
-		logger.Info(fmt.Sprintf("Getting all indices excluding today and yesterday today=%s yesterday=%s", today, yesterday))
+		logger.WithFields(logging.Fields{"today": today, "yesterday": yesterday}).Info("Getting all indices excluding today and yesterday")
 
 		allIndices, err := client.GetIndicesWithFields("*", "index")
 		if err != nil {
@@ -110,9 +112,9 @@ func runSnapshotsBackfill(cmd *cobra.Command, args []string) error {
 			}
 		}
 
-		logger.Info(fmt.Sprintf("Found indices to process count=%d", len(indicesToProcess)))
+		logger.WithField("count", len(indicesToProcess)).Info("Found indices to process")
 		if len(indicesToProcess) > 0 {
-			logger.Info(fmt.Sprintf("Indices to process %s", strings.Join(indicesToProcess, ", ")))
+			logger.WithField("indices", strings.Join(indicesToProcess, ", ")).Info("Indices to process")
 		}
 	}
 
@@ -121,7 +123,7 @@ func runSnapshotsBackfill(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	logger.Info(fmt.Sprintf("Getting all snapshots from repository repo=%s", defaultRepo))
+	logger.WithField("repo", defaultRepo).Info("Getting all snapshots from repository")
 	allSnapshots, err := utils.GetSnapshotsIgnore404(client, defaultRepo, "*")
 	if err != nil {
 		return fmt.Errorf("failed to get snapshots: %v", err)
@@ -137,7 +139,7 @@ func runSnapshotsBackfill(cmd *cobra.Command, args []string) error {
 		}
 	}
 	if len(snapshotNames) > 0 {
-		logger.Info(fmt.Sprintf("Found successful snapshots count=%d", len(snapshotNames)))
+		logger.WithField("count", len(snapshotNames)).Info("Found successful snapshots")
 	} else {
 		logger.Info("Found snapshots none")
 	}
@@ -149,7 +151,7 @@ func runSnapshotsBackfill(cmd *cobra.Command, args []string) error {
 		hasDate := utils.HasDateInName(indexName, cfg.GetDateFormat())
 		if !hasDate {
 			indicesWithoutDate = append(indicesWithoutDate, indexName)
-			logger.Warn(fmt.Sprintf("Skipping index without date in name index=%s", indexName))
+			logger.WithField("index", indexName).Warn("Skipping index without date in name")
 			continue
 		}
 
@@ -159,7 +161,7 @@ func runSnapshotsBackfill(cmd *cobra.Command, args []string) error {
 	}
 
 	if len(indicesWithoutDate) > 0 {
-		logger.Info(fmt.Sprintf("Skipped indices without date count=%d list=%s", len(indicesWithoutDate), strings.Join(indicesWithoutDate, ", ")))
+		logger.WithFields(logging.Fields{"count": len(indicesWithoutDate), "list": strings.Join(indicesWithoutDate, ", ")}).Info("Skipped indices without date")
 	}
 
 	if len(indicesWithoutSnapshots) == 0 {
@@ -167,9 +169,9 @@ func runSnapshotsBackfill(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	logger.Info(fmt.Sprintf("Indices without snapshots count=%d", len(indicesWithoutSnapshots)))
+	logger.WithField("count", len(indicesWithoutSnapshots)).Info("Indices without snapshots")
 	if len(indicesWithoutSnapshots) > 0 {
-		logger.Info(fmt.Sprintf("Indices without snapshots list=%s", strings.Join(indicesWithoutSnapshots, ", ")))
+		logger.WithField("list", strings.Join(indicesWithoutSnapshots, ", ")).Info("Indices without snapshots")
 	}
 
 	dateGroups := utils.GroupIndicesByDate(indicesWithoutSnapshots, cfg.GetDateFormat())
@@ -179,20 +181,23 @@ func runSnapshotsBackfill(cmd *cobra.Command, args []string) error {
 	}
 	sort.Strings(dateKeys)
 
-	logger.Info(fmt.Sprintf("Grouped indices by date groups=%d", len(dateGroups)))
+	logger.WithField("groups", len(dateGroups)).Info("Grouped indices by date")
 	for _, dateKey := range dateKeys {
 		indicesForDate := dateGroups[dateKey]
-		logger.Info(fmt.Sprintf("Date group date=%s indicesCount=%d indices=%s", dateKey, len(indicesForDate), strings.Join(indicesForDate, ", ")))
+		logger.WithFields(logging.Fields{"date": dateKey, "indicesCount": len(indicesForDate), "indices": strings.Join(indicesForDate, ", ")}).Info("Date group")
 	}
 
 	var totalSnapshotsToCreate int
 	var allSnapshotsToCreate []utils.SnapshotGroup
 	var successfulSnapshots []string
 	var failedSnapshots []string
+	var backfilledIndices []string
+	var progress *utils.SnapshotProgress
+	limiter := utils.NewConcurrencyLimiter(cfg, client, "snapshot", cfg.GetMaxConcurrentSnapshots(), logger)
 
 	for _, dateKey := range dateKeys {
 		indicesForDate := dateGroups[dateKey]
-		logger.Info(fmt.Sprintf("Processing date group date=%s indicesCount=%d", dateKey, len(indicesForDate)))
+		logger.WithFields(logging.Fields{"date": dateKey, "indicesCount": len(indicesForDate)}).Info("Processing date group")
 
 		indicesSet := make(map[string]bool)
 		for _, idx := range indicesForDate {
@@ -203,7 +208,7 @@ func runSnapshotsBackfill(cmd *cobra.Command, args []string) error {
 		pattern := "*" + dateKey + "*"
 		indicesWithSize, err := client.GetIndicesWithFields(pattern, "index,ss", "ss:asc")
 		if err != nil {
-			logger.Warn(fmt.Sprintf("Failed to get indices with size for date date=%s error=%v, using unsorted list", dateKey, err))
+			logger.WithFields(logging.Fields{"date": dateKey, "error": err}).Warn("Failed to get indices with size for date, using unsorted list")
 		} else {
 			var sortedIndices []string
 			for _, idx := range indicesWithSize {
@@ -226,7 +231,7 @@ func runSnapshotsBackfill(cmd *cobra.Command, args []string) error {
 		goFormat := utils.ConvertDateFormat(cfg.GetDateFormat())
 		parsedDate, err := time.Parse(goFormat, dateKey)
 		if err != nil {
-			logger.Error(fmt.Sprintf("Failed to parse date date=%s error=%v", dateKey, err))
+			logger.WithFields(logging.Fields{"date": dateKey, "error": err}).Error("Failed to parse date")
 			continue
 		}
 		snapshotDate := utils.FormatDate(parsedDate.AddDate(0, 0, 1), cfg.GetDateFormat())
@@ -239,11 +244,11 @@ func runSnapshotsBackfill(cmd *cobra.Command, args []string) error {
 			indexConfig := utils.FindMatchingIndexConfig(indexName, indicesConfig)
 			if indexConfig != nil {
 				if !indexConfig.Snapshot {
-					logger.Info(fmt.Sprintf("Skipping index: snapshot disabled in config index=%s", indexName))
+					logger.WithField("index", indexName).Info("Skipping index: snapshot disabled in config")
 					continue
 				}
 				if indexConfig.ManualSnapshot {
-					logger.Info(fmt.Sprintf("Skipping index: manual snapshot in config index=%s", indexName))
+					logger.WithField("index", indexName).Info("Skipping index: manual snapshot in config")
 					continue
 				}
 
@@ -261,7 +266,7 @@ func runSnapshotsBackfill(cmd *cobra.Command, args []string) error {
 				cutoffDate := utils.GetLaterCutoffDate(cutoffDateDaysCount, cutoffDateS3, cfg.GetDateFormat())
 
 				if utils.IsOlderThanCutoff(indexName, cutoffDate, cfg.GetDateFormat()) {
-					logger.Info(fmt.Sprintf("Skipping index older than cutoff index=%s cutoff=%s", indexName, cutoffDate))
+					logger.WithFields(logging.Fields{"index": indexName, "cutoff": cutoffDate}).Info("Skipping index older than cutoff")
 					continue
 				}
 
@@ -288,7 +293,7 @@ func runSnapshotsBackfill(cmd *cobra.Command, args []string) error {
 				if !utils.IsOlderThanCutoff(idx, cutoffDate, cfg.GetDateFormat()) {
 					filteredUnknown = append(filteredUnknown, idx)
 				} else {
-					logger.Info(fmt.Sprintf("Skipping unknown index older than cutoff index=%s cutoff=%s", idx, cutoffDate))
+					logger.WithFields(logging.Fields{"index": idx, "cutoff": cutoffDate}).Info("Skipping unknown index older than cutoff")
 				}
 			}
 			unknownIndices = filteredUnknown
@@ -332,7 +337,7 @@ func runSnapshotsBackfill(cmd *cobra.Command, args []string) error {
 			return sizeI < sizeJ
 		})
 		if len(snapshotGroups) == 0 && len(repoGroups) == 0 {
-			logger.Info(fmt.Sprintf("No snapshots to create for date date=%s", dateKey))
+			logger.WithField("date", dateKey).Info("No snapshots to create for date")
 			continue
 		}
 
@@ -345,7 +350,7 @@ func runSnapshotsBackfill(cmd *cobra.Command, args []string) error {
 				existingMain = []opensearch.Snapshot{}
 			}
 			filteredMain := make([]utils.SnapshotGroup, 0, len(snapshotGroups))
-			inProgressMain := make([]string, 0)
+			inProgressMain := make([]logging.Fields, 0)
 			for _, g := range snapshotGroups {
 				state, ok := utils.GetSnapshotStateByName(g.SnapshotName, existingMain)
 				if ok && state == "SUCCESS" {
@@ -386,7 +391,7 @@ func runSnapshotsBackfill(cmd *cobra.Command, args []string) error {
 					continue
 				}
 				if ok && state == "IN_PROGRESS" {
-					inProgressMain = append(inProgressMain, fmt.Sprintf("repo=%s snapshot=%s", defaultRepo, g.SnapshotName))
+					inProgressMain = append(inProgressMain, logging.Fields{"repo": defaultRepo, "snapshot": g.SnapshotName})
 					continue
 				}
 				filteredMain = append(filteredMain, g)
@@ -399,7 +404,7 @@ func runSnapshotsBackfill(cmd *cobra.Command, args []string) error {
 				perRepo[repo] = append(perRepo[repo], g)
 			}
 			filteredPerRepo := map[string][]utils.SnapshotGroup{}
-			inProgressPerRepo := make([]string, 0)
+			inProgressPerRepo := make([]logging.Fields, 0)
 			for repo, groups := range perRepo {
 				sort.Slice(groups, func(i, j int) bool {
 					var sizeI, sizeJ int64
@@ -458,7 +463,7 @@ func runSnapshotsBackfill(cmd *cobra.Command, args []string) error {
 						continue
 					}
 					if ok && state == "IN_PROGRESS" {
-						inProgressPerRepo = append(inProgressPerRepo, fmt.Sprintf("repo=%s snapshot=%s", repo, g.SnapshotName))
+						inProgressPerRepo = append(inProgressPerRepo, logging.Fields{"repo": repo, "snapshot": g.SnapshotName})
 						continue
 					}
 					filteredPerRepo[repo] = append(filteredPerRepo[repo], g)
@@ -472,28 +477,23 @@ func runSnapshotsBackfill(cmd *cobra.Command, args []string) error {
 
 			logger.Info("DRY RUN: Snapshot creation plan")
 			logger.Info("=" + strings.Repeat("=", 50))
-			logger.Info(fmt.Sprintf("Index date: %s, Snapshot date: %s", dateKey, snapshotDate))
+			logger.WithFields(logging.Fields{"indexDate": dateKey, "snapshotDate": snapshotDate}).Info("Backfill dates")
 
 			if len(inProgressMain)+len(inProgressPerRepo) > 0 {
 				logger.Info("")
 				logger.Info("Currently IN_PROGRESS snapshots:")
-				for _, msg := range inProgressMain {
-					logger.Info(fmt.Sprintf("  %s", msg))
+				for _, fields := range inProgressMain {
+					logger.WithFields(fields).Info("  IN_PROGRESS")
 				}
-				for _, msg := range inProgressPerRepo {
-					logger.Info(fmt.Sprintf("  %s", msg))
+				for _, fields := range inProgressPerRepo {
+					logger.WithFields(fields).Info("  IN_PROGRESS")
 				}
 				logger.Info("=" + strings.Repeat("=", 30))
 			}
 
 			for i, group := range filteredMain {
 				logger.Info("")
-				logger.Info(fmt.Sprintf("Snapshot %d (repo %s): %s", i+1, defaultRepo, group.SnapshotName))
-				logger.Info(fmt.Sprintf("Pattern: %s (%s)", group.Pattern, group.Kind))
-				logger.Info(fmt.Sprintf("Indices (%d):", len(group.Indices)))
-				for _, index := range group.Indices {
-					logger.Info(fmt.Sprintf("  %s", index))
-				}
+				logger.WithFields(logging.Fields{"repo": defaultRepo, "snapshot": group.SnapshotName, "pattern": group.Pattern, "kind": group.Kind, "count": len(group.Indices), "indices": strings.Join(group.Indices, ", ")}).Info(fmt.Sprintf("Snapshot %d", i+1))
 				logger.Info("=" + strings.Repeat("=", 30))
 			}
 
@@ -501,19 +501,14 @@ func runSnapshotsBackfill(cmd *cobra.Command, args []string) error {
 				for repo, groups := range filteredPerRepo {
 					for _, g := range groups {
 						logger.Info("")
-						logger.Info(fmt.Sprintf("Snapshot (repo %s): %s", repo, g.SnapshotName))
-						logger.Info(fmt.Sprintf("Pattern: %s (%s)", g.Pattern, g.Kind))
-						logger.Info(fmt.Sprintf("Indices (%d):", len(g.Indices)))
-						for _, index := range g.Indices {
-							logger.Info(fmt.Sprintf("  %s", index))
-						}
+						logger.WithFields(logging.Fields{"repo": repo, "snapshot": g.SnapshotName, "pattern": g.Pattern, "kind": g.Kind, "count": len(g.Indices), "indices": strings.Join(g.Indices, ", ")}).Info("Snapshot")
 						logger.Info("=" + strings.Repeat("=", 30))
 					}
 				}
 			}
 
 			logger.Info("")
-			logger.Info(fmt.Sprintf("DRY RUN: Would create %d snapshots for index date %s (snapshot date %s)", total, dateKey, snapshotDate))
+			logger.WithFields(logging.Fields{"count": total, "indexDate": dateKey, "snapshotDate": snapshotDate}).Info("DRY RUN: Would create snapshots for index date")
 
 			totalSnapshotsToCreate += total
 			allSnapshotsToCreate = append(allSnapshotsToCreate, filteredMain...)
@@ -530,10 +525,13 @@ func runSnapshotsBackfill(cmd *cobra.Command, args []string) error {
 				logger.Info(fmt.Sprintf("Waiting %d seconds before starting snapshot creation to distribute load", randomWaitSeconds))
 				time.Sleep(randomWaitDuration)
 			}
+			if progress == nil {
+				progress = utils.StartSnapshotProgress(utils.NewStateStore(cfg, client), "snapshotsbackfill", state.ModeDaily, defaultRepo, today, logger)
+			}
 
 			allSnapshotsForDate, err := utils.GetSnapshotsIgnore404(client, defaultRepo, "*"+snapshotDate+"*")
 			if err != nil {
-				logger.Error(fmt.Sprintf("Failed to get snapshots for date date=%s error=%v", dateKey, err))
+				logger.WithFields(logging.Fields{"date": dateKey, "error": err}).Error("Failed to get snapshots for date")
 				continue
 			}
 			if allSnapshotsForDate == nil {
@@ -563,7 +561,7 @@ func runSnapshotsBackfill(cmd *cobra.Command, args []string) error {
 							}
 						}
 						if len(missingIndices) == 0 {
-							logger.Info(fmt.Sprintf("Valid snapshot already exists with all indices snapshot=%s", group.SnapshotName))
+							logger.WithField("snapshot", group.SnapshotName).Info("Valid snapshot already exists with all indices")
 							continue
 						}
 						randomSuffix := utils.GenerateRandomAlphanumericString(6)
@@ -572,7 +570,7 @@ func runSnapshotsBackfill(cmd *cobra.Command, args []string) error {
 							datePart := parts[len(parts)-1]
 							baseName := strings.Join(parts[:len(parts)-1], "-")
 							newSnapshotName := baseName + "-" + randomSuffix + "-" + datePart
-							logger.Info(fmt.Sprintf("Some indices missing in existing snapshot, creating additional snapshot original=%s new=%s missingIndicesCount=%d", group.SnapshotName, newSnapshotName, len(missingIndices)))
+							logger.WithFields(logging.Fields{"original": group.SnapshotName, "new": newSnapshotName, "missingIndicesCount": len(missingIndices)}).Info("Some indices missing in existing snapshot, creating additional snapshot")
 							indicesStr := strings.Join(missingIndices, ",")
 							var totalSize int64
 							for _, idx := range missingIndices {
@@ -586,24 +584,25 @@ func runSnapshotsBackfill(cmd *cobra.Command, args []string) error {
 								DateStr:      today,
 								PollInterval: 10 * time.Minute,
 								Size:         totalSize,
+								Metadata:     group.Metadata("snapshotsbackfill"),
 							})
 						}
 						continue
 					}
-					if state == "IN_PROGRESS" {
-						logger.Info(fmt.Sprintf("Snapshot is currently IN_PROGRESS snapshot=%s repo=%s", group.SnapshotName, defaultRepo))
+					if state == "IN_PROGRESS" && !progress.Started(defaultRepo, group.SnapshotName) {
+						logger.WithFields(logging.Fields{"snapshot": group.SnapshotName, "repo": defaultRepo}).Info("Snapshot is currently IN_PROGRESS")
 						continue
 					}
 				}
 
-				exists, err := utils.CheckAndCleanSnapshot(group.SnapshotName, strings.Join(group.Indices, ","), allSnapshotsForDate, client, defaultRepo, logger)
+				exists, err := progress.CheckAndCleanSnapshot(group.SnapshotName, strings.Join(group.Indices, ","), allSnapshotsForDate, client, defaultRepo, logger)
 				if err != nil {
-					logger.Error(fmt.Sprintf("Failed to check/clean snapshot snapshot=%s error=%v", group.SnapshotName, err))
+					logger.WithFields(logging.Fields{"snapshot": group.SnapshotName, "error": err}).Error("Failed to check/clean snapshot")
 					continue
 				}
 
 				if exists {
-					logger.Info(fmt.Sprintf("Valid snapshot already exists snapshot=%s", group.SnapshotName))
+					logger.WithField("snapshot", group.SnapshotName).Info("Valid snapshot already exists")
 					continue
 				}
 
@@ -620,12 +619,14 @@ func runSnapshotsBackfill(cmd *cobra.Command, args []string) error {
 					DateStr:      today,
 					PollInterval: 10 * time.Minute,
 					Size:         totalSize,
+					Metadata:     group.Metadata("snapshotsbackfill"),
 				})
 			}
 
 			if len(snapshotTasks) > 0 {
-				successful, failed := utils.CreateSnapshotsInParallel(client, snapshotTasks, cfg.GetMaxConcurrentSnapshots(), madisonClient, logger, false)
+				successful, failed := utils.CreateSnapshotsInParallel(client, snapshotTasks, cfg.GetMaxConcurrentSnapshots(), notifier, logger, false, progress, limiter)
 				successfulSnapshots = append(successfulSnapshots, successful...)
+				backfilledIndices = append(backfilledIndices, utils.SucceededTaskIndices(snapshotTasks, successful)...)
 				failedSnapshots = append(failedSnapshots, failed...)
 			}
 
@@ -650,7 +651,7 @@ func runSnapshotsBackfill(cmd *cobra.Command, args []string) error {
 					})
 					existing, err := utils.GetSnapshotsIgnore404(client, repo, "*"+snapshotDate+"*")
 					if err != nil {
-						logger.Error(fmt.Sprintf("Failed to get snapshots from repo repo=%s error=%v", repo, err))
+						logger.WithFields(logging.Fields{"repo": repo, "error": err}).Error("Failed to get snapshots from repo")
 						continue
 					}
 					if existing == nil {
@@ -678,7 +679,7 @@ func runSnapshotsBackfill(cmd *cobra.Command, args []string) error {
 									}
 								}
 								if len(missingIndices) == 0 {
-									logger.Info(fmt.Sprintf("Valid snapshot already exists with all indices repo=%s snapshot=%s", repo, g.SnapshotName))
+									logger.WithFields(logging.Fields{"repo": repo, "snapshot": g.SnapshotName}).Info("Valid snapshot already exists with all indices")
 									continue
 								}
 								randomSuffix := utils.GenerateRandomAlphanumericString(6)
@@ -687,7 +688,7 @@ func runSnapshotsBackfill(cmd *cobra.Command, args []string) error {
 									datePart := parts[len(parts)-1]
 									baseName := strings.Join(parts[:len(parts)-1], "-")
 									newSnapshotName := baseName + "-" + randomSuffix + "-" + datePart
-									logger.Info(fmt.Sprintf("Some indices missing in existing snapshot, creating additional snapshot repo=%s original=%s new=%s missingIndicesCount=%d", repo, g.SnapshotName, newSnapshotName, len(missingIndices)))
+									logger.WithFields(logging.Fields{"repo": repo, "original": g.SnapshotName, "new": newSnapshotName, "missingIndicesCount": len(missingIndices)}).Info("Some indices missing in existing snapshot, creating additional snapshot")
 									indicesStr := strings.Join(missingIndices, ",")
 									var totalSize int64
 									for _, idx := range missingIndices {
@@ -701,22 +702,23 @@ func runSnapshotsBackfill(cmd *cobra.Command, args []string) error {
 										DateStr:      today,
 										PollInterval: 10 * time.Minute,
 										Size:         totalSize,
+										Metadata:     g.Metadata("snapshotsbackfill"),
 									})
 								}
 								continue
 							}
-							if state == "IN_PROGRESS" {
-								logger.Info(fmt.Sprintf("Snapshot is currently IN_PROGRESS repo=%s snapshot=%s", repo, g.SnapshotName))
+							if state == "IN_PROGRESS" && !progress.Started(repo, g.SnapshotName) {
+								logger.WithFields(logging.Fields{"repo": repo, "snapshot": g.SnapshotName}).Info("Snapshot is currently IN_PROGRESS")
 								continue
 							}
 						}
-						exists, err := utils.CheckAndCleanSnapshot(g.SnapshotName, strings.Join(g.Indices, ","), existing, client, repo, logger)
+						exists, err := progress.CheckAndCleanSnapshot(g.SnapshotName, strings.Join(g.Indices, ","), existing, client, repo, logger)
 						if err != nil {
-							logger.Error(fmt.Sprintf("Failed to check/clean snapshot repo=%s snapshot=%s error=%v", repo, g.SnapshotName, err))
+							logger.WithFields(logging.Fields{"repo": repo, "snapshot": g.SnapshotName, "error": err}).Error("Failed to check/clean snapshot")
 							continue
 						}
 						if exists {
-							logger.Info(fmt.Sprintf("Valid snapshot already exists repo=%s snapshot=%s", repo, g.SnapshotName))
+							logger.WithFields(logging.Fields{"repo": repo, "snapshot": g.SnapshotName}).Info("Valid snapshot already exists")
 							continue
 						}
 						indicesStr := strings.Join(g.Indices, ",")
@@ -732,33 +734,39 @@ func runSnapshotsBackfill(cmd *cobra.Command, args []string) error {
 							DateStr:      today,
 							PollInterval: 10 * time.Minute,
 							Size:         totalSize,
+							Metadata:     g.Metadata("snapshotsbackfill"),
 						})
 					}
 				}
 				if len(repoSnapshotTasks) > 0 {
-					successful, failed := utils.CreateSnapshotsInParallel(client, repoSnapshotTasks, cfg.GetMaxConcurrentSnapshots(), madisonClient, logger, false)
+					successful, failed := utils.CreateSnapshotsInParallel(client, repoSnapshotTasks, cfg.GetMaxConcurrentSnapshots(), notifier, logger, false, progress, limiter)
 					successfulSnapshots = append(successfulSnapshots, successful...)
+					backfilledIndices = append(backfilledIndices, utils.SucceededTaskIndices(repoSnapshotTasks, successful)...)
 					failedSnapshots = append(failedSnapshots, failed...)
 				}
 			}
 		}
 	}
 
+	progress.Finish()
+
 	if !cfg.GetDryRun() {
+		utils.ResolveAlertSubjects(notifier, alerts.SnapshotsMissing, backfilledIndices, logger)
+		utils.ResolveAlertSubjects(notifier, alerts.SnapshotCreationFailed, backfilledIndices, logger)
 		logger.Info(strings.Repeat("=", 60))
 		logger.Info("SNAPSHOT BACKFILL SUMMARY")
 		logger.Info(strings.Repeat("=", 60))
 		if len(successfulSnapshots) > 0 {
 			logger.Info(fmt.Sprintf("Successfully created: %d snapshots", len(successfulSnapshots)))
 			for _, name := range successfulSnapshots {
-				logger.Info(fmt.Sprintf("  ✓ %s", name))
+				logger.WithField("snapshot", name).Info("  ✓")
 			}
 		}
 		if len(failedSnapshots) > 0 {
 			logger.Info("")
 			logger.Info(fmt.Sprintf("Failed to create: %d snapshots", len(failedSnapshots)))
 			for _, name := range failedSnapshots {
-				logger.Info(fmt.Sprintf("  ✗ %s", name))
+				logger.WithField("snapshot", name).Info("  ✗")
 			}
 		}
 		if len(successfulSnapshots) == 0 && len(failedSnapshots) == 0 {
@@ -777,9 +785,7 @@ func runSnapshotsBackfill(cmd *cobra.Command, args []string) error {
 			logger.Info(fmt.Sprintf("Would create %d snapshots total:", totalSnapshotsToCreate))
 			logger.Info("")
 			for i, group := range allSnapshotsToCreate {
-				logger.Info(fmt.Sprintf("%d. Snapshot: %s", i+1, group.SnapshotName))
-				logger.Info(fmt.Sprintf("   Pattern: %s (%s)", group.Pattern, group.Kind))
-				logger.Info(fmt.Sprintf("   Indices (%d): %s", len(group.Indices), strings.Join(group.Indices, ", ")))
+				logger.WithFields(logging.Fields{"snapshot": group.SnapshotName, "pattern": group.Pattern, "kind": group.Kind, "count": len(group.Indices), "indices": strings.Join(group.Indices, ", ")}).Info(fmt.Sprintf("%d. Snapshot", i+1))
 				logger.Info("")
 			}
 		}
@@ -5,7 +5,9 @@ import (
 	"osctl/pkg/alerts"
 	"osctl/pkg/config"
 	"osctl/pkg/logging"
+	"osctl/pkg/metrics"
 	"osctl/pkg/opensearch"
+	"osctl/pkg/report"
 	"osctl/pkg/utils"
 	"strings"
 	"time"
@@ -16,7 +18,7 @@ import (
 var snapshotsCheckerCmd = &cobra.Command{
 	Use:   "snapshotschecker",
 	Short: "Check for missing snapshots and send alerts",
-	Long: `Check for missing snapshots of indices and send alerts to Madison.
+	Long: `Check for missing snapshots of indices and send alerts to the configured notifiers.
 Supports both whitelist and exclude list modes.`,
 	RunE: runSnapshotsChecker,
 }
@@ -50,7 +52,7 @@ func runSnapshotsChecker(cmd *cobra.Command, args []string) error {
 	today := utils.FormatDate(time.Now(), cfg.GetDateFormat())
 	yesterday := utils.FormatDate(time.Now().AddDate(0, 0, -1), cfg.GetDateFormat())
 
-	logger.Info(fmt.Sprintf("Getting all indices excluding today and yesterday today=%s yesterday=%s", today, yesterday))
+	logger.WithFields(logging.Fields{"today": today, "yesterday": yesterday}).Info("Getting all indices excluding today and yesterday")
 
 	allIndices, err := client.GetIndicesWithFields("*", "index")
 	if err != nil {
@@ -89,9 +91,9 @@ func runSnapshotsChecker(cmd *cobra.Command, args []string) error {
 		indicesToProcess = append(indicesToProcess, indexName)
 	}
 
-	logger.Info(fmt.Sprintf("Found indices to process count=%d", len(indicesToProcess)))
+	logger.WithField("count", len(indicesToProcess)).Info("Found indices to process")
 	if len(indicesToProcess) > 0 {
-		logger.Info(fmt.Sprintf("Indices to process %s", strings.Join(indicesToProcess, ", ")))
+		logger.WithField("indices", strings.Join(indicesToProcess, ", ")).Info("Indices to process")
 	}
 
 	if len(indicesToProcess) == 0 {
@@ -99,7 +101,7 @@ func runSnapshotsChecker(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	logger.Info(fmt.Sprintf("Getting all snapshots from repository repo=%s", cfg.GetSnapshotRepo()))
+	logger.WithField("repo", cfg.GetSnapshotRepo()).Info("Getting all snapshots from repository")
 	allSnapshots, err := utils.GetSnapshotsIgnore404(client, cfg.GetSnapshotRepo(), "*")
 	if err != nil {
 		return fmt.Errorf("failed to get snapshots: %v", err)
@@ -108,6 +110,20 @@ func runSnapshotsChecker(cmd *cobra.Command, args []string) error {
 		allSnapshots = []opensearch.Snapshot{}
 	}
 
+	copyRules := utils.SecondaryCopyRules(indicesConfig)
+	secondarySnapshots := map[string][]opensearch.Snapshot{}
+	for _, rule := range copyRules {
+		if _, ok := secondarySnapshots[rule.SecondaryRepository]; ok {
+			continue
+		}
+		copies, err := utils.GetSnapshotsIgnore404(client, rule.SecondaryRepository, "*")
+		if err != nil {
+			logger.WithFields(logging.Fields{"repo": rule.SecondaryRepository, "error": err}).Error("Failed to get secondary repo snapshots")
+			continue
+		}
+		secondarySnapshots[rule.SecondaryRepository] = copies
+	}
+
 	var snapshotNames []string
 	for _, s := range allSnapshots {
 		if s.State == "SUCCESS" {
@@ -115,12 +131,13 @@ func runSnapshotsChecker(cmd *cobra.Command, args []string) error {
 		}
 	}
 	if len(snapshotNames) > 0 {
-		logger.Info(fmt.Sprintf("Found successful snapshots count=%d", len(snapshotNames)))
+		logger.WithField("count", len(snapshotNames)).Info("Found successful snapshots")
 	} else {
 		logger.Info("Found snapshots none")
 	}
 
 	var missingSnapshotIndicesList []string
+	metrics.SnapshotsMissing.Reset()
 
 	for _, indexName := range indicesToProcess {
 		indexConfig := utils.FindMatchingIndexConfig(indexName, indicesConfig)
@@ -148,7 +165,7 @@ func runSnapshotsChecker(cmd *cobra.Command, args []string) error {
 			cutoffDate = utils.GetLaterCutoffDate(cutoffDateDaysCount, cutoffDateS3, cfg.GetDateFormat())
 
 			if utils.IsOlderThanCutoff(indexName, cutoffDate, cfg.GetDateFormat()) {
-				logger.Info(fmt.Sprintf("Skipping index older than cutoff index=%s cutoff=%s", indexName, cutoffDate))
+				logger.WithFields(logging.Fields{"index": indexName, "cutoff": cutoffDate}).Info("Skipping index older than cutoff")
 				continue
 			}
 		} else {
@@ -165,37 +182,118 @@ func runSnapshotsChecker(cmd *cobra.Command, args []string) error {
 				cutoffDate = utils.GetLaterCutoffDate(cutoffDateDaysCount, cutoffDateS3, cfg.GetDateFormat())
 
 				if utils.IsOlderThanCutoff(indexName, cutoffDate, cfg.GetDateFormat()) {
-					logger.Info(fmt.Sprintf("Skipping unknown index older than cutoff index=%s cutoff=%s", indexName, cutoffDate))
+					logger.WithFields(logging.Fields{"index": indexName, "cutoff": cutoffDate}).Info("Skipping unknown index older than cutoff")
 					continue
 				}
 			}
 		}
 
 		if shouldHaveSnapshot {
+			prefix := indexName
+			if dateStr := utils.ExtractDateFromIndex(indexName, cfg.GetDateFormat()); dateStr != "" {
+				prefix = utils.IndexPrefixForDate(indexName, dateStr)
+			}
 			if !utils.HasValidSnapshot(indexName, allSnapshots) {
 				missingSnapshotIndicesList = append(missingSnapshotIndicesList, indexName)
+				metrics.SnapshotsMissing.Add(1, prefix)
+				report.Failed(indexName, "no valid snapshot")
+			} else {
+				metrics.SnapshotsMissing.Add(0, prefix)
+				report.Succeeded(indexName)
 			}
 		}
 	}
 
+	notifier := utils.NewNotifier(cfg)
+	if !cfg.GetDryRun() {
+		utils.ResolveAlerts(notifier, alerts.SnapshotsMissing, missingSnapshotIndicesList, logger)
+	}
 	if len(missingSnapshotIndicesList) > 0 {
-		logger.Warn(fmt.Sprintf("Missing snapshots found count=%d", len(missingSnapshotIndicesList)))
-		logger.Warn(fmt.Sprintf("Missing snapshots list %s", strings.Join(missingSnapshotIndicesList, ", ")))
+		logger.WithField("count", len(missingSnapshotIndicesList)).Warn("Missing snapshots found")
+		logger.WithField("indices", strings.Join(missingSnapshotIndicesList, ", ")).Warn("Missing snapshots list")
 		if cfg.GetDryRun() {
-			logger.Info("DRY RUN: Would send Madison alert for missing snapshots")
+			logger.Info("DRY RUN: Would send alert for missing snapshots")
 		} else {
-			madisonClient := alerts.NewMadisonClient(cfg.GetMadisonKey(), cfg.GetOSDURL(), cfg.GetMadisonURL())
-			response, err := madisonClient.SendMadisonSnapshotMissingAlert(missingSnapshotIndicesList, cfg.GetSnapshotRepo(), cfg.GetKubeNamespace(), today)
+			if notifier == nil {
+				return fmt.Errorf("failed to send alert: no alert notifier configured (alert-notifiers)")
+			}
+			response, err := notifier.Notify(alerts.NewSnapshotsMissingEvent(missingSnapshotIndicesList, cfg.GetSnapshotRepo(), cfg.GetKubeNamespace(), today))
 			if err != nil {
-				logger.Error(fmt.Sprintf("Failed to send Madison alert error=%v", err))
-				return fmt.Errorf("failed to send Madison alert: %v", err)
+				logger.WithField("error", err).Error("Failed to send alert")
+				return fmt.Errorf("failed to send alert: %v", err)
 			}
-			logger.Info(fmt.Sprintf("Madison alert sent successfully: type=SnapshotMissing response=%s", response))
+			logger.WithFields(logging.Fields{"type": "SnapshotMissing", "notifiers": notifier.Name(), "response": response}).Info("Alert sent successfully")
 		}
 	} else {
 		logger.Info("All snapshots are present")
 	}
 
+	if len(copyRules) > 0 {
+		missingCopies, details := missingSecondaryCopies(client, cfg.GetSnapshotRepo(), copyRules, indicesConfig, secondarySnapshots, s3Config, cfg.GetDateFormat(), logger)
+		if !cfg.GetDryRun() {
+			utils.ResolveAlerts(notifier, alerts.SnapshotCopyMissing, missingCopies, logger)
+		}
+		if len(missingCopies) > 0 {
+			logger.WithField("count", len(missingCopies)).Warn("Missing secondary copies found")
+			logger.WithField("snapshots", strings.Join(missingCopies, ", ")).Warn("Missing secondary copies list")
+			if cfg.GetDryRun() {
+				logger.Info("DRY RUN: Would send alert for missing secondary copies")
+			} else {
+				if notifier == nil {
+					return fmt.Errorf("failed to send alert: no alert notifier configured (alert-notifiers)")
+				}
+				response, err := notifier.Notify(alerts.NewSnapshotCopyMissingEvent(missingCopies, details))
+				if err != nil {
+					logger.WithField("error", err).Error("Failed to send alert")
+					return fmt.Errorf("failed to send alert: %v", err)
+				}
+				logger.WithFields(logging.Fields{"type": "SnapshotCopyMissing", "notifiers": notifier.Name(), "response": response}).Info("Alert sent successfully")
+			}
+		} else {
+			logger.Info("All secondary copies are present")
+		}
+	}
+
 	logger.Info("Snapshot checking completed")
 	return nil
 }
+
+func missingSecondaryCopies(client *opensearch.Client, defaultRepo string, rules, indicesConfig []config.IndexConfig, secondarySnapshots map[string][]opensearch.Snapshot, s3Config config.S3SnapshotsConfig, dateFormat string, logger *logging.Logger) ([]string, []string) {
+	yesterday := utils.FormatDate(time.Now().AddDate(0, 0, -1), dateFormat)
+	sourceSnapshots := map[string][]opensearch.Snapshot{}
+	var missing, details []string
+	for _, rule := range rules {
+		copies, ok := secondarySnapshots[rule.SecondaryRepository]
+		if !ok {
+			continue
+		}
+		sourceRepo := utils.SourceRepository(defaultRepo, rule)
+		snaps, ok := sourceSnapshots[sourceRepo]
+		if !ok {
+			var err error
+			snaps, err = utils.GetSnapshotsIgnore404(client, sourceRepo, "*")
+			if err != nil {
+				logger.WithFields(logging.Fields{"repo": sourceRepo, "error": err}).Error("Failed to get snapshots")
+				continue
+			}
+			sourceSnapshots[sourceRepo] = snaps
+		}
+		cutoff := utils.FormatDate(time.Now().AddDate(0, 0, -utils.SecondaryRetentionDays(rule, s3Config)), dateFormat)
+		for _, s := range snaps {
+			if s.State != "SUCCESS" || utils.IsSnapshotCopy(s.Snapshot) || !utils.HasDateInName(s.Snapshot, dateFormat) {
+				continue
+			}
+			if !utils.SnapshotBelongsToRule(s, rule, indicesConfig) {
+				continue
+			}
+			if !utils.IsOlderThanCutoff(s.Snapshot, yesterday, dateFormat) || utils.IsOlderThanCutoff(s.Snapshot, cutoff, dateFormat) {
+				continue
+			}
+			if !utils.HasSnapshotCopy(s.Snapshot, copies) {
+				missing = append(missing, s.Snapshot)
+				details = append(details, fmt.Sprintf("- %s/%s → %s", sourceRepo, s.Snapshot, rule.SecondaryRepository))
+			}
+		}
+	}
+	return missing, details
+}
@@ -39,7 +39,7 @@ func runSnapshotsDelete(cmd *cobra.Command, args []string) error {
 	s3Config := cfg.GetOsctlIndicesS3SnapshotsConfig()
 	unknownConfig := cfg.GetOsctlIndicesUnknownConfig()
 
-	logger.Info(fmt.Sprintf("Starting snapshot deletion indicesCount=%d allDays=%d unknownDays=%d", len(indicesConfig), s3Config.UnitCount.All, s3Config.UnitCount.Unknown))
+	logger.WithFields(logging.Fields{"indicesCount": len(indicesConfig), "allDays": s3Config.UnitCount.All, "unknownDays": s3Config.UnitCount.Unknown}).Info("Starting snapshot deletion")
 
 	client, err := utils.NewOSClientWithURL(cfg, cfg.GetOpenSearchURL())
 	if err != nil {
@@ -59,7 +59,7 @@ func runSnapshotsDelete(cmd *cobra.Command, args []string) error {
 		names = append(names, s.Snapshot)
 	}
 	if len(names) > 0 {
-		logger.Info(fmt.Sprintf("Found snapshots %s", strings.Join(names, ", ")))
+		logger.WithField("snapshots", strings.Join(names, ", ")).Info("Found snapshots")
 	} else {
 		logger.Info("Found snapshots none")
 	}
@@ -71,8 +71,11 @@ func runSnapshotsDelete(cmd *cobra.Command, args []string) error {
 
 	for _, snapshot := range allSnapshots {
 		snapshotName := snapshot.Snapshot
+		if utils.IsManagedSnapshotCopy(snapshot, indicesConfig) {
+			continue
+		}
 
-		indexConfig := utils.FindMatchingSnapshotConfig(snapshotName, indicesConfig)
+		indexConfig := utils.FindSnapshotConfig(snapshot, indicesConfig)
 
 		if indexConfig != nil && indexConfig.Repository != "" {
 			indexConfig = nil
@@ -102,7 +105,7 @@ func runSnapshotsDelete(cmd *cobra.Command, args []string) error {
 			}
 		}
 		for _, snapshot := range danglingSnapshots {
-			logger.Info(fmt.Sprintf("Dangling snapshot (default repo) snapshot=%s", snapshot.Snapshot))
+			logger.WithField("snapshot", snapshot.Snapshot).Info("Dangling snapshot (default repo)")
 		}
 	}
 
@@ -115,7 +118,7 @@ func runSnapshotsDelete(cmd *cobra.Command, args []string) error {
 	for repo := range repoSet {
 		rsnaps, err := utils.GetSnapshotsIgnore404(client, repo, "*")
 		if err != nil {
-			logger.Error(fmt.Sprintf("Failed to get repo snapshots repo=%s error=%v", repo, err))
+			logger.WithFields(logging.Fields{"repo": repo, "error": err}).Error("Failed to get repo snapshots")
 			continue
 		}
 		if rsnaps == nil {
@@ -126,27 +129,30 @@ func runSnapshotsDelete(cmd *cobra.Command, args []string) error {
 			for _, s := range rsnaps {
 				rnames = append(rnames, s.Snapshot)
 			}
-			logger.Info(fmt.Sprintf("Found snapshots in repo=%s %s", repo, strings.Join(rnames, ", ")))
+			logger.WithFields(logging.Fields{"repo": repo, "snapshots": strings.Join(rnames, ", ")}).Info("Found snapshots")
 		} else {
-			logger.Info(fmt.Sprintf("Found snapshots in repo=%s none", repo))
+			logger.WithField("repo", repo).Info("Found snapshots none")
 		}
 		for _, s := range rsnaps {
 			name := s.Snapshot
-			ic := utils.FindMatchingSnapshotConfig(name, indicesConfig)
+			if utils.IsManagedSnapshotCopy(s, indicesConfig) {
+				continue
+			}
+			ic := utils.FindSnapshotConfig(s, indicesConfig)
 			if ic == nil {
-				logger.Info(fmt.Sprintf("Dangling snapshot (repo=%s) snapshot=%s (no matching config)", repo, name))
+				logger.WithFields(logging.Fields{"repo": repo, "snapshot": name}).Info("Dangling snapshot (no matching config)")
 				continue
 			}
 			if ic.Repository != repo {
-				logger.Info(fmt.Sprintf("Dangling snapshot (repo=%s) snapshot=%s (belongs to repo=%s)", repo, name, ic.Repository))
+				logger.WithFields(logging.Fields{"repo": repo, "snapshot": name, "configRepo": ic.Repository}).Info("Dangling snapshot (belongs to another repo)")
 				continue
 			}
 			if !ic.Snapshot {
-				logger.Info(fmt.Sprintf("Dangling snapshot (repo=%s) snapshot=%s (snapshot disabled in config)", repo, name))
+				logger.WithFields(logging.Fields{"repo": repo, "snapshot": name}).Info("Dangling snapshot (snapshot disabled in config)")
 				continue
 			}
 			if !utils.HasDateInName(name, cfg.GetDateFormat()) {
-				logger.Info(fmt.Sprintf("Skip repo snapshot without date repo=%s snapshot=%s", repo, name))
+				logger.WithFields(logging.Fields{"repo": repo, "snapshot": name}).Info("Skip repo snapshot without date")
 				continue
 			}
 			daysCount := s3Config.UnitCount.All
@@ -159,6 +165,10 @@ func runSnapshotsDelete(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	for repo, names := range secondaryCopiesToDelete(client, cfg.GetSnapshotRepo(), indicesConfig, s3Config, cfg.GetDateFormat(), logger) {
+		repoToSnapshots[repo] = append(repoToSnapshots[repo], names...)
+	}
+
 	var successfulDeletions []string
 	var failedDeletions []string
 
@@ -170,11 +180,11 @@ func runSnapshotsDelete(cmd *cobra.Command, args []string) error {
 			time.Sleep(randomWaitDuration)
 		}
 
-		logger.Info(fmt.Sprintf("Snapshots to delete %s", strings.Join(snapshotsToDelete, ", ")))
-		logger.Info(fmt.Sprintf("Deleting snapshots count=%d", len(snapshotsToDelete)))
-		successful, failed, err := utils.BatchDeleteSnapshots(client, snapshotsToDelete, cfg.GetSnapshotRepo(), cfg.GetDryRun(), logger)
+		logger.WithField("snapshots", strings.Join(snapshotsToDelete, ", ")).Info("Snapshots to delete")
+		logger.WithField("count", len(snapshotsToDelete)).Info("Deleting snapshots")
+		successful, failed, err := utils.BatchDeleteSnapshots(client.WithAuditReason("snapshot retention period expired"), snapshotsToDelete, cfg.GetSnapshotRepo(), cfg.GetDryRun(), logger)
 		if err != nil {
-			logger.Error(fmt.Sprintf("Failed to delete snapshots error=%v", err))
+			logger.WithField("error", err).Error("Failed to delete snapshots")
 		}
 		successfulDeletions = append(successfulDeletions, successful...)
 		failedDeletions = append(failedDeletions, failed...)
@@ -187,8 +197,8 @@ func runSnapshotsDelete(cmd *cobra.Command, args []string) error {
 			if len(names) == 0 {
 				continue
 			}
-			logger.Info(fmt.Sprintf("Snapshots to delete (repo=%s) %s", repo, strings.Join(names, ", ")))
-			successful, failed, _ := utils.BatchDeleteSnapshots(client, names, repo, cfg.GetDryRun(), logger)
+			logger.WithFields(logging.Fields{"repo": repo, "snapshots": strings.Join(names, ", ")}).Info("Snapshots to delete")
+			successful, failed, _ := utils.BatchDeleteSnapshots(client.WithAuditReason("snapshot retention period expired"), names, repo, cfg.GetDryRun(), logger)
 			for _, name := range successful {
 				successfulDeletions = append(successfulDeletions, fmt.Sprintf("%s (repo=%s)", name, repo))
 			}
@@ -207,14 +217,14 @@ func runSnapshotsDelete(cmd *cobra.Command, args []string) error {
 		if len(successfulDeletions) > 0 {
 			logger.Info(fmt.Sprintf("Successfully deleted: %d snapshots", len(successfulDeletions)))
 			for _, name := range successfulDeletions {
-				logger.Info(fmt.Sprintf("  ✓ %s", name))
+				logger.WithField("snapshot", name).Info("  ✓")
 			}
 		}
 		if len(failedDeletions) > 0 {
 			logger.Info("")
 			logger.Info(fmt.Sprintf("Failed to delete: %d snapshots", len(failedDeletions)))
 			for _, name := range failedDeletions {
-				logger.Info(fmt.Sprintf("  ✗ %s", name))
+				logger.WithField("snapshot", name).Info("  ✗")
 			}
 		}
 		if len(successfulDeletions) == 0 && len(failedDeletions) == 0 {
@@ -226,3 +236,48 @@ func runSnapshotsDelete(cmd *cobra.Command, args []string) error {
 	logger.Info("Snapshot deletion completed")
 	return nil
 }
+
+func secondaryCopiesToDelete(client *opensearch.Client, defaultRepo string, indicesConfig []config.IndexConfig, s3Config config.S3SnapshotsConfig, dateFormat string, logger *logging.Logger) map[string][]string {
+	rules := utils.SecondaryCopyRules(indicesConfig)
+	if len(rules) == 0 {
+		return nil
+	}
+	registered, err := client.GetRepositories()
+	if err != nil {
+		logger.WithField("error", err).Error("Failed to get snapshot repositories, secondary copies are not deleted this run")
+		return nil
+	}
+
+	toDelete := map[string][]string{}
+	listed := map[string][]opensearch.Snapshot{}
+	for _, rule := range rules {
+		repo := rule.SecondaryRepository
+		snaps, ok := listed[repo]
+		if !ok {
+			snaps, err = utils.GetSnapshotsIgnore404(client, repo, "*")
+			if err != nil {
+				logger.WithFields(logging.Fields{"repo": repo, "error": err}).Error("Failed to get secondary repo snapshots")
+				continue
+			}
+			listed[repo] = snaps
+		}
+		shared := utils.RepositoriesShareStore(registered, utils.SourceRepository(defaultRepo, rule), repo)
+		days := utils.SecondaryRetentionDays(rule, s3Config)
+		cutoff := utils.FormatDate(time.Now().AddDate(0, 0, -days), dateFormat)
+		for _, s := range snaps {
+			name := s.Snapshot
+			if shared && !utils.IsSnapshotCopy(name) {
+				continue
+			}
+			source := utils.SnapshotCopySource(name)
+			if !utils.HasDateInName(source, dateFormat) || !utils.SnapshotBelongsToRule(opensearch.Snapshot{Snapshot: source, Metadata: s.Metadata}, rule, indicesConfig) {
+				continue
+			}
+			if utils.IsOlderThanCutoff(source, cutoff, dateFormat) {
+				toDelete[repo] = append(toDelete[repo], name)
+			}
+		}
+		logger.WithFields(logging.Fields{"repo": repo, "rule": rule.Value, "retentionDays": days, "sharedStore": shared}).Info("Secondary copies checked")
+	}
+	return toDelete
+}
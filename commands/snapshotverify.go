@@ -0,0 +1,227 @@
+package commands
+
+import (
+	"fmt"
+	"osctl/pkg/alerts"
+	"osctl/pkg/config"
+	"osctl/pkg/logging"
+	"osctl/pkg/opensearch"
+	"osctl/pkg/report"
+	"osctl/pkg/utils"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var snapshotVerifyCmd = &cobra.Command{
+	Use:   "snapshotverify",
+	Short: "Verify snapshots by test restores into temporary indices",
+	Long: `Pick a rotating sample of SUCCESS snapshots per prefix and restore one index of each
+into a temporary renamed index (snapshotverify-temp-prefix) on the main cluster or the
+recoverer. Doc counts are compared with the original index when it still exists, the
+temporary index is deleted and failures are sent to the configured alert notifiers.`,
+	RunE: runSnapshotVerify,
+}
+
+func init() {
+	addFlags(snapshotVerifyCmd)
+}
+
+type snapshotVerifyResult struct {
+	sample utils.VerifySample
+	temp   string
+	ok     bool
+	detail string
+}
+
+func runSnapshotVerify(cmd *cobra.Command, args []string) error {
+	cfg := config.GetConfig()
+	logger := logging.NewLogger()
+	client, err := utils.NewOSClientWithURL(cfg, cfg.GetOpenSearchURL())
+	if err != nil {
+		return fmt.Errorf("failed to create OpenSearch client: %v", err)
+	}
+
+	indicesConfig, err := cfg.GetOsctlIndices()
+	if err != nil {
+		return fmt.Errorf("failed to get osctl indices: %v", err)
+	}
+
+	target := cfg.GetSnapshotVerifyTarget()
+	targetClient := client
+	if target == "recoverer" {
+		if cfg.GetOpenSearchRecovererURL() == "" {
+			return fmt.Errorf("os-recoverer-url is required for snapshotverify-target=recoverer")
+		}
+		targetClient, err = utils.NewOSClientWithURL(cfg, cfg.GetOpenSearchRecovererURL())
+		if err != nil {
+			return fmt.Errorf("failed to create OpenSearch recoverer client: %v", err)
+		}
+	}
+
+	sampleSize := cfg.GetSnapshotVerifySampleSize()
+	tempPrefix := cfg.GetSnapshotVerifyTempPrefix()
+	timeout := cfg.GetSnapshotVerifyTimeout()
+	dateFormat := cfg.GetDateFormat()
+	day := int(time.Now().Unix() / 86400)
+	repos := utils.SnapshotRepositoriesFor(cfg.GetSnapshotRepo(), indicesConfig)
+	logger.WithFields(logging.Fields{"target": target, "repos": strings.Join(repos, ","), "sampleSize": sampleSize, "tempPrefix": tempPrefix, "timeout": timeout, "dryRun": cfg.GetDryRun()}).Info("Starting snapshotverify")
+
+	if err := utils.CheckSnapshotRepositories(targetClient, repos, logger); err != nil {
+		return err
+	}
+
+	var samples []utils.VerifySample
+	for _, repo := range repos {
+		snapshots, err := utils.GetSnapshotsIgnore404(client, repo, "*")
+		if err != nil {
+			return fmt.Errorf("failed to get snapshots repo=%s: %v", repo, err)
+		}
+		var successful []opensearch.Snapshot
+		for _, s := range snapshots {
+			if s.State == "SUCCESS" {
+				successful = append(successful, s)
+			}
+		}
+		groups := utils.GroupSnapshotsByPrefix(successful, dateFormat)
+		prefixes := make([]string, 0, len(groups))
+		for prefix := range groups {
+			prefixes = append(prefixes, prefix)
+		}
+		sort.Strings(prefixes)
+		for _, prefix := range prefixes {
+			for _, s := range utils.RotatingSample(groups[prefix], sampleSize, day) {
+				index := utils.PickVerifyIndex(s, day)
+				if index == "" {
+					logger.WithFields(logging.Fields{"repo": repo, "snapshot": s.Snapshot}).Info("Snapshot has no user indices to verify, skipping")
+					continue
+				}
+				samples = append(samples, utils.VerifySample{Repo: repo, Prefix: prefix, Snapshot: s, Index: index})
+			}
+		}
+		logger.WithFields(logging.Fields{"repo": repo, "success": len(successful), "prefixes": len(groups)}).Info("Snapshots in repo")
+	}
+	logger.Info(fmt.Sprintf("Snapshots selected for test restore: %d", len(samples)))
+
+	if cfg.GetDryRun() {
+		for i, s := range samples {
+			logger.WithFields(logging.Fields{"repo": s.Repo, "snapshot": s.Snapshot.Snapshot, "index": s.Index, "temp": tempPrefix + s.Index}).Info(fmt.Sprintf("DRY RUN: Would test-restore %d", i+1))
+			report.Skipped(fmt.Sprintf("%s (repo=%s)", s.Snapshot.Snapshot, s.Repo), "dry run")
+		}
+		return nil
+	}
+
+	var results []snapshotVerifyResult
+	for _, s := range samples {
+		start := time.Now()
+		r := verifySnapshotSample(client, targetClient, s, tempPrefix, dateFormat, timeout, logger)
+		item := fmt.Sprintf("%s (repo=%s)", s.Snapshot.Snapshot, s.Repo)
+		if r.ok {
+			report.Succeeded(item).Took(time.Since(start))
+		} else {
+			report.Failed(item, fmt.Sprintf("index=%s: %s", s.Index, r.detail)).Took(time.Since(start))
+		}
+		results = append(results, r)
+	}
+
+	var failedSnapshots, details []string
+	logger.Info(strings.Repeat("=", 60))
+	logger.Info("SNAPSHOTVERIFY SUMMARY")
+	logger.Info(strings.Repeat("=", 60))
+	for _, r := range results {
+		line := fmt.Sprintf("%s/%s index=%s: %s", r.sample.Repo, r.sample.Snapshot.Snapshot, r.sample.Index, r.detail)
+		if r.ok {
+			logger.Info("  ✓ " + line)
+			continue
+		}
+		logger.Info("  ✗ " + line)
+		failedSnapshots = append(failedSnapshots, r.sample.Snapshot.Snapshot)
+		details = append(details, "- "+line)
+	}
+	logger.Info(strings.Repeat("=", 60))
+
+	if len(failedSnapshots) == 0 {
+		return nil
+	}
+	if notifier := utils.NewNotifier(cfg); notifier != nil {
+		response, err := notifier.Notify(alerts.NewSnapshotVerifyFailedEvent(failedSnapshots, details))
+		if err != nil {
+			logger.WithField("error", err).Error("Failed to send alert")
+		} else {
+			logger.WithFields(logging.Fields{"type": "SnapshotVerifyFailed", "count": len(failedSnapshots), "notifiers": notifier.Name(), "response": response}).Info("Alert sent successfully")
+		}
+	} else {
+		logger.Warn("No alert notifier is configured (alert-notifiers) — alert skipped")
+	}
+	return fmt.Errorf("snapshot verification failed for %d snapshots", len(failedSnapshots))
+}
+
+func verifySnapshotSample(client, targetClient *opensearch.Client, s utils.VerifySample, tempPrefix, dateFormat string, timeout time.Duration, logger *logging.Logger) snapshotVerifyResult {
+	temp := tempPrefix + s.Index
+	r := snapshotVerifyResult{sample: s, temp: temp}
+
+	if exists, err := targetClient.IndexExists(temp); err == nil && exists {
+		logger.WithField("index", temp).Warn("Temporary index left from a previous run, deleting")
+		if err := targetClient.DeleteIndex(temp); err != nil {
+			r.detail = fmt.Sprintf("failed to delete stale temporary index %s: %v", temp, err)
+			return r
+		}
+	}
+
+	utils.WaitForOurRestoreSlot(targetClient, []string{tempPrefix + "*"}, 1, 30*time.Second, logger, 0)
+
+	start := time.Now()
+	logger.WithFields(logging.Fields{"repo": s.Repo, "snapshot": s.Snapshot.Snapshot, "index": s.Index, "temp": temp}).Info("Test restore started")
+	if err := targetClient.RestoreSnapshot(s.Repo, s.Snapshot.Snapshot, utils.RestoreBodyWithRename(s.Index, temp)); err != nil {
+		r.detail = fmt.Sprintf("restore request failed: %v", err)
+		return r
+	}
+	defer func() {
+		if err := targetClient.DeleteIndex(temp); err != nil {
+			logger.WithFields(logging.Fields{"index": temp, "error": err}).Error("Failed to delete temporary index")
+		} else {
+			logger.WithField("index", temp).Info("Temporary index deleted")
+		}
+	}()
+
+	if err := utils.WaitForVerifyRestore(targetClient, temp, 30*time.Second, timeout, logger); err != nil {
+		r.detail = err.Error()
+		return r
+	}
+
+	restored, err := targetClient.CountDocs(temp)
+	if err != nil {
+		r.detail = fmt.Sprintf("failed to count docs in %s: %v", temp, err)
+		return r
+	}
+	duration := time.Since(start).Round(time.Second)
+
+	exists, err := client.IndexExists(s.Index)
+	if err != nil || !exists {
+		r.ok = true
+		r.detail = fmt.Sprintf("restored docs=%d in %s (original index not available, count not compared)", restored, duration)
+		return r
+	}
+	original, err := client.CountDocs(s.Index)
+	if err != nil {
+		r.ok = true
+		r.detail = fmt.Sprintf("restored docs=%d in %s (failed to count original: %v)", restored, duration, err)
+		return r
+	}
+
+	dayBeforeSnapshot := utils.FormatDate(time.UnixMilli(s.Snapshot.StartTimeInMillis).AddDate(0, 0, -1), dateFormat)
+	complete := s.Snapshot.StartTimeInMillis > 0 && utils.IsOlderThanCutoff(s.Index, dayBeforeSnapshot, dateFormat)
+	switch {
+	case restored == original:
+		r.ok = true
+		r.detail = fmt.Sprintf("docs=%d match original in %s", restored, duration)
+	case !complete && restored < original:
+		r.ok = true
+		r.detail = fmt.Sprintf("docs=%d, original=%d still written after snapshot, in %s", restored, original, duration)
+	default:
+		r.detail = fmt.Sprintf("doc count mismatch restored=%d original=%d", restored, original)
+	}
+	return r
+}
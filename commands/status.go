@@ -0,0 +1,188 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"osctl/pkg/config"
+	"osctl/pkg/logging"
+	"osctl/pkg/opensearch"
+	"osctl/pkg/state"
+	"osctl/pkg/utils"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show lifecycle status of configured prefixes, the cluster and in-flight runs",
+	Long: `Summarise where the index lifecycle stands: cluster disk utilisation, active snapshot
+and restore tasks, and per configured prefix the index count, oldest and newest dates, size,
+tier, replicas, snapshot coverage and the next deletion date with the policy that drives it.
+Runs still in flight from the state store (index or file) are listed at the end together with
+their unfinished and failed tasks; with --status-all finished runs are listed as well.
+Output is a terminal table or JSON (--status-format).`,
+	RunE: runStatus,
+}
+
+func init() {
+	addFlags(statusCmd)
+}
+
+type statusReport struct {
+	GeneratedAt time.Time             `json:"generated_at"`
+	Cluster     utils.ClusterStatus   `json:"cluster"`
+	Prefixes    []*utils.PrefixStatus `json:"prefixes"`
+	Runs        []*state.Run          `json:"runs"`
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	cfg := config.GetConfig()
+	logger := logging.NewLogger()
+
+	client, err := utils.NewOSClientWithURL(cfg, cfg.GetOpenSearchURL())
+	if err != nil {
+		return fmt.Errorf("failed to create OpenSearch client: %v", err)
+	}
+
+	report := statusReport{GeneratedAt: time.Now().UTC(), Cluster: statusCluster(cfg, client, logger), Runs: []*state.Run{}}
+
+	indices, err := client.GetIndicesWithFields("*", "index,rep,ss", "index:asc")
+	if err != nil {
+		return fmt.Errorf("failed to get indices: %v", err)
+	}
+	cold, err := client.GetIndicesColdRequirement("*")
+	if err != nil {
+		logger.WithField("error", err).Warn("Failed to read index tiers, all indices shown as hot")
+	}
+	report.Prefixes, err = utils.BuildPrefixStatuses(indices, cold, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to get osctl indices: %v", err)
+	}
+	snapshots := make(map[string][]opensearch.Snapshot)
+	for _, p := range report.Prefixes {
+		if p.SnapshotRepo == "" || p.Indices == 0 {
+			continue
+		}
+		list, ok := snapshots[p.SnapshotRepo]
+		if !ok {
+			list, err = utils.GetSnapshotsIgnore404(client, p.SnapshotRepo, "*")
+			if err != nil {
+				logger.WithFields(logging.Fields{"repo": p.SnapshotRepo, "error": err}).Warn("Failed to list snapshots, coverage unknown")
+			}
+			snapshots[p.SnapshotRepo] = list
+		}
+		p.FillSnapshotCoverage(list)
+	}
+
+	if cfg.GetStateStore() != "none" {
+		runs, err := utils.NewStateStore(cfg, client).List()
+		if err != nil {
+			logger.WithFields(logging.Fields{"store": cfg.GetStateStore(), "error": err}).Warn("Failed to read run state")
+		}
+		for _, run := range runs {
+			if cfg.GetStatusAll() || run.Status == state.RunRunning {
+				report.Runs = append(report.Runs, run)
+			}
+		}
+		logger.WithFields(logging.Fields{"total": len(runs), "shown": len(report.Runs), "store": cfg.GetStateStore()}).Info("Runs in state store")
+	}
+	logger.WithFields(logging.Fields{"prefixes": len(report.Prefixes), "indices": len(indices)}).Info("Lifecycle status collected")
+
+	if cfg.GetStatusFormat() == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	return writeLifecycleTable(os.Stdout, report)
+}
+
+func statusCluster(cfg *config.Config, client *opensearch.Client, logger *logging.Logger) utils.ClusterStatus {
+	status := utils.ClusterStatus{Name: utils.ClusterName(cfg), ActiveSnapshots: []string{}, ActiveRestores: []string{}}
+	if util, err := utils.GetAverageUtilization(client, logger, false); err != nil {
+		logger.WithField("error", err).Warn("Failed to get disk utilization")
+	} else {
+		status.DiskUtilization = util
+	}
+	if active, err := utils.GetActiveSnapshots(client); err != nil {
+		logger.WithField("error", err).Warn("Failed to get active snapshots")
+	} else {
+		for _, s := range active {
+			status.ActiveSnapshots = append(status.ActiveSnapshots, fmt.Sprintf("%s (repo=%s)", s.Snapshot, s.Repository))
+		}
+	}
+	if restores, err := client.ActiveSnapshotRecoveryIndices(); err != nil {
+		logger.WithField("error", err).Warn("Failed to get active restores")
+	} else {
+		status.ActiveRestores = append(status.ActiveRestores, restores...)
+	}
+	return status
+}
+
+func writeLifecycleTable(w io.Writer, report statusReport) error {
+	c := report.Cluster
+	fmt.Fprintf(w, "CLUSTER %s  disk %d%%  active snapshots %d  active restores %d\n", c.Name, c.DiskUtilization, len(c.ActiveSnapshots), len(c.ActiveRestores))
+	for _, s := range c.ActiveSnapshots {
+		fmt.Fprintln(w, "  snapshot "+s)
+	}
+	for _, index := range c.ActiveRestores {
+		fmt.Fprintln(w, "  restore "+index)
+	}
+	fmt.Fprintln(w, "")
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PREFIX\tINDICES\tOLDEST\tNEWEST\tSIZE\tTIER\tREPLICAS\tSNAPSHOTS\tNEXT DELETION\tPOLICY")
+	for _, p := range report.Prefixes {
+		next := p.NextDeletion
+		if next == "" {
+			next = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", p.Prefix, p.Indices, p.OldestDate, p.NewestDate, utils.FormatSize(p.TotalBytes),
+			p.Tier(), strings.Join(p.Replicas, ","), p.Coverage(), next, p.Policy)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w, "")
+	if len(report.Runs) == 0 {
+		fmt.Fprintln(w, "No in-flight runs")
+		return nil
+	}
+	return writeStatusTable(w, report.Runs)
+}
+
+func writeStatusTable(w io.Writer, runs []*state.Run) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "RUN ID\tACTION\tMODE\tDATE\tPOD\tSTATUS\tSTARTED\tUPDATED\tRESUMED\tDONE\tFAILED\tLEFT")
+	for _, run := range runs {
+		counts := run.Counts()
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s ago\t%d\t%d\t%d\t%d\n", run.ID, run.Action, run.Mode, run.Date, run.Pod, run.Status,
+			run.StartedAt.Format(time.RFC3339), time.Since(run.UpdatedAt).Round(time.Second), run.Resumed,
+			counts[state.TaskSuccess]+counts[state.TaskPartial], counts[state.TaskFailed], counts[state.TaskPending]+counts[state.TaskRunning])
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	header := false
+	for _, run := range runs {
+		for _, t := range run.Tasks {
+			if t.Status == state.TaskSuccess {
+				continue
+			}
+			if !header {
+				fmt.Fprintln(tw, "")
+				fmt.Fprintln(tw, "RUN ID\tREPOSITORY\tSNAPSHOT\tSTATUS\tATTEMPTS\tUPDATED\tLAST ERROR")
+				header = true
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%s ago\t%s\n", run.ID, t.Repo, t.Snapshot, t.Status, t.Attempts, time.Since(t.UpdatedAt).Round(time.Second), t.LastError)
+		}
+	}
+	return tw.Flush()
+}
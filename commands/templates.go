@@ -0,0 +1,179 @@
+package commands
+
+import (
+	"fmt"
+	"osctl/pkg/config"
+	"osctl/pkg/logging"
+	"osctl/pkg/utils"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var templatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "Index template tools",
+	Long:  `Tools for inspecting composable and legacy index templates.`,
+}
+
+var templatesCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check index templates for priority conflicts",
+	Long: `Load all composable (_index_template) and legacy (_template) templates and compute,
+for every active index prefix (indices created today), which template would win.
+Reports equal-priority overlaps (index creation fails on OpenSearch), shadowed
+templates and prefixes that are not covered by any template.
+Exits with an error when equal-priority conflicts are found.`,
+	RunE: runTemplatesCheck,
+}
+
+func init() {
+	templatesCmd.AddCommand(templatesCheckCmd)
+	addFlags(templatesCmd)
+	addFlags(templatesCheckCmd)
+}
+
+func runTemplatesCheck(cmd *cobra.Command, args []string) error {
+	cfg := config.GetConfig()
+	logger := logging.NewLogger()
+	client, err := utils.NewOSClientWithURL(cfg, cfg.GetOpenSearchURL())
+	if err != nil {
+		return fmt.Errorf("failed to create OpenSearch client: %v", err)
+	}
+
+	candidates, err := utils.CollectTemplateCandidates(client)
+	if err != nil {
+		return err
+	}
+	legacyCount := 0
+	for _, t := range candidates {
+		if t.Legacy {
+			legacyCount++
+		}
+	}
+	logger.WithFields(logging.Fields{"composable": len(candidates) - legacyCount, "legacy": legacyCount}).Info("Templates loaded")
+
+	today := utils.FormatDate(time.Now(), cfg.GetDateFormat())
+	indicesToday, err := client.GetIndicesWithFields(fmt.Sprintf("*-%s*,-.*", today), "index")
+	if err != nil {
+		return fmt.Errorf("failed to get today's indices: %v", err)
+	}
+
+	prefixes := make(map[string]string)
+	for _, idx := range indicesToday {
+		if utils.ShouldSkipIndex(idx.Index) {
+			continue
+		}
+		base := utils.IndexPrefixForDate(idx.Index, today)
+		if _, ok := prefixes[base]; !ok {
+			prefixes[base] = idx.Index
+		}
+	}
+	bases := make([]string, 0, len(prefixes))
+	for base := range prefixes {
+		bases = append(bases, base)
+	}
+	sort.Strings(bases)
+	logger.WithFields(logging.Fields{"count": len(bases), "date": today}).Info("Active prefixes discovered")
+
+	var conflicts, sameOrder, shadowed, uncovered []string
+	winsCount := make(map[string]int)
+	matchCount := make(map[string]int)
+	for _, base := range bases {
+		index := prefixes[base]
+		res := utils.ResolveTemplateForIndex(index, candidates)
+		if res.Winner == nil {
+			logger.WithFields(logging.Fields{"prefix": base, "index": index}).Warn("No template matches")
+			uncovered = append(uncovered, base)
+			continue
+		}
+		winsCount[templateLabel(*res.Winner)]++
+		matchCount[templateLabel(*res.Winner)]++
+		logger.WithFields(logging.Fields{"prefix": base, "index": index, "winner": templateLabel(*res.Winner), "priority": res.Winner.Priority, "patterns": strings.Join(res.Winner.Patterns, ", ")}).Info("Template resolved")
+		if len(res.Tied) > 0 {
+			names := []string{templateLabel(*res.Winner)}
+			for _, t := range res.Tied {
+				names = append(names, templateLabel(t))
+				matchCount[templateLabel(t)]++
+			}
+			logger.WithFields(logging.Fields{"prefix": base, "priority": res.Winner.Priority, "templates": strings.Join(names, ", ")}).Error("Equal priority overlap")
+			conflicts = append(conflicts, fmt.Sprintf("%s (priority=%d): %s", base, res.Winner.Priority, strings.Join(names, ", ")))
+		}
+		if len(res.Merged) > 0 {
+			names := []string{templateLabel(*res.Winner)}
+			for _, t := range res.Merged {
+				names = append(names, templateLabel(t))
+				winsCount[templateLabel(t)]++
+				matchCount[templateLabel(t)]++
+			}
+			logger.WithFields(logging.Fields{"prefix": base, "order": res.Winner.Priority, "templates": strings.Join(names, ", ")}).Warn("Legacy templates with equal order are merged in unspecified order")
+			sameOrder = append(sameOrder, fmt.Sprintf("%s (order=%d): %s", base, res.Winner.Priority, strings.Join(names, ", ")))
+		}
+		for _, t := range res.Shadowed {
+			matchCount[templateLabel(t)]++
+			logger.WithFields(logging.Fields{"prefix": base, "template": templateLabel(t), "priority": t.Priority, "winner": templateLabel(*res.Winner)}).Warn("Template shadowed")
+			shadowed = append(shadowed, fmt.Sprintf("%s: %s (priority=%d) shadowed by %s (priority=%d)", base, templateLabel(t), t.Priority, templateLabel(*res.Winner), res.Winner.Priority))
+		}
+	}
+
+	var neverWins []string
+	for name, n := range matchCount {
+		if n > 0 && winsCount[name] == 0 {
+			neverWins = append(neverWins, name)
+		}
+	}
+	sort.Strings(neverWins)
+
+	logger.Info(strings.Repeat("=", 60))
+	logger.Info("TEMPLATES CHECK SUMMARY")
+	logger.Info(strings.Repeat("=", 60))
+	logger.Info(fmt.Sprintf("Active prefixes: %d", len(bases)))
+	if len(conflicts) > 0 {
+		logger.Info(fmt.Sprintf("Equal priority overlaps: %d", len(conflicts)))
+		for _, c := range conflicts {
+			logger.Info("  ✗ " + c)
+		}
+	}
+	if len(sameOrder) > 0 {
+		logger.Info(fmt.Sprintf("Legacy templates with equal order: %d", len(sameOrder)))
+		for _, s := range sameOrder {
+			logger.Info("  ! " + s)
+		}
+	}
+	if len(shadowed) > 0 {
+		logger.Info(fmt.Sprintf("Shadowed templates: %d", len(shadowed)))
+		for _, s := range shadowed {
+			logger.Info("  - " + s)
+		}
+	}
+	if len(neverWins) > 0 {
+		logger.Info(fmt.Sprintf("Templates that never win for active prefixes: %d", len(neverWins)))
+		for _, n := range neverWins {
+			logger.Info("  - " + n)
+		}
+	}
+	if len(uncovered) > 0 {
+		logger.Info(fmt.Sprintf("Prefixes without template: %d", len(uncovered)))
+		for _, u := range uncovered {
+			logger.Info("  - " + u)
+		}
+	}
+	if len(conflicts) == 0 && len(sameOrder) == 0 && len(shadowed) == 0 && len(uncovered) == 0 {
+		logger.Info("  ✓ No template problems found")
+	}
+	logger.Info(strings.Repeat("=", 60))
+
+	if len(conflicts) > 0 {
+		return fmt.Errorf("found %d equal priority template overlaps", len(conflicts))
+	}
+	return nil
+}
+
+func templateLabel(t utils.TemplateCandidate) string {
+	if t.Legacy {
+		return t.Name + " (legacy)"
+	}
+	return t.Name
+}
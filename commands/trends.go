@@ -0,0 +1,116 @@
+package commands
+
+import (
+	"osctl/pkg/alerts"
+	"osctl/pkg/config"
+	"osctl/pkg/logging"
+	"osctl/pkg/opensearch"
+	"osctl/pkg/utils"
+	"strings"
+	"time"
+)
+
+const trendForecastHistoryDays = 30
+
+func collectSnapshotTrends(cfg *config.Config, client *opensearch.Client, repos []string, logger *logging.Logger) ([]utils.TrendAnomaly, []utils.WindowForecast) {
+	dateFormat := cfg.GetDateFormat()
+	baselineDays := cfg.GetTrendBaselineDays()
+	sizesCutoff := utils.FormatDate(time.Now().AddDate(0, 0, -(baselineDays+1)), dateFormat)
+
+	var anomalies []utils.TrendAnomaly
+	var forecasts []utils.WindowForecast
+	for _, repo := range repos {
+		snapshots, err := utils.GetSnapshotsIgnore404(client, repo, "*")
+		if err != nil {
+			logger.WithFields(logging.Fields{"repo": repo, "error": err}).Warn("Failed to get snapshots for trends")
+			continue
+		}
+		items := make([]utils.InventorySnapshot, 0, len(snapshots))
+		for _, s := range snapshots {
+			date := utils.ExtractDateFromIndex(s.Snapshot, dateFormat)
+			if date == "" || utils.IsSnapshotCopy(s.Snapshot) {
+				continue
+			}
+			item := utils.InventorySnapshot{
+				Snapshot:        s.Snapshot,
+				Date:            date,
+				State:           s.State,
+				Indices:         len(s.Indices),
+				StartTimeMillis: s.StartTimeInMillis,
+				DurationMillis:  s.DurationInMillis,
+			}
+			if s.State == "SUCCESS" && !utils.IsOlderThanCutoff(s.Snapshot, sizesCutoff, dateFormat) {
+				total, incremental, err := utils.SnapshotStatusSizes(client, repo, s.Snapshot)
+				if err != nil {
+					logger.WithFields(logging.Fields{"repo": repo, "snapshot": s.Snapshot, "error": err}).Warn("Failed to get snapshot sizes")
+				}
+				item.TotalBytes = total
+				item.IncrementalBytes = incremental
+			}
+			items = append(items, item)
+		}
+		for _, inv := range utils.BuildPrefixInventory(repo, items, dateFormat) {
+			anomalies = append(anomalies, utils.DetectTrendAnomalies(inv, baselineDays, cfg.GetTrendDurationFactor(), cfg.GetTrendRatioFactor())...)
+		}
+		forecasts = append(forecasts, utils.ForecastSnapshotWindow(repo, items, cfg.GetSnapshotWindow(), trendForecastHistoryDays, dateFormat))
+	}
+	return anomalies, forecasts
+}
+
+func reportSnapshotTrends(cfg *config.Config, client *opensearch.Client, repos []string, today string, notifier alerts.Notifier, logger *logging.Logger) {
+	anomalies, forecasts := collectSnapshotTrends(cfg, client, repos, logger)
+
+	var snapshots, details []string
+	for _, a := range anomalies {
+		if utils.ExtractDateFromIndex(a.Snapshot, cfg.GetDateFormat()) != today {
+			continue
+		}
+		snapshots = append(snapshots, a.Snapshot)
+		details = append(details, "- "+a.String())
+	}
+	var lateRepos, forecastDetails []string
+	for _, f := range forecasts {
+		if f.DaysLeft >= 0 && f.DaysLeft <= cfg.GetTrendForecastDays() {
+			lateRepos = append(lateRepos, f.Repo)
+			forecastDetails = append(forecastDetails, "- "+f.String())
+		}
+	}
+
+	logger.Info(strings.Repeat("=", 60))
+	logger.Info("SNAPSHOT TRENDS")
+	logger.Info(strings.Repeat("=", 60))
+	for _, f := range forecasts {
+		logger.Info("  - " + f.String())
+	}
+	for _, line := range details {
+		logger.Info("  ✗ " + strings.TrimPrefix(line, "- "))
+	}
+	if len(details) == 0 {
+		logger.Info("  ✓ No duration or incremental ratio anomalies today")
+	}
+	logger.Info(strings.Repeat("=", 60))
+
+	if len(snapshots) == 0 && len(lateRepos) == 0 {
+		return
+	}
+	if notifier == nil {
+		logger.Warn("No alert notifier is configured (alert-notifiers) — alert skipped")
+		return
+	}
+	if len(snapshots) > 0 {
+		response, err := notifier.Notify(alerts.NewSnapshotTrendEvent(snapshots, details))
+		if err != nil {
+			logger.WithField("error", err).Error("Failed to send alert")
+		} else {
+			logger.WithFields(logging.Fields{"type": "SnapshotTrendAnomaly", "count": len(snapshots), "notifiers": notifier.Name(), "response": response}).Info("Alert sent successfully")
+		}
+	}
+	if len(lateRepos) > 0 {
+		response, err := notifier.Notify(alerts.NewSnapshotWindowForecastEvent(lateRepos, forecastDetails))
+		if err != nil {
+			logger.WithField("error", err).Error("Failed to send alert")
+		} else {
+			logger.WithFields(logging.Fields{"type": "SnapshotWindowForecast", "count": len(lateRepos), "notifiers": notifier.Name(), "response": response}).Info("Alert sent successfully")
+		}
+	}
+}
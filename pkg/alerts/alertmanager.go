@@ -0,0 +1,72 @@
+package alerts
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type Alertmanager struct {
+	url        string
+	kibanaHost string
+	httpClient *http.Client
+}
+
+type alertmanagerAlert struct {
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       *time.Time        `json:"endsAt,omitempty"`
+	GeneratorURL string            `json:"generatorURL,omitempty"`
+}
+
+func NewAlertmanagerNotifier(url, kibanaHost string) *Alertmanager {
+	return &Alertmanager{
+		url:        strings.TrimRight(url, "/"),
+		kibanaHost: kibanaHost,
+		httpClient: newHTTPClient(),
+	}
+}
+
+func (a *Alertmanager) Name() string {
+	return "alertmanager"
+}
+
+func (a *Alertmanager) Notify(e Event) (string, error) {
+	if len(e.Items) == 0 {
+		return "", nil
+	}
+	batch := make([]alertmanagerAlert, 0, len(e.Items))
+	for _, subject := range e.Items {
+		labels := map[string]string{
+			"alertname":      string(e.Type),
+			"severity_level": e.Severity,
+			"source":         "osctl",
+			"subject":        subject,
+		}
+		if a.kibanaHost != "" {
+			labels["kibana"] = a.kibanaHost
+		}
+		alert := alertmanagerAlert{
+			Labels: labels,
+			Annotations: map[string]string{
+				"summary":     e.Summary,
+				"description": e.Description,
+				"indices":     strings.Join(e.Items, ","),
+			},
+			StartsAt:     e.Time,
+			GeneratorURL: a.kibanaHost,
+		}
+		if e.Resolved() {
+			alert.EndsAt = &e.Time
+		}
+		batch = append(batch, alert)
+	}
+	jsonData, err := json.Marshal(batch)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal alert: %v", err)
+	}
+	return postJSON(a.httpClient, "alertmanager", a.url+"/api/v2/alerts", jsonData)
+}
@@ -0,0 +1,83 @@
+package alerts
+
+import (
+	"strings"
+	"time"
+)
+
+func newEvent(t EventType, severity string, items []string, data TemplateData) Event {
+	return Event{
+		Type:     t,
+		Status:   StatusFiring,
+		Severity: severity,
+		Items:    items,
+		Data:     data,
+		Time:     time.Now().UTC(),
+	}
+}
+
+func NewResolvedEvent(t EventType, severity string, items []string) Event {
+	e := newEvent(t, severity, items, TemplateData{})
+	e.Status = StatusResolved
+	return e
+}
+
+func NewRestoreForeignEvent(indices []string, namespace, dateStr string) Event {
+	return newEvent(RestoreForeign, "4", indices, TemplateData{Namespace: namespace, Date: dateStr})
+}
+
+func NewSnapshotStateFailedEvent(snapshotName, state, snapRepo, namespace, dateStr string) Event {
+	return newEvent(SnapshotStateFailed, "4", []string{snapshotName}, TemplateData{Snapshot: snapshotName, State: state, Repository: snapRepo, Namespace: namespace, Date: dateStr})
+}
+
+func NewRestoreFailedEvent(snapshotName, indexName, snapRepo, namespace, dateStr string) Event {
+	return newEvent(RestoreFailed, "4", []string{indexName}, TemplateData{Snapshot: snapshotName, Index: indexName, Repository: snapRepo, Namespace: namespace, Date: dateStr})
+}
+
+func NewSnapshotsMissingEvent(indices []string, snapRepo, namespace, dateStr string) Event {
+	return newEvent(SnapshotsMissing, "5", indices, TemplateData{Repository: snapRepo, Namespace: namespace, Date: dateStr})
+}
+
+func NewDanglingIndicesEvent(indices []string, kibanaHost string) Event {
+	return newEvent(DanglingIndices, "4", indices, TemplateData{Kibana: kibanaHost})
+}
+
+func NewSnapshotCreationFailedEvent(snapshotName, indexName, snapRepo, namespace, dateStr string) Event {
+	return newEvent(SnapshotCreationFailed, "4", strings.Split(indexName, ","), TemplateData{Snapshot: snapshotName, Index: indexName, Repository: snapRepo, Namespace: namespace, Date: dateStr})
+}
+
+func NewMappingFieldsLimitEvent(usages []MappingUsage) Event {
+	indices := make([]string, 0, len(usages))
+	for _, u := range usages {
+		indices = append(indices, u.Index)
+	}
+	return newEvent(MappingFieldsLimit, "4", indices, TemplateData{Mapping: usages})
+}
+
+func NewClusterHealthEvent(status string, indices []string, groups []AllocationGroup) Event {
+	severity := "5"
+	if status == "red" {
+		severity = "4"
+	}
+	return newEvent(ClusterHealthDegraded, severity, indices, TemplateData{HealthStatus: status, Allocation: groups})
+}
+
+func NewSnapshotVerifyFailedEvent(snapshots []string, details []string) Event {
+	return newEvent(SnapshotVerifyFailed, "4", snapshots, TemplateData{Details: details})
+}
+
+func NewSnapshotCopyFailedEvent(snapshots []string, details []string) Event {
+	return newEvent(SnapshotCopyFailed, "4", snapshots, TemplateData{Details: details})
+}
+
+func NewSnapshotCopyMissingEvent(snapshots []string, details []string) Event {
+	return newEvent(SnapshotCopyMissing, "4", snapshots, TemplateData{Details: details})
+}
+
+func NewSnapshotTrendEvent(snapshots []string, details []string) Event {
+	return newEvent(SnapshotTrendAnomaly, "4", snapshots, TemplateData{Details: details})
+}
+
+func NewSnapshotWindowForecastEvent(repos []string, details []string) Event {
+	return newEvent(SnapshotWindowForecast, "4", repos, TemplateData{Details: details})
+}
@@ -0,0 +1,160 @@
+package alerts
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type EventType string
+
+const (
+	SnapshotsMissing       EventType = "SnapshotsMissing"
+	SnapshotCreationFailed EventType = "SnapshotCreationFailed"
+	DanglingIndices        EventType = "DanglingIndices"
+	RestoreFailed          EventType = "SnapshotRestoreFailed"
+	RestoreForeign         EventType = "SnapshotRestoreForeign"
+	SnapshotStateFailed    EventType = "SnapshotStateFailed"
+	MappingFieldsLimit     EventType = "MappingFieldsLimit"
+	ClusterHealthDegraded  EventType = "ClusterHealthDegraded"
+	SnapshotVerifyFailed   EventType = "SnapshotVerifyFailed"
+	SnapshotCopyFailed     EventType = "SnapshotCopyFailed"
+	SnapshotCopyMissing    EventType = "SnapshotCopyMissing"
+	SnapshotTrendAnomaly   EventType = "SnapshotTrendAnomaly"
+	SnapshotWindowForecast EventType = "SnapshotWindowForecast"
+)
+
+var EventTypes = []EventType{
+	SnapshotsMissing,
+	SnapshotCreationFailed,
+	DanglingIndices,
+	RestoreFailed,
+	RestoreForeign,
+	SnapshotStateFailed,
+	MappingFieldsLimit,
+	ClusterHealthDegraded,
+	SnapshotVerifyFailed,
+	SnapshotCopyFailed,
+	SnapshotCopyMissing,
+	SnapshotTrendAnomaly,
+	SnapshotWindowForecast,
+}
+
+func KnownEventType(name string) bool {
+	for _, t := range EventTypes {
+		if string(t) == name {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	StatusFiring   = "firing"
+	StatusResolved = "resolved"
+)
+
+type Event struct {
+	Type        EventType    `json:"type"`
+	Status      string       `json:"status"`
+	Severity    string       `json:"severity"`
+	Items       []string     `json:"items"`
+	Summary     string       `json:"summary"`
+	Description string       `json:"description"`
+	Data        TemplateData `json:"data"`
+	Time        time.Time    `json:"time"`
+}
+
+func (e Event) Resolved() bool {
+	return e.Status == StatusResolved
+}
+
+func (e Event) ItemsList() string {
+	if len(e.Items) > 3 {
+		return strings.Join(e.Items[:3], ",") + ",..."
+	}
+	return strings.Join(e.Items, ",")
+}
+
+type Notifier interface {
+	Name() string
+	Notify(e Event) (string, error)
+}
+
+type Multi []Notifier
+
+func (m Multi) Name() string {
+	names := make([]string, 0, len(m))
+	for _, n := range m {
+		names = append(names, n.Name())
+	}
+	return strings.Join(names, ",")
+}
+
+func (m Multi) Notify(e Event) (string, error) {
+	var responses []string
+	var errs []error
+	for _, n := range m {
+		response, err := n.Notify(e)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", n.Name(), err))
+			continue
+		}
+		responses = append(responses, fmt.Sprintf("%s=%s", n.Name(), strings.TrimSpace(response)))
+	}
+	if len(errs) > 0 && len(responses) > 0 {
+		return strings.Join(responses, " "), &PartialError{Err: errors.Join(errs...)}
+	}
+	return strings.Join(responses, " "), errors.Join(errs...)
+}
+
+type PartialError struct {
+	Err error
+}
+
+func (e *PartialError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PartialError) Unwrap() error {
+	return e.Err
+}
+
+func Delivered(err error) bool {
+	var partial *PartialError
+	return err == nil || errors.As(err, &partial)
+}
+
+func newHTTPClient() *http.Client {
+	return &http.Client{Timeout: 30 * time.Second}
+}
+
+func postJSON(httpClient *http.Client, name, url string, payload []byte) (string, error) {
+	if url == "" {
+		return "", fmt.Errorf("%s URL is required", name)
+	}
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(payload))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode == 403 {
+		return "", fmt.Errorf("%s API returned 403 Forbidden - check key and permissions", name)
+	}
+	if resp.StatusCode >= 400 {
+		return "", fmt.Errorf("%s API returned status %d: %s", name, resp.StatusCode, string(body))
+	}
+	return string(body), nil
+}
@@ -0,0 +1,32 @@
+package alerts
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type Slack struct {
+	url        string
+	httpClient *http.Client
+}
+
+func NewSlackNotifier(url string) *Slack {
+	return &Slack{url: url, httpClient: newHTTPClient()}
+}
+
+func (s *Slack) Name() string {
+	return "slack"
+}
+
+func (s *Slack) Notify(e Event) (string, error) {
+	if len(e.Items) == 0 {
+		return "", nil
+	}
+	text := fmt.Sprintf("*[%s] %s*\n%s", e.Type, e.Summary, e.Description)
+	jsonData, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal alert: %v", err)
+	}
+	return postJSON(s.httpClient, "slack", s.url, jsonData)
+}
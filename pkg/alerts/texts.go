@@ -0,0 +1,201 @@
+package alerts
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates
+var builtinTemplates embed.FS
+
+var Languages = []string{"ru", "en"}
+
+const resolvedTemplate = "Resolved"
+
+type MappingUsage struct {
+	Index   string   `json:"index"`
+	Fields  int      `json:"fields"`
+	Limit   int      `json:"limit"`
+	Percent float64  `json:"percent"`
+	Grown   []string `json:"grown,omitempty"`
+}
+
+type AllocationGroup struct {
+	Reason  string   `json:"reason"`
+	Shards  int      `json:"shards"`
+	Indices []string `json:"indices"`
+	Advice  string   `json:"advice"`
+}
+
+type TemplateData struct {
+	Type         EventType         `json:"type"`
+	Status       string            `json:"status"`
+	Items        []string          `json:"items"`
+	Snapshot     string            `json:"snapshot,omitempty"`
+	Index        string            `json:"index,omitempty"`
+	Repository   string            `json:"repository,omitempty"`
+	Namespace    string            `json:"namespace,omitempty"`
+	Date         string            `json:"date,omitempty"`
+	State        string            `json:"state,omitempty"`
+	HealthStatus string            `json:"health_status,omitempty"`
+	Details      []string          `json:"details,omitempty"`
+	Mapping      []MappingUsage    `json:"mapping,omitempty"`
+	Allocation   []AllocationGroup `json:"allocation,omitempty"`
+	Kibana       string            `json:"kibana,omitempty"`
+	Cluster      string            `json:"cluster,omitempty"`
+	Pod          string            `json:"pod,omitempty"`
+	RunLink      string            `json:"run_link,omitempty"`
+}
+
+type TextOptions struct {
+	Language     string
+	OverridesDir string
+	RunLink      string
+	Cluster      string
+	Kibana       string
+	Namespace    string
+	Pod          string
+}
+
+type Texts struct {
+	opts      TextOptions
+	templates map[string]*template.Template
+	runLink   *template.Template
+}
+
+var templateFuncs = template.FuncMap{
+	"join": strings.Join,
+	"short": func(items []string) string {
+		if len(items) > 3 {
+			return strings.Join(items[:3], ",") + ",..."
+		}
+		return strings.Join(items, ",")
+	},
+	"more":  func(items []string) bool { return len(items) > 3 },
+	"lines": func(items []string) string { return strings.Join(items, "\n") },
+}
+
+func KnownLanguage(lang string) bool {
+	for _, l := range Languages {
+		if l == lang {
+			return true
+		}
+	}
+	return false
+}
+
+func NewTexts(opts TextOptions) (*Texts, error) {
+	if !KnownLanguage(opts.Language) {
+		return nil, fmt.Errorf("unknown alert language %q (supported: %s)", opts.Language, strings.Join(Languages, ", "))
+	}
+	common, err := builtinTemplates.ReadFile(fmt.Sprintf("templates/%s/common.tmpl", opts.Language))
+	if err != nil {
+		return nil, err
+	}
+	texts := &Texts{opts: opts, templates: make(map[string]*template.Template)}
+	names := []string{resolvedTemplate}
+	for _, t := range EventTypes {
+		names = append(names, string(t))
+	}
+	for _, name := range names {
+		builtin, err := builtinTemplates.ReadFile(fmt.Sprintf("templates/%s/%s.tmpl", opts.Language, name))
+		if err != nil {
+			return nil, err
+		}
+		tmpl := template.New(name).Funcs(templateFuncs)
+		if _, err := tmpl.Parse(string(common)); err != nil {
+			return nil, fmt.Errorf("failed to parse common %s template: %v", opts.Language, err)
+		}
+		if _, err := tmpl.Parse(string(builtin)); err != nil {
+			return nil, fmt.Errorf("failed to parse %s/%s template: %v", opts.Language, name, err)
+		}
+		if opts.OverridesDir != "" {
+			path := filepath.Join(opts.OverridesDir, name+".tmpl")
+			override, err := os.ReadFile(path)
+			if err != nil && !os.IsNotExist(err) {
+				return nil, fmt.Errorf("failed to read alert template %s: %v", path, err)
+			}
+			if err == nil {
+				if _, err := tmpl.Parse(string(override)); err != nil {
+					return nil, fmt.Errorf("failed to parse alert template %s: %v", path, err)
+				}
+			}
+		}
+		texts.templates[name] = tmpl
+	}
+	if opts.RunLink != "" {
+		texts.runLink, err = template.New("run-link").Funcs(templateFuncs).Parse(opts.RunLink)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse alert run link template: %v", err)
+		}
+	}
+	return texts, nil
+}
+
+func (t *Texts) Render(e Event) (Event, error) {
+	data := e.Data
+	data.Type = e.Type
+	data.Status = e.Status
+	data.Items = e.Items
+	data.Cluster = t.opts.Cluster
+	data.Pod = t.opts.Pod
+	if data.Kibana == "" {
+		data.Kibana = t.opts.Kibana
+	}
+	if data.Namespace == "" {
+		data.Namespace = t.opts.Namespace
+	}
+	if t.runLink != nil {
+		var buf bytes.Buffer
+		if err := t.runLink.Execute(&buf, data); err != nil {
+			return e, fmt.Errorf("failed to render alert run link: %v", err)
+		}
+		data.RunLink = strings.TrimSpace(buf.String())
+	}
+
+	name := string(e.Type)
+	if e.Resolved() {
+		name = resolvedTemplate
+	}
+	tmpl, ok := t.templates[name]
+	if !ok {
+		return e, fmt.Errorf("no alert template for %s", name)
+	}
+	var summary, description bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&summary, "summary", data); err != nil {
+		return e, fmt.Errorf("failed to render %s summary: %v", name, err)
+	}
+	if err := tmpl.ExecuteTemplate(&description, "description", data); err != nil {
+		return e, fmt.Errorf("failed to render %s description: %v", name, err)
+	}
+	e.Data = data
+	e.Summary = strings.TrimSpace(summary.String())
+	e.Description = strings.TrimSpace(description.String())
+	return e, nil
+}
+
+type textsNotifier struct {
+	next  Notifier
+	texts *Texts
+}
+
+func WithTexts(next Notifier, texts *Texts) Notifier {
+	return &textsNotifier{next: next, texts: texts}
+}
+
+func (n *textsNotifier) Name() string {
+	return n.next.Name()
+}
+
+func (n *textsNotifier) Notify(e Event) (string, error) {
+	rendered, err := n.texts.Render(e)
+	if err != nil {
+		return "", err
+	}
+	return n.next.Notify(rendered)
+}
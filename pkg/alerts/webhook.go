@@ -0,0 +1,64 @@
+package alerts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"text/template"
+)
+
+const DefaultWebhookTemplate = `{{ json . }}`
+
+type Webhook struct {
+	url        string
+	kibanaHost string
+	tmpl       *template.Template
+	httpClient *http.Client
+}
+
+type webhookData struct {
+	Event
+	ItemsShort string `json:"items_short"`
+	Kibana     string `json:"kibana,omitempty"`
+}
+
+func ParseWebhookTemplate(text string) (*template.Template, error) {
+	if strings.TrimSpace(text) == "" {
+		text = DefaultWebhookTemplate
+	}
+	return template.New("webhook").Funcs(template.FuncMap{
+		"json": func(v any) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+		"join": strings.Join,
+	}).Parse(text)
+}
+
+func NewWebhookNotifier(url, kibanaHost, templateText string) (*Webhook, error) {
+	tmpl, err := ParseWebhookTemplate(templateText)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook template: %v", err)
+	}
+	return &Webhook{url: url, kibanaHost: kibanaHost, tmpl: tmpl, httpClient: newHTTPClient()}, nil
+}
+
+func (w *Webhook) Name() string {
+	return "webhook"
+}
+
+func (w *Webhook) Notify(e Event) (string, error) {
+	if len(e.Items) == 0 {
+		return "", nil
+	}
+	var body bytes.Buffer
+	if err := w.tmpl.Execute(&body, webhookData{Event: e, ItemsShort: e.ItemsList(), Kibana: w.kibanaHost}); err != nil {
+		return "", fmt.Errorf("failed to render webhook template: %v", err)
+	}
+	if !json.Valid(body.Bytes()) {
+		return "", fmt.Errorf("webhook template rendered invalid JSON")
+	}
+	return postJSON(w.httpClient, "webhook", w.url, body.Bytes())
+}
@@ -0,0 +1,138 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+type Record struct {
+	Timestamp time.Time `json:"@timestamp"`
+	Actor     string    `json:"actor"`
+	Policy    string    `json:"policy,omitempty"`
+	RunID     string    `json:"runId,omitempty"`
+	Cluster   string    `json:"cluster,omitempty"`
+	Action    string    `json:"action"`
+	Target    string    `json:"target"`
+	Reason    string    `json:"reason,omitempty"`
+	Result    string    `json:"result"`
+	Error     string    `json:"error,omitempty"`
+}
+
+type Sink interface {
+	Write(r Record) error
+}
+
+type DocWriter interface {
+	CreateDoc(index, id string, payload interface{}) error
+}
+
+type FileSink struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileSink(path string) *FileSink {
+	return &FileSink{path: path}
+}
+
+func (s *FileSink) Write(r Record) error {
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open audit file %s: %v", s.path, err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit file %s: %v", s.path, err)
+	}
+	return nil
+}
+
+type IndexSink struct {
+	client DocWriter
+	index  string
+}
+
+func NewIndexSink(client DocWriter, index string) *IndexSink {
+	return &IndexSink{client: client, index: index}
+}
+
+func (s *IndexSink) Write(r Record) error {
+	if err := s.client.CreateDoc(s.index, uuid.NewString(), r); err != nil {
+		return fmt.Errorf("failed to write audit record to %s: %v", s.index, err)
+	}
+	return nil
+}
+
+var (
+	mu      sync.RWMutex
+	sinks   []Sink
+	context Record
+	onError = func(err error) {
+		fmt.Fprintf(os.Stderr, "audit: %v\n", err)
+	}
+)
+
+func Configure(actor, cluster string, s ...Sink) {
+	mu.Lock()
+	defer mu.Unlock()
+	sinks = s
+	context.Actor = actor
+	context.Cluster = cluster
+}
+
+func SetRun(policy, runID string) {
+	mu.Lock()
+	defer mu.Unlock()
+	context.Policy = policy
+	context.RunID = runID
+}
+
+func OnError(fn func(err error)) {
+	mu.Lock()
+	defer mu.Unlock()
+	onError = fn
+}
+
+func Log(r Record, err error) {
+	mu.RLock()
+	ctx := context
+	active := sinks
+	report := onError
+	mu.RUnlock()
+	if len(active) == 0 {
+		return
+	}
+	r.Timestamp = time.Now().UTC()
+	r.Actor = ctx.Actor
+	r.Policy = ctx.Policy
+	r.RunID = ctx.RunID
+	if r.Cluster == "" {
+		r.Cluster = ctx.Cluster
+	}
+	r.Result = ResultSuccess
+	if err != nil {
+		r.Result = ResultFailure
+		r.Error = err.Error()
+	}
+	for _, s := range active {
+		if werr := s.Write(r); werr != nil {
+			report(werr)
+		}
+	}
+}
@@ -2,7 +2,10 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
+	"osctl/pkg/alerts"
+	"osctl/pkg/logging"
 	"strconv"
 	"strings"
 	"time"
@@ -12,69 +15,150 @@ import (
 )
 
 type Config struct {
-	Action                             string
-	OpenSearchURL                      string
-	OpenSearchRecovererURL             string
-	CertFile                           string
-	KeyFile                            string
-	CAFile                             string
-	InsecureSkipVerify                 string
-	BasicAuthUser                      string
-	BasicAuthPass                      string
-	Timeout                            string
-	RetryAttempts                      string
-	DateFormat                         string
-	RecovererDateFormat                string
-	MadisonURL                         string
-	OSDURL                             string
-	MadisonKey                         string
-	SnapshotRepo                       string
-	RetentionThreshold                 string
-	RetentionDaysCount                 string
-	RetentionCheckSnapshots            string
-	RetentionCheckNodesDown            string
-	IndicesDeleteCheckSnapshots        string
-	DereplicatorDaysCount              string
-	DereplicatorUseSnapshot            string
-	DataSourceName                     string
-	KibanaUser                         string
-	KibanaPass                         string
-	HotCount                           string
-	ColdAttribute                      string
-	ExtractedPattern                   string
-	ExtractedDays                      string
-	DryRun                             string
-	ShardingTargetSizeGiB              string
-	ShardingExcludeRegex               string
-	ShardingRoutingAllocationTemp      string
-	KibanaIndexRegex                   string
-	KubeNamespace                      string
-	SnapshotManualKind                 string
-	SnapshotManualValue                string
-	SnapshotManualName                 string
-	SnapshotManualSystem               string
-	SnapshotManualDaysCount            string
-	SnapshotManualCountS3              string
-	SnapshotManualRepo                 string
-	OSCTLConfig                        string
-	OSCTLIndicesConfig                 string
-	OsctlIndicesConfig                 *OsctlIndicesConfig
-	OSCTLTenantsConfig                 string
-	KibanaMultidomainEnabled           string
-	DataSourceKibanaMultitenancy       string
-	DataSourceKibanaMultidomainEnabled string
-	DataSourceRemoteCRT                string
-	DataSourceEndpoint                 string
-	IndexPatternsKibanaMultitenancy    string
-	IndexPatternsKibanaTenantsConfig   string
-	IndexPatternsRecovererEnabled      string
-	IndexPatternsRefreshEnabled        string
-	SnapshotsBackfillIndicesList       string
-	MaxConcurrentSnapshots             string
-	RestoreIndexFilter                 string
-	RestoreDaysCount                   string
-	RestoreDate                        string
-	ES5Compatibility                   string
+	Action                             string              `yaml:"action" flag:"action"`
+	OpenSearchURL                      string              `yaml:"opensearch_url" flag:"os-url"`
+	OpenSearchRecovererURL             string              `yaml:"opensearch_recoverer_url" flag:"os-recoverer-url"`
+	CertFile                           string              `yaml:"cert_file" flag:"cert-file"`
+	KeyFile                            string              `yaml:"key_file" flag:"key-file"`
+	CAFile                             string              `yaml:"ca_file" flag:"ca-file"`
+	InsecureSkipVerify                 string              `yaml:"insecure_skip_verify" flag:"insecure-skip-verify" type:"bool"`
+	BasicAuthUser                      string              `yaml:"basic_auth_user" flag:"basic-auth-user"`
+	BasicAuthPass                      string              `yaml:"basic_auth_pass" flag:"basic-auth-pass"`
+	Timeout                            string              `yaml:"timeout" flag:"timeout"`
+	RetryAttempts                      string              `yaml:"retry_attempts" flag:"retry-attempts"`
+	DateFormat                         string              `yaml:"date_format" flag:"date-format"`
+	RecovererDateFormat                string              `yaml:"recoverer_date_format" flag:"recoverer-date-format"`
+	MadisonURL                         string              `yaml:"madison_url" flag:"madison-url"`
+	OSDURL                             string              `yaml:"osd_url" flag:"osd-url"`
+	MadisonKey                         string              `yaml:"madison_key" flag:"madison-key"`
+	SnapshotRepo                       string              `yaml:"snapshot_repo" flag:"snap-repo"`
+	RetentionThreshold                 string              `yaml:"retention_threshold" flag:"retention-threshold" type:"float64"`
+	RetentionDaysCount                 string              `yaml:"retention_days_count" flag:"retention-days-count"`
+	RetentionCheckSnapshots            string              `yaml:"retention_check_snapshots" flag:"retention-check-snapshots"`
+	RetentionCheckNodesDown            string              `yaml:"retention_check_nodes_down" flag:"retention-check-nodes-down"`
+	IndicesDeleteCheckSnapshots        string              `yaml:"indicesdelete_check_snapshots" flag:"indicesdelete-check-snapshots"`
+	DereplicatorDaysCount              string              `yaml:"dereplicator_days_count" flag:"dereplicator-days-count"`
+	DereplicatorUseSnapshot            string              `yaml:"dereplicator_use_snapshot" flag:"dereplicator-use-snapshot"`
+	DataSourceName                     string              `yaml:"datasource_name" flag:"datasource-name"`
+	KibanaUser                         string              `yaml:"kibana_user" flag:"kibana-user"`
+	KibanaPass                         string              `yaml:"kibana_pass" flag:"kibana-pass"`
+	HotCount                           string              `yaml:"hot_count" flag:"hot-count"`
+	ColdAttribute                      string              `yaml:"cold_attribute" flag:"cold-attribute"`
+	ExtractedPattern                   string              `yaml:"extracted_pattern" flag:"extracted-pattern"`
+	ExtractedDays                      string              `yaml:"extracted_days" flag:"days"`
+	DryRun                             string              `yaml:"dry_run" flag:"dry-run"`
+	ShardingTargetSizeGiB              string              `yaml:"sharding_target_size_gib" flag:"sharding-target-size-gib"`
+	ShardingExcludeRegex               string              `yaml:"exclude_sharding" flag:"exclude-sharding"`
+	ShardingRoutingAllocationTemp      string              `yaml:"sharding_routing_allocation_temp" flag:"sharding-routing-allocation-temp"`
+	KibanaIndexRegex                   string              `yaml:"kibana_index_regex" flag:"kibana-index-regex"`
+	KubeNamespace                      string              `yaml:"kube_namespace" flag:"kube-namespace"`
+	SnapshotManualKind                 string              `yaml:"snapshot_manual_kind" flag:"snapshot-manual-kind"`
+	SnapshotManualValue                string              `yaml:"snapshot_manual_value" flag:"snapshot-manual-value"`
+	SnapshotManualName                 string              `yaml:"snapshot_manual_name" flag:"snapshot-manual-name"`
+	SnapshotManualSystem               string              `yaml:"snapshot_manual_system" flag:"snapshot-manual-system"`
+	SnapshotManualDaysCount            string              `yaml:"snapshot_manual_days_count" flag:"snapshot-manual-days-count"`
+	SnapshotManualCountS3              string              `yaml:"snapshot_manual_count_s3" flag:"snapshot-manual-count-s3"`
+	SnapshotManualRepo                 string              `yaml:"snapshot_manual_repo" flag:"snapshot-manual-repo"`
+	OSCTLConfig                        string              `yaml:"osctl_config" flag:"config"`
+	OSCTLIndicesConfig                 string              `yaml:"osctl_indices_config" flag:"osctl-indices-config"`
+	OsctlIndicesConfig                 *OsctlIndicesConfig `yaml:"-"`
+	Repositories                       []RepositoryConfig  `yaml:"repositories"`
+	OSCTLTenantsConfig                 string              `yaml:"kibana_tenants_config" flag:"kibana-tenants-config"`
+	KibanaMultidomainEnabled           string              `yaml:"kibana_multidomain_enabled" flag:"kibana-multidomain-enabled" type:"bool"`
+	DataSourceKibanaMultitenancy       string              `yaml:"datasource_kibana_multitenancy" flag:"datasource-kibana-multitenancy"`
+	DataSourceKibanaMultidomainEnabled string              `yaml:"datasource_kibana_multidomain_enabled" flag:"datasource-kibana-multidomain-enabled"`
+	DataSourceRemoteCRT                string              `yaml:"datasource_remote_crt" flag:"datasource-remote-crt"`
+	DataSourceEndpoint                 string              `yaml:"datasource_endpoint" flag:"datasource-endpoint"`
+	IndexPatternsKibanaMultitenancy    string              `yaml:"indexpatterns_kibana_multitenancy" flag:"indexpatterns-kibana-multitenancy"`
+	IndexPatternsKibanaTenantsConfig   string              `yaml:"indexpatterns_kibana_tenants_config" flag:"indexpatterns-kibana-tenants-config"`
+	IndexPatternsRecovererEnabled      string              `yaml:"indexpatterns_recoverer_enabled" flag:"indexpatterns-recoverer-enabled"`
+	IndexPatternsRefreshEnabled        string              `yaml:"indexpatterns_refresh_enabled" flag:"indexpatterns-refresh-enabled"`
+	SnapshotsBackfillIndicesList       string              `yaml:"snapshots_backfill_indices_list" flag:"indices-list"`
+	MaxConcurrentSnapshots             string              `yaml:"max_concurrent_snapshots" flag:"max-concurrent-snapshots"`
+	RestoreIndexFilter                 string              `yaml:"restore_index_filter" flag:"index-filter"`
+	RestoreDaysCount                   string              `yaml:"restore_days_count" flag:"days"`
+	RestoreDate                        string              `yaml:"restore_date" flag:"date"`
+	RestoreDateFrom                    string              `yaml:"restore_date_from" flag:"date-from"`
+	RestoreDateTo                      string              `yaml:"restore_date_to" flag:"date-to"`
+	RestorePrefixes                    string              `yaml:"restore_prefix" flag:"prefix"`
+	RestoreTarget                      string              `yaml:"restore_target" flag:"restore-target"`
+	RestoreRenameTemplate              string              `yaml:"restore_rename_template" flag:"restore-rename-template"`
+	ES5Compatibility                   string              `yaml:"es5_compatibility" flag:"es5-compatibility"`
+	MappingCheckerThreshold            string              `yaml:"mappingchecker_threshold" flag:"mappingchecker-threshold"`
+	MappingCheckerTopFields            string              `yaml:"mappingchecker_top_fields" flag:"mappingchecker-top-fields"`
+	MappingCheckerRaiseLimit           string              `yaml:"mappingchecker_raise_limit" flag:"mappingchecker-raise-limit"`
+	MappingCheckerLimitStep            string              `yaml:"mappingchecker_limit_step" flag:"mappingchecker-limit-step"`
+	HealthCheckerRerouteFailed         string              `yaml:"healthchecker_reroute_failed" flag:"reroute-failed"`
+	HealthCheckerMaxExplain            string              `yaml:"healthchecker_max_explain" flag:"healthchecker-max-explain"`
+	DanglingResolve                    string              `yaml:"dangling_resolve" flag:"dangling-resolve"`
+	RepositoriesVerifyOnly             string              `yaml:"repositories_verify_only" flag:"repositories-verify-only"`
+	SnapshotVerifySampleSize           string              `yaml:"snapshotverify_sample_size" flag:"snapshotverify-sample-size"`
+	SnapshotVerifyTarget               string              `yaml:"snapshotverify_target" flag:"snapshotverify-target"`
+	SnapshotVerifyTempPrefix           string              `yaml:"snapshotverify_temp_prefix" flag:"snapshotverify-temp-prefix"`
+	SnapshotVerifyTimeout              string              `yaml:"snapshotverify_timeout" flag:"snapshotverify-timeout"`
+	InventoryFormat                    string              `yaml:"inventory_format" flag:"inventory-format"`
+	InventoryOutput                    string              `yaml:"inventory_output" flag:"inventory-output"`
+	InventorySizes                     string              `yaml:"inventory_sizes" flag:"inventory-sizes"`
+	ExtractTimeFrom                    string              `yaml:"extract_time_from" flag:"extract-time-from"`
+	ExtractTimeTo                      string              `yaml:"extract_time_to" flag:"extract-time-to"`
+	ExtractQuery                       string              `yaml:"extract_query" flag:"extract-query"`
+	ExtractTimeField                   string              `yaml:"extract_time_field" flag:"extract-time-field"`
+	ExtractTenant                      string              `yaml:"extract_tenant" flag:"extract-tenant"`
+	ExtractTimeout                     string              `yaml:"extract_timeout" flag:"extract-timeout"`
+	SnapshotCopyMode                   string              `yaml:"snapshotcopy_mode" flag:"snapshotcopy-mode"`
+	SnapshotCopyScratchURL             string              `yaml:"opensearch_scratch_url" flag:"os-scratch-url"`
+	SnapshotCopyMaxCopies              string              `yaml:"snapshotcopy_max_copies" flag:"snapshotcopy-max-copies"`
+	SnapshotCopyTimeout                string              `yaml:"snapshotcopy_timeout" flag:"snapshotcopy-timeout"`
+	StateStore                         string              `yaml:"state_store" flag:"state-store"`
+	StateIndex                         string              `yaml:"state_index" flag:"state-index"`
+	StateFile                          string              `yaml:"state_file" flag:"state-file"`
+	StatusAll                          string              `yaml:"status_all" flag:"status-all"`
+	StatusFormat                       string              `yaml:"status_format" flag:"status-format"`
+	ExplainFormat                      string              `yaml:"explain_format" flag:"explain-format"`
+	LintLive                           string              `yaml:"lint_live" flag:"lint-live"`
+	SnapshotTrends                     string              `yaml:"snapshot_trends" flag:"snapshot-trends"`
+	TrendBaselineDays                  string              `yaml:"trend_baseline_days" flag:"trend-baseline-days"`
+	TrendDurationFactor                string              `yaml:"trend_duration_factor" flag:"trend-duration-factor"`
+	TrendRatioFactor                   string              `yaml:"trend_ratio_factor" flag:"trend-ratio-factor"`
+	SnapshotWindow                     string              `yaml:"snapshot_window" flag:"snapshot-window"`
+	TrendForecastDays                  string              `yaml:"trend_forecast_days" flag:"trend-forecast-days"`
+	AdaptiveConcurrency                string              `yaml:"adaptive_concurrency" flag:"adaptive-concurrency"`
+	MinConcurrentSnapshots             string              `yaml:"min_concurrent_snapshots" flag:"min-concurrent-snapshots"`
+	AdaptiveInterval                   string              `yaml:"adaptive_interval" flag:"adaptive-interval"`
+	AdaptiveMaxCPU                     string              `yaml:"adaptive_max_cpu" flag:"adaptive-max-cpu"`
+	AdaptiveMaxLoad                    string              `yaml:"adaptive_max_load" flag:"adaptive-max-load"`
+	AdaptiveMaxQueue                   string              `yaml:"adaptive_max_queue" flag:"adaptive-max-queue"`
+	AdaptiveMaxRecoveryThrottle        string              `yaml:"adaptive_max_recovery_throttle" flag:"adaptive-max-recovery-throttle"`
+	AlertNotifiers                     string              `yaml:"alert_notifiers" flag:"alert-notifiers"`
+	AlertmanagerURL                    string              `yaml:"alertmanager_url" flag:"alertmanager-url"`
+	AlertWebhookURL                    string              `yaml:"alert_webhook_url" flag:"alert-webhook-url"`
+	AlertWebhookTemplate               string              `yaml:"alert_webhook_template" flag:"alert-webhook-template"`
+	SlackWebhookURL                    string              `yaml:"slack_webhook_url" flag:"slack-webhook-url"`
+	AlertStateStore                    string              `yaml:"alert_state_store" flag:"alert-state-store"`
+	AlertStateIndex                    string              `yaml:"alert_state_index" flag:"alert-state-index"`
+	AlertStateFile                     string              `yaml:"alert_state_file" flag:"alert-state-file"`
+	AlertDedupWindow                   string              `yaml:"alert_dedup_window" flag:"alert-dedup-window"`
+	AlertResolve                       string              `yaml:"alert_resolve" flag:"alert-resolve"`
+	SilenceTrigger                     string              `yaml:"silence_trigger" flag:"silence-trigger"`
+	SilenceSubject                     string              `yaml:"silence_subject" flag:"silence-subject"`
+	SilenceDuration                    string              `yaml:"silence_duration" flag:"silence-duration"`
+	SilenceReason                      string              `yaml:"silence_reason" flag:"silence-reason"`
+	SilenceID                          string              `yaml:"silence_id" flag:"silence-id"`
+	AlertLanguage                      string              `yaml:"alert_language" flag:"alert-language"`
+	AlertTemplatesDir                  string              `yaml:"alert_templates_dir" flag:"alert-templates-dir"`
+	AlertClusterName                   string              `yaml:"alert_cluster_name" flag:"alert-cluster-name"`
+	AlertRunLink                       string              `yaml:"alert_run_link" flag:"alert-run-link"`
+	MetricsListen                      string              `yaml:"metrics_listen" flag:"metrics-listen"`
+	MetricsPushgatewayURL              string              `yaml:"metrics_pushgateway_url" flag:"metrics-pushgateway-url"`
+	MetricsJob                         string              `yaml:"metrics_job" flag:"metrics-job"`
+	MetricsTextfileDir                 string              `yaml:"metrics_textfile_dir" flag:"metrics-textfile-dir"`
+	DaemonInterval                     string              `yaml:"daemon_interval" flag:"daemon-interval"`
+	LogLevel                           string              `yaml:"log_level" flag:"log-level"`
+	LogFormat                          string              `yaml:"log_format" flag:"log-format"`
+	ReportFile                         string              `yaml:"report_file" flag:"report-file"`
+	ReportIndex                        string              `yaml:"report_index" flag:"report-index"`
+	AuditFile                          string              `yaml:"audit_file" flag:"audit-file"`
+	AuditIndex                         string              `yaml:"audit_index" flag:"audit-index"`
 }
 
 type CommandConfig = Config
@@ -94,6 +178,7 @@ func LoadConfig(cmd *cobra.Command, commandName string) error {
 	setDefaults()
 
 	configPath := getValue(cmd, "config", "OSCTL_CONFIG", viper.GetString("osctl_config"))
+	lenientConfig := commandName == "config-validate" || commandName == "config-schema"
 
 	if configPath != "" {
 		viper.SetConfigFile(configPath)
@@ -103,6 +188,10 @@ func LoadConfig(cmd *cobra.Command, commandName string) error {
 			} else {
 				return fmt.Errorf("error reading config file %s: %w", configPath, err)
 			}
+		} else if !lenientConfig {
+			if err := checkConfigFileFields(configPath); err != nil {
+				return fmt.Errorf("error reading config file %s: %w", configPath, err)
+			}
 		}
 	}
 
@@ -110,7 +199,10 @@ func LoadConfig(cmd *cobra.Command, commandName string) error {
 	osctlIndicesPath := getValue(cmd, "osctl-indices-config", "OSCTL_INDICES_CONFIG", viper.GetString("osctl_indices_config"))
 	tenantsPath := getValue(cmd, "kibana-tenants-config", "KIBANA_TENANTS_CONFIG", viper.GetString("kibana_tenants_config"))
 
-	requireIndicesConfig := commandName == "snapshots" || commandName == "indicesdelete" || commandName == "snapshotsdelete" || commandName == "snapshotschecker" || commandName == "snapshotsbackfill"
+	requireIndicesConfig := commandName == "snapshots" || commandName == "indicesdelete" || commandName == "snapshotsdelete" || commandName == "snapshotschecker" || commandName == "snapshotsbackfill" || commandName == "snapshotverify" || commandName == "inventory" || commandName == "snapshotcopy" || commandName == "status" || commandName == "explain"
+	if commandName == "danglingchecker" {
+		requireIndicesConfig = parseBoolWithDefault(getValue(cmd, "dangling-resolve", "DANGLING_RESOLVE", viper.GetString("dangling_resolve")), "dangling_resolve")
+	}
 
 	if requireIndicesConfig {
 
@@ -134,6 +226,11 @@ func LoadConfig(cmd *cobra.Command, commandName string) error {
 		}
 	}
 
+	repositories, err := loadRepositoriesConfig()
+	if err != nil && !lenientConfig {
+		return err
+	}
+
 	configInstance = &Config{
 		Action:                        getValue(cmd, "action", "OSCTL_ACTION", viper.GetString("action")),
 		OpenSearchURL:                 getValue(cmd, "os-url", "OPENSEARCH_URL", viper.GetString("opensearch_url")),
@@ -181,6 +278,7 @@ func LoadConfig(cmd *cobra.Command, commandName string) error {
 		OSCTLConfig:                   getValue(cmd, "config", "OSCTL_CONFIG", viper.GetString("osctl_config")),
 		OSCTLIndicesConfig:            getValue(cmd, "osctl-indices-config", "OSCTL_INDICES_CONFIG", viper.GetString("osctl_indices_config")),
 		OsctlIndicesConfig:            osctlIndicesConfig,
+		Repositories:                  repositories,
 		OSCTLTenantsConfig:            getValue(cmd, "kibana-tenants-config", "KIBANA_TENANTS_CONFIG", tenantsPath),
 		KibanaMultidomainEnabled:      getValue(cmd, "kibana-multidomain-enabled", "KIBANA_MULTIDOMAIN_ENABLED", viper.GetString("kibana_multidomain_enabled")),
 		DataSourceName:                getValue(cmd, "datasource-name", "DATA_SOURCE_NAME", viper.GetString("datasource_name")),
@@ -198,7 +296,98 @@ func LoadConfig(cmd *cobra.Command, commandName string) error {
 		RestoreIndexFilter:                 getValue(cmd, "index-filter", "RESTORE_INDEX_FILTER", viper.GetString("restore_index_filter")),
 		RestoreDaysCount:                   getValue(cmd, "days", "RESTORE_DAYS_COUNT", viper.GetString("restore_days_count")),
 		RestoreDate:                        getValue(cmd, "date", "RESTORE_DATE", viper.GetString("restore_date")),
+		RestoreDateFrom:                    getValue(cmd, "date-from", "RESTORE_DATE_FROM", viper.GetString("restore_date_from")),
+		RestoreDateTo:                      getValue(cmd, "date-to", "RESTORE_DATE_TO", viper.GetString("restore_date_to")),
+		RestorePrefixes:                    getValue(cmd, "prefix", "RESTORE_PREFIX", viper.GetString("restore_prefix")),
+		RestoreTarget:                      getValue(cmd, "restore-target", "RESTORE_TARGET", viper.GetString("restore_target")),
+		RestoreRenameTemplate:              getValue(cmd, "restore-rename-template", "RESTORE_RENAME_TEMPLATE", viper.GetString("restore_rename_template")),
 		ES5Compatibility:                   getValue(cmd, "es5-compatibility", "ES5_COMPATIBILITY", viper.GetString("es5_compatibility")),
+		MappingCheckerThreshold:            getValue(cmd, "mappingchecker-threshold", "MAPPINGCHECKER_THRESHOLD", viper.GetString("mappingchecker_threshold")),
+		MappingCheckerTopFields:            getValue(cmd, "mappingchecker-top-fields", "MAPPINGCHECKER_TOP_FIELDS", viper.GetString("mappingchecker_top_fields")),
+		MappingCheckerRaiseLimit:           getValue(cmd, "mappingchecker-raise-limit", "MAPPINGCHECKER_RAISE_LIMIT", viper.GetString("mappingchecker_raise_limit")),
+		MappingCheckerLimitStep:            getValue(cmd, "mappingchecker-limit-step", "MAPPINGCHECKER_LIMIT_STEP", viper.GetString("mappingchecker_limit_step")),
+		HealthCheckerRerouteFailed:         getValue(cmd, "reroute-failed", "HEALTHCHECKER_REROUTE_FAILED", viper.GetString("healthchecker_reroute_failed")),
+		HealthCheckerMaxExplain:            getValue(cmd, "healthchecker-max-explain", "HEALTHCHECKER_MAX_EXPLAIN", viper.GetString("healthchecker_max_explain")),
+		DanglingResolve:                    getValue(cmd, "dangling-resolve", "DANGLING_RESOLVE", viper.GetString("dangling_resolve")),
+		RepositoriesVerifyOnly:             getValue(cmd, "repositories-verify-only", "REPOSITORIES_VERIFY_ONLY", viper.GetString("repositories_verify_only")),
+		SnapshotVerifySampleSize:           getValue(cmd, "snapshotverify-sample-size", "SNAPSHOTVERIFY_SAMPLE_SIZE", viper.GetString("snapshotverify_sample_size")),
+		SnapshotVerifyTarget:               getValue(cmd, "snapshotverify-target", "SNAPSHOTVERIFY_TARGET", viper.GetString("snapshotverify_target")),
+		SnapshotVerifyTempPrefix:           getValue(cmd, "snapshotverify-temp-prefix", "SNAPSHOTVERIFY_TEMP_PREFIX", viper.GetString("snapshotverify_temp_prefix")),
+		SnapshotVerifyTimeout:              getValue(cmd, "snapshotverify-timeout", "SNAPSHOTVERIFY_TIMEOUT", viper.GetString("snapshotverify_timeout")),
+		InventoryFormat:                    getValue(cmd, "inventory-format", "INVENTORY_FORMAT", viper.GetString("inventory_format")),
+		InventoryOutput:                    getValue(cmd, "inventory-output", "INVENTORY_OUTPUT", viper.GetString("inventory_output")),
+		InventorySizes:                     getValue(cmd, "inventory-sizes", "INVENTORY_SIZES", viper.GetString("inventory_sizes")),
+		ExtractTimeFrom:                    getValue(cmd, "extract-time-from", "EXTRACT_TIME_FROM", viper.GetString("extract_time_from")),
+		ExtractTimeTo:                      getValue(cmd, "extract-time-to", "EXTRACT_TIME_TO", viper.GetString("extract_time_to")),
+		ExtractQuery:                       getValue(cmd, "extract-query", "EXTRACT_QUERY", viper.GetString("extract_query")),
+		ExtractTimeField:                   getValue(cmd, "extract-time-field", "EXTRACT_TIME_FIELD", viper.GetString("extract_time_field")),
+		ExtractTenant:                      getValue(cmd, "extract-tenant", "EXTRACT_TENANT", viper.GetString("extract_tenant")),
+		ExtractTimeout:                     getValue(cmd, "extract-timeout", "EXTRACT_TIMEOUT", viper.GetString("extract_timeout")),
+		SnapshotCopyMode:                   getValue(cmd, "snapshotcopy-mode", "SNAPSHOTCOPY_MODE", viper.GetString("snapshotcopy_mode")),
+		SnapshotCopyScratchURL:             getValue(cmd, "os-scratch-url", "OPENSEARCH_SCRATCH_URL", viper.GetString("opensearch_scratch_url")),
+		SnapshotCopyMaxCopies:              getValue(cmd, "snapshotcopy-max-copies", "SNAPSHOTCOPY_MAX_COPIES", viper.GetString("snapshotcopy_max_copies")),
+		SnapshotCopyTimeout:                getValue(cmd, "snapshotcopy-timeout", "SNAPSHOTCOPY_TIMEOUT", viper.GetString("snapshotcopy_timeout")),
+		StateStore:                         getValue(cmd, "state-store", "STATE_STORE", viper.GetString("state_store")),
+		StateIndex:                         getValue(cmd, "state-index", "STATE_INDEX", viper.GetString("state_index")),
+		StateFile:                          getValue(cmd, "state-file", "STATE_FILE", viper.GetString("state_file")),
+		StatusAll:                          getValue(cmd, "status-all", "STATUS_ALL", viper.GetString("status_all")),
+		StatusFormat:                       getValue(cmd, "status-format", "STATUS_FORMAT", viper.GetString("status_format")),
+		ExplainFormat:                      getValue(cmd, "explain-format", "EXPLAIN_FORMAT", viper.GetString("explain_format")),
+		LintLive:                           getValue(cmd, "lint-live", "LINT_LIVE", viper.GetString("lint_live")),
+		SnapshotTrends:                     getValue(cmd, "snapshot-trends", "SNAPSHOT_TRENDS", viper.GetString("snapshot_trends")),
+		TrendBaselineDays:                  getValue(cmd, "trend-baseline-days", "TREND_BASELINE_DAYS", viper.GetString("trend_baseline_days")),
+		TrendDurationFactor:                getValue(cmd, "trend-duration-factor", "TREND_DURATION_FACTOR", viper.GetString("trend_duration_factor")),
+		TrendRatioFactor:                   getValue(cmd, "trend-ratio-factor", "TREND_RATIO_FACTOR", viper.GetString("trend_ratio_factor")),
+		SnapshotWindow:                     getValue(cmd, "snapshot-window", "SNAPSHOT_WINDOW", viper.GetString("snapshot_window")),
+		TrendForecastDays:                  getValue(cmd, "trend-forecast-days", "TREND_FORECAST_DAYS", viper.GetString("trend_forecast_days")),
+		AdaptiveConcurrency:                getValue(cmd, "adaptive-concurrency", "ADAPTIVE_CONCURRENCY", viper.GetString("adaptive_concurrency")),
+		MinConcurrentSnapshots:             getValue(cmd, "min-concurrent-snapshots", "MIN_CONCURRENT_SNAPSHOTS", viper.GetString("min_concurrent_snapshots")),
+		AdaptiveInterval:                   getValue(cmd, "adaptive-interval", "ADAPTIVE_INTERVAL", viper.GetString("adaptive_interval")),
+		AdaptiveMaxCPU:                     getValue(cmd, "adaptive-max-cpu", "ADAPTIVE_MAX_CPU", viper.GetString("adaptive_max_cpu")),
+		AdaptiveMaxLoad:                    getValue(cmd, "adaptive-max-load", "ADAPTIVE_MAX_LOAD", viper.GetString("adaptive_max_load")),
+		AdaptiveMaxQueue:                   getValue(cmd, "adaptive-max-queue", "ADAPTIVE_MAX_QUEUE", viper.GetString("adaptive_max_queue")),
+		AdaptiveMaxRecoveryThrottle:        getValue(cmd, "adaptive-max-recovery-throttle", "ADAPTIVE_MAX_RECOVERY_THROTTLE", viper.GetString("adaptive_max_recovery_throttle")),
+		AlertNotifiers:                     getValue(cmd, "alert-notifiers", "ALERT_NOTIFIERS", viper.GetString("alert_notifiers")),
+		AlertmanagerURL:                    getValue(cmd, "alertmanager-url", "ALERTMANAGER_URL", viper.GetString("alertmanager_url")),
+		AlertWebhookURL:                    getValue(cmd, "alert-webhook-url", "ALERT_WEBHOOK_URL", viper.GetString("alert_webhook_url")),
+		AlertWebhookTemplate:               getValue(cmd, "alert-webhook-template", "ALERT_WEBHOOK_TEMPLATE", viper.GetString("alert_webhook_template")),
+		SlackWebhookURL:                    getValue(cmd, "slack-webhook-url", "SLACK_WEBHOOK_URL", viper.GetString("slack_webhook_url")),
+		AlertStateStore:                    getValue(cmd, "alert-state-store", "ALERT_STATE_STORE", viper.GetString("alert_state_store")),
+		AlertStateIndex:                    getValue(cmd, "alert-state-index", "ALERT_STATE_INDEX", viper.GetString("alert_state_index")),
+		AlertStateFile:                     getValue(cmd, "alert-state-file", "ALERT_STATE_FILE", viper.GetString("alert_state_file")),
+		AlertDedupWindow:                   getValue(cmd, "alert-dedup-window", "ALERT_DEDUP_WINDOW", viper.GetString("alert_dedup_window")),
+		AlertResolve:                       getValue(cmd, "alert-resolve", "ALERT_RESOLVE", viper.GetString("alert_resolve")),
+		SilenceTrigger:                     getValue(cmd, "silence-trigger", "SILENCE_TRIGGER", viper.GetString("silence_trigger")),
+		SilenceSubject:                     getValue(cmd, "silence-subject", "SILENCE_SUBJECT", viper.GetString("silence_subject")),
+		SilenceDuration:                    getValue(cmd, "silence-duration", "SILENCE_DURATION", viper.GetString("silence_duration")),
+		SilenceReason:                      getValue(cmd, "silence-reason", "SILENCE_REASON", viper.GetString("silence_reason")),
+		SilenceID:                          getValue(cmd, "silence-id", "SILENCE_ID", viper.GetString("silence_id")),
+		AlertLanguage:                      getValue(cmd, "alert-language", "ALERT_LANGUAGE", viper.GetString("alert_language")),
+		AlertTemplatesDir:                  getValue(cmd, "alert-templates-dir", "ALERT_TEMPLATES_DIR", viper.GetString("alert_templates_dir")),
+		AlertClusterName:                   getValue(cmd, "alert-cluster-name", "ALERT_CLUSTER_NAME", viper.GetString("alert_cluster_name")),
+		AlertRunLink:                       getValue(cmd, "alert-run-link", "ALERT_RUN_LINK", viper.GetString("alert_run_link")),
+		MetricsListen:                      getValue(cmd, "metrics-listen", "METRICS_LISTEN", viper.GetString("metrics_listen")),
+		MetricsPushgatewayURL:              getValue(cmd, "metrics-pushgateway-url", "METRICS_PUSHGATEWAY_URL", viper.GetString("metrics_pushgateway_url")),
+		MetricsJob:                         getValue(cmd, "metrics-job", "METRICS_JOB", viper.GetString("metrics_job")),
+		MetricsTextfileDir:                 getValue(cmd, "metrics-textfile-dir", "METRICS_TEXTFILE_DIR", viper.GetString("metrics_textfile_dir")),
+		DaemonInterval:                     getValue(cmd, "daemon-interval", "DAEMON_INTERVAL", viper.GetString("daemon_interval")),
+		LogLevel:                           getValue(cmd, "log-level", "LOG_LEVEL", viper.GetString("log_level")),
+		LogFormat:                          getValue(cmd, "log-format", "LOG_FORMAT", viper.GetString("log_format")),
+		ReportFile:                         getValue(cmd, "report-file", "REPORT_FILE", viper.GetString("report_file")),
+		ReportIndex:                        getValue(cmd, "report-index", "REPORT_INDEX", viper.GetString("report_index")),
+		AuditFile:                          getValue(cmd, "audit-file", "AUDIT_FILE", viper.GetString("audit_file")),
+		AuditIndex:                         getValue(cmd, "audit-index", "AUDIT_INDEX", viper.GetString("audit_index")),
+	}
+
+	if err := validateNotifiers(configInstance); err != nil {
+		return err
+	}
+	if err := validateMetrics(configInstance); err != nil {
+		return err
+	}
+
+	if err := logging.Configure(configInstance.GetLogLevel(), configInstance.GetLogFormat()); err != nil {
+		return err
 	}
 
 	switch commandName {
@@ -206,6 +395,26 @@ func LoadConfig(cmd *cobra.Command, commandName string) error {
 		if configInstance.SnapshotRepo == "" {
 			return fmt.Errorf("snap-repo is required for %s", commandName)
 		}
+		if commandName == "restore" {
+			if err := validateRestoreConfig(configInstance); err != nil {
+				return err
+			}
+		}
+		if commandName == "snapshots" || commandName == "snapshotsbackfill" {
+			if err := validateStateStore(configInstance); err != nil {
+				return err
+			}
+		}
+		if commandName != "snapshotsdelete" {
+			if err := validateConcurrencyConfig(configInstance); err != nil {
+				return err
+			}
+		}
+		if commandName == "snapshots" {
+			if err := validateTrendConfig(configInstance); err != nil {
+				return err
+			}
+		}
 	case "snapshot-manual":
 		repoToUse := configInstance.SnapshotRepo
 		if configInstance.SnapshotManualRepo != "" {
@@ -220,8 +429,248 @@ func LoadConfig(cmd *cobra.Command, commandName string) error {
 				return fmt.Errorf("kibana-user and kibana-pass are required when indexpatterns-refresh-enabled is true")
 			}
 		}
+	case "mappingchecker":
+		if t := parseFloatWithDefault(configInstance.MappingCheckerThreshold, "mappingchecker_threshold"); t <= 0 || t > 100 {
+			return fmt.Errorf("mappingchecker-threshold must be between 0 and 100, got %v", t)
+		}
+	case "snapshotverify":
+		if configInstance.SnapshotRepo == "" {
+			return fmt.Errorf("snap-repo is required for %s", commandName)
+		}
+		if t := configInstance.SnapshotVerifyTarget; t != "main" && t != "recoverer" {
+			return fmt.Errorf("snapshotverify-target must be main or recoverer, got %q", t)
+		}
+		if configInstance.SnapshotVerifyTempPrefix == "" {
+			return fmt.Errorf("snapshotverify-temp-prefix must not be empty")
+		}
+	case "inventory":
+		switch configInstance.InventoryFormat {
+		case "table", "json", "csv", "html":
+		default:
+			return fmt.Errorf("inventory-format must be one of table, json, csv, html, got %q", configInstance.InventoryFormat)
+		}
+		if err := validateTrendConfig(configInstance); err != nil {
+			return err
+		}
+	case "extract":
+		if configInstance.SnapshotRepo == "" {
+			return fmt.Errorf("snap-repo is required for %s", commandName)
+		}
+		if configInstance.OpenSearchRecovererURL == "" {
+			return fmt.Errorf("os-recoverer-url is required for %s", commandName)
+		}
+		if configInstance.GetRestoreDate() == "" || len(configInstance.GetRestorePrefixes()) == 0 {
+			return fmt.Errorf("date and prefix are required for %s", commandName)
+		}
+		if configInstance.GetExtractTimeFrom() == "" || configInstance.GetExtractTimeTo() == "" {
+			return fmt.Errorf("extract-time-from and extract-time-to are required for %s", commandName)
+		}
+		if configInstance.ExtractTimeField == "" {
+			return fmt.Errorf("extract-time-field must not be empty")
+		}
+	case "snapshotcopy":
+		if configInstance.SnapshotRepo == "" {
+			return fmt.Errorf("snap-repo is required for %s", commandName)
+		}
+		switch configInstance.SnapshotCopyMode {
+		case "auto", "clone", "restore":
+		default:
+			return fmt.Errorf("snapshotcopy-mode must be auto, clone or restore, got %q", configInstance.SnapshotCopyMode)
+		}
+	case "status":
+		if err := validateStateStore(configInstance); err != nil {
+			return err
+		}
+		switch configInstance.StatusFormat {
+		case "table", "json":
+		default:
+			return fmt.Errorf("status-format must be table or json, got %q", configInstance.StatusFormat)
+		}
+	case "explain":
+		switch configInstance.ExplainFormat {
+		case "text", "json":
+		default:
+			return fmt.Errorf("explain-format must be text or json, got %q", configInstance.ExplainFormat)
+		}
+	case "alerts-list", "alerts-silence", "alerts-unsilence":
+		if configInstance.AlertStateStore == "none" {
+			return fmt.Errorf("alert-state-store=none keeps no alert state")
+		}
+		if commandName == "alerts-silence" {
+			if err := validateSilence(configInstance); err != nil {
+				return err
+			}
+		}
+		if commandName == "alerts-unsilence" && configInstance.SilenceID == "" {
+			return fmt.Errorf("silence-id is required")
+		}
+	}
+
+	return nil
+}
+
+func validateConcurrencyConfig(c *Config) error {
+	if !c.GetAdaptiveConcurrency() {
+		return nil
+	}
+	if lo, hi := c.GetMinConcurrentSnapshots(), c.GetMaxConcurrentSnapshots(); lo < 1 || lo > hi {
+		return fmt.Errorf("min-concurrent-snapshots must be between 1 and max-concurrent-snapshots (%d), got %d", hi, lo)
+	}
+	if d := c.GetAdaptiveInterval(); d < 10*time.Second {
+		return fmt.Errorf("adaptive-interval must be at least 10s, got %v", d)
+	}
+	return nil
+}
+
+func validateTrendConfig(c *Config) error {
+	if d := c.GetTrendBaselineDays(); d < 3 {
+		return fmt.Errorf("trend-baseline-days must be at least 3, got %d", d)
+	}
+	if f := c.GetTrendDurationFactor(); f <= 1 {
+		return fmt.Errorf("trend-duration-factor must be greater than 1, got %v", f)
+	}
+	if f := c.GetTrendRatioFactor(); f <= 1 {
+		return fmt.Errorf("trend-ratio-factor must be greater than 1, got %v", f)
+	}
+	return nil
+}
+
+func validateStateStore(c *Config) error {
+	switch c.StateStore {
+	case "index":
+		if c.StateIndex == "" {
+			return fmt.Errorf("state-index must not be empty when state-store=index")
+		}
+	case "file":
+		if c.StateFile == "" {
+			return fmt.Errorf("state-file must not be empty when state-store=file")
+		}
+	case "none":
+	default:
+		return fmt.Errorf("state-store must be index, file or none, got %q", c.StateStore)
+	}
+	return nil
+}
+
+func validateNotifiers(c *Config) error {
+	for _, name := range c.GetAlertNotifiers() {
+		switch name {
+		case "madison":
+		case "alertmanager":
+			if c.AlertmanagerURL == "" {
+				return fmt.Errorf("alertmanager-url is required for alert-notifiers=alertmanager")
+			}
+		case "webhook":
+			if c.AlertWebhookURL == "" {
+				return fmt.Errorf("alert-webhook-url is required for alert-notifiers=webhook")
+			}
+			if _, err := alerts.ParseWebhookTemplate(c.AlertWebhookTemplate); err != nil {
+				return fmt.Errorf("invalid alert-webhook-template: %v", err)
+			}
+		case "slack":
+			if c.SlackWebhookURL == "" {
+				return fmt.Errorf("slack-webhook-url is required for alert-notifiers=slack")
+			}
+		default:
+			return fmt.Errorf("alert-notifiers must contain only madison, alertmanager, webhook or slack, got %q", name)
+		}
+	}
+	switch c.AlertStateStore {
+	case "index":
+		if c.AlertStateIndex == "" {
+			return fmt.Errorf("alert-state-index must not be empty when alert-state-store=index")
+		}
+	case "file":
+		if c.AlertStateFile == "" {
+			return fmt.Errorf("alert-state-file must not be empty when alert-state-store=file")
+		}
+	case "none":
+	default:
+		return fmt.Errorf("alert-state-store must be index, file or none, got %q", c.AlertStateStore)
 	}
+	if c.GetAlertDedupWindow() < 0 {
+		return fmt.Errorf("alert-dedup-window must not be negative")
+	}
+	if c.AlertTemplatesDir != "" {
+		if info, err := os.Stat(c.AlertTemplatesDir); err != nil || !info.IsDir() {
+			return fmt.Errorf("alert-templates-dir %s is not a directory", c.AlertTemplatesDir)
+		}
+	}
+	if _, err := alerts.NewTexts(alerts.TextOptions{Language: c.GetAlertLanguage(), OverridesDir: c.AlertTemplatesDir, RunLink: c.AlertRunLink}); err != nil {
+		return fmt.Errorf("invalid alert templates: %v", err)
+	}
+	return nil
+}
+
+func validateMetrics(c *Config) error {
+	if c.DaemonInterval != "" {
+		d, err := time.ParseDuration(c.DaemonInterval)
+		if err != nil || d < 0 {
+			return fmt.Errorf("daemon-interval must be a non-negative duration, got %q", c.DaemonInterval)
+		}
+	}
+	if c.MetricsTextfileDir != "" {
+		if info, err := os.Stat(c.MetricsTextfileDir); err != nil || !info.IsDir() {
+			return fmt.Errorf("metrics-textfile-dir %s is not a directory", c.MetricsTextfileDir)
+		}
+	}
+	if c.MetricsPushgatewayURL != "" {
+		if u, err := url.Parse(c.MetricsPushgatewayURL); err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("metrics-pushgateway-url must be an absolute URL, got %q", c.MetricsPushgatewayURL)
+		}
+	}
+	return nil
+}
+
+func validateSilence(c *Config) error {
+	if c.SilenceTrigger == "" {
+		return fmt.Errorf("silence-trigger is required (event type or *)")
+	}
+	if c.SilenceTrigger != "*" && !alerts.KnownEventType(c.SilenceTrigger) {
+		return fmt.Errorf("unknown silence-trigger %q", c.SilenceTrigger)
+	}
+	if c.GetSilenceDuration() <= 0 {
+		return fmt.Errorf("silence-duration must be positive")
+	}
+	return nil
+}
 
+func validateRestoreConfig(c *Config) error {
+	switch c.RestoreTarget {
+	case "main":
+	case "recoverer":
+		if c.OpenSearchRecovererURL == "" {
+			return fmt.Errorf("os-recoverer-url is required for restore-target=recoverer")
+		}
+	default:
+		return fmt.Errorf("restore-target must be main or recoverer, got %q", c.RestoreTarget)
+	}
+	from, to := c.GetRestoreDateFrom(), c.GetRestoreDateTo()
+	if from == "" && to == "" {
+		return nil
+	}
+	if from == "" || to == "" {
+		return fmt.Errorf("date-from and date-to must be set together")
+	}
+	dateFormat := c.DateFormat
+	if dateFormat == "" {
+		dateFormat = "%Y.%m.%d"
+	}
+	goFormat := ConvertDateFormat(dateFormat)
+	fromTime, err := time.Parse(goFormat, from)
+	if err != nil {
+		return fmt.Errorf("invalid date-from %q for date_format %s: %v", from, dateFormat, err)
+	}
+	toTime, err := time.Parse(goFormat, to)
+	if err != nil {
+		return fmt.Errorf("invalid date-to %q for date_format %s: %v", to, dateFormat, err)
+	}
+	if toTime.Before(fromTime) {
+		return fmt.Errorf("date-to %s is before date-from %s", to, from)
+	}
+	if toTime.Sub(fromTime) > 366*24*time.Hour {
+		return fmt.Errorf("date range %s..%s is longer than 366 days", from, to)
+	}
 	return nil
 }
 
@@ -278,7 +727,79 @@ func setDefaults() {
 	viper.SetDefault("indexpatterns_refresh_enabled", false)
 	viper.SetDefault("max_concurrent_snapshots", 3)
 	viper.SetDefault("restore_days_count", 1)
+	viper.SetDefault("restore_target", "main")
 	viper.SetDefault("es5_compatibility", false)
+	viper.SetDefault("mappingchecker_threshold", 80.0)
+	viper.SetDefault("mappingchecker_top_fields", 5)
+	viper.SetDefault("mappingchecker_raise_limit", false)
+	viper.SetDefault("mappingchecker_limit_step", 1000)
+	viper.SetDefault("healthchecker_reroute_failed", false)
+	viper.SetDefault("healthchecker_max_explain", 50)
+	viper.SetDefault("dangling_resolve", false)
+	viper.SetDefault("repositories_verify_only", false)
+	viper.SetDefault("snapshotverify_sample_size", 1)
+	viper.SetDefault("snapshotverify_target", "main")
+	viper.SetDefault("snapshotverify_temp_prefix", "osctl-verify-")
+	viper.SetDefault("snapshotverify_timeout", "2h")
+	viper.SetDefault("inventory_format", "table")
+	viper.SetDefault("inventory_output", "")
+	viper.SetDefault("inventory_sizes", true)
+	viper.SetDefault("extract_time_field", "@timestamp")
+	viper.SetDefault("extract_tenant", "global")
+	viper.SetDefault("extract_timeout", "2h")
+	viper.SetDefault("snapshotcopy_mode", "auto")
+	viper.SetDefault("snapshotcopy_max_copies", 10)
+	viper.SetDefault("snapshotcopy_timeout", "6h")
+	viper.SetDefault("state_store", "none")
+	viper.SetDefault("state_index", ".osctl-state")
+	viper.SetDefault("state_file", "osctl-state.json")
+	viper.SetDefault("status_all", false)
+	viper.SetDefault("status_format", "table")
+	viper.SetDefault("explain_format", "text")
+	viper.SetDefault("lint_live", false)
+	viper.SetDefault("snapshot_trends", true)
+	viper.SetDefault("trend_baseline_days", 7)
+	viper.SetDefault("trend_duration_factor", 2.0)
+	viper.SetDefault("trend_ratio_factor", 3.0)
+	viper.SetDefault("snapshot_window", "8h")
+	viper.SetDefault("trend_forecast_days", 14)
+	viper.SetDefault("adaptive_concurrency", false)
+	viper.SetDefault("min_concurrent_snapshots", 1)
+	viper.SetDefault("adaptive_interval", "1m")
+	viper.SetDefault("adaptive_max_cpu", 85.0)
+	viper.SetDefault("adaptive_max_load", 1.5)
+	viper.SetDefault("adaptive_max_queue", 10)
+	viper.SetDefault("adaptive_max_recovery_throttle", 1.0)
+	viper.SetDefault("alert_notifiers", "madison")
+	viper.SetDefault("alertmanager_url", "")
+	viper.SetDefault("alert_webhook_url", "")
+	viper.SetDefault("alert_webhook_template", "")
+	viper.SetDefault("slack_webhook_url", "")
+	viper.SetDefault("alert_state_store", "index")
+	viper.SetDefault("alert_state_index", ".osctl-alerts")
+	viper.SetDefault("alert_state_file", "osctl-alerts.json")
+	viper.SetDefault("alert_dedup_window", "24h")
+	viper.SetDefault("alert_resolve", true)
+	viper.SetDefault("silence_trigger", "")
+	viper.SetDefault("silence_subject", "*")
+	viper.SetDefault("silence_duration", "24h")
+	viper.SetDefault("silence_reason", "")
+	viper.SetDefault("silence_id", "")
+	viper.SetDefault("alert_language", "ru")
+	viper.SetDefault("alert_templates_dir", "")
+	viper.SetDefault("alert_cluster_name", "")
+	viper.SetDefault("alert_run_link", "")
+	viper.SetDefault("metrics_listen", "")
+	viper.SetDefault("metrics_pushgateway_url", "")
+	viper.SetDefault("metrics_job", "osctl")
+	viper.SetDefault("metrics_textfile_dir", "")
+	viper.SetDefault("daemon_interval", "0")
+	viper.SetDefault("log_level", "info")
+	viper.SetDefault("log_format", "json")
+	viper.SetDefault("report_file", "")
+	viper.SetDefault("report_index", false)
+	viper.SetDefault("audit_file", "")
+	viper.SetDefault("audit_index", "")
 }
 
 func GetAvailableActions() []string {
@@ -298,6 +819,14 @@ func GetAvailableActions() []string {
 		"indexpatterns",
 		"datasource",
 		"restore",
+		"mappingchecker",
+		"healthchecker",
+		"repositories",
+		"snapshotverify",
+		"inventory",
+		"extract",
+		"snapshotcopy",
+		"status",
 	}
 }
 
@@ -323,6 +852,14 @@ func GetConfig() *Config {
 	return configInstance
 }
 
+func checkConfigFileFields(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return decodeStrict(data, &Config{})
+}
+
 func getValue(cmd *cobra.Command, flagName, envVar, configValue string) string {
 	if flag := cmd.Flags().Lookup(flagName); flag != nil && flag.Changed {
 		return flag.Value.String()
@@ -350,6 +887,13 @@ func parseDurationWithDefault(value, key string) time.Duration {
 	return 0
 }
 
+func ConvertDateFormat(dateFormat string) string {
+	goFormat := strings.ReplaceAll(dateFormat, "%Y", "2006")
+	goFormat = strings.ReplaceAll(goFormat, "%m", "01")
+	goFormat = strings.ReplaceAll(goFormat, "%d", "02")
+	return goFormat
+}
+
 func parseIntWithDefault(value, key string) int {
 	if value != "" {
 		if parsed, err := strconv.Atoi(value); err == nil {
@@ -640,10 +1184,363 @@ func (c *Config) GetRestoreDate() string {
 	return strings.TrimSpace(c.RestoreDate)
 }
 
+func (c *Config) GetRestoreDateFrom() string {
+	return strings.TrimSpace(c.RestoreDateFrom)
+}
+
+func (c *Config) GetRestoreDateTo() string {
+	return strings.TrimSpace(c.RestoreDateTo)
+}
+
+func (c *Config) GetRestorePrefixes() []string {
+	var prefixes []string
+	for _, p := range strings.Split(c.RestorePrefixes, ",") {
+		p = strings.TrimSpace(p)
+		if p != "" {
+			prefixes = append(prefixes, p)
+		}
+	}
+	return prefixes
+}
+
+func (c *Config) GetRestoreTarget() string {
+	return c.RestoreTarget
+}
+
+func (c *Config) GetRestoreRenameTemplate() string {
+	if c.RestoreRenameTemplate == "" && c.RestoreTarget == "recoverer" {
+		pattern := c.ExtractedPattern
+		if pattern == "" {
+			pattern = "extracted_"
+		}
+		return pattern + "<index>_<restore-date>"
+	}
+	return c.RestoreRenameTemplate
+}
+
 func (c *Config) GetES5Compatibility() bool {
 	return parseBoolWithDefault(c.ES5Compatibility, "es5_compatibility")
 }
 
+func (c *Config) GetMappingCheckerThreshold() float64 {
+	return parseFloatWithDefault(c.MappingCheckerThreshold, "mappingchecker_threshold")
+}
+
+func (c *Config) GetMappingCheckerTopFields() int {
+	return parseIntWithDefault(c.MappingCheckerTopFields, "mappingchecker_top_fields")
+}
+
+func (c *Config) GetMappingCheckerRaiseLimit() bool {
+	return parseBoolWithDefault(c.MappingCheckerRaiseLimit, "mappingchecker_raise_limit")
+}
+
+func (c *Config) GetMappingCheckerLimitStep() int {
+	return parseIntWithDefault(c.MappingCheckerLimitStep, "mappingchecker_limit_step")
+}
+
+func (c *Config) GetHealthCheckerRerouteFailed() bool {
+	return parseBoolWithDefault(c.HealthCheckerRerouteFailed, "healthchecker_reroute_failed")
+}
+
+func (c *Config) GetHealthCheckerMaxExplain() int {
+	return parseIntWithDefault(c.HealthCheckerMaxExplain, "healthchecker_max_explain")
+}
+
+func (c *Config) GetDanglingResolve() bool {
+	return parseBoolWithDefault(c.DanglingResolve, "dangling_resolve")
+}
+
+func (c *Config) GetRepositoriesVerifyOnly() bool {
+	return parseBoolWithDefault(c.RepositoriesVerifyOnly, "repositories_verify_only")
+}
+
+func (c *Config) GetSnapshotVerifySampleSize() int {
+	return parseIntWithDefault(c.SnapshotVerifySampleSize, "snapshotverify_sample_size")
+}
+
+func (c *Config) GetSnapshotVerifyTarget() string {
+	return c.SnapshotVerifyTarget
+}
+
+func (c *Config) GetSnapshotVerifyTempPrefix() string {
+	return c.SnapshotVerifyTempPrefix
+}
+
+func (c *Config) GetSnapshotVerifyTimeout() time.Duration {
+	return parseDurationWithDefault(c.SnapshotVerifyTimeout, "snapshotverify_timeout")
+}
+
+func (c *Config) GetInventoryFormat() string {
+	return c.InventoryFormat
+}
+
+func (c *Config) GetInventoryOutput() string {
+	return c.InventoryOutput
+}
+
+func (c *Config) GetInventorySizes() bool {
+	return parseBoolWithDefault(c.InventorySizes, "inventory_sizes")
+}
+
+func (c *Config) GetExtractTimeFrom() string {
+	return strings.TrimSpace(c.ExtractTimeFrom)
+}
+
+func (c *Config) GetExtractTimeTo() string {
+	return strings.TrimSpace(c.ExtractTimeTo)
+}
+
+func (c *Config) GetExtractQuery() string {
+	return c.ExtractQuery
+}
+
+func (c *Config) GetExtractTimeField() string {
+	return c.ExtractTimeField
+}
+
+func (c *Config) GetExtractTenant() string {
+	return c.ExtractTenant
+}
+
+func (c *Config) GetExtractTimeout() time.Duration {
+	return parseDurationWithDefault(c.ExtractTimeout, "extract_timeout")
+}
+
+func (c *Config) GetSnapshotCopyMode() string {
+	return c.SnapshotCopyMode
+}
+
+func (c *Config) GetSnapshotCopyScratchURL() string {
+	return c.SnapshotCopyScratchURL
+}
+
+func (c *Config) GetSnapshotCopyMaxCopies() int {
+	return parseIntWithDefault(c.SnapshotCopyMaxCopies, "snapshotcopy_max_copies")
+}
+
+func (c *Config) GetSnapshotCopyTimeout() time.Duration {
+	return parseDurationWithDefault(c.SnapshotCopyTimeout, "snapshotcopy_timeout")
+}
+
+func (c *Config) GetStateStore() string {
+	return c.StateStore
+}
+
+func (c *Config) GetStateIndex() string {
+	return c.StateIndex
+}
+
+func (c *Config) GetStateFile() string {
+	return c.StateFile
+}
+
+func (c *Config) GetStatusAll() bool {
+	return parseBoolWithDefault(c.StatusAll, "status_all")
+}
+
+func (c *Config) GetStatusFormat() string {
+	return c.StatusFormat
+}
+
+func (c *Config) GetExplainFormat() string {
+	return c.ExplainFormat
+}
+
+func (c *Config) GetLintLive() bool {
+	return parseBoolWithDefault(c.LintLive, "lint_live")
+}
+
+func (c *Config) GetSnapshotTrends() bool {
+	return parseBoolWithDefault(c.SnapshotTrends, "snapshot_trends")
+}
+
+func (c *Config) GetTrendBaselineDays() int {
+	return parseIntWithDefault(c.TrendBaselineDays, "trend_baseline_days")
+}
+
+func (c *Config) GetTrendDurationFactor() float64 {
+	return parseFloatWithDefault(c.TrendDurationFactor, "trend_duration_factor")
+}
+
+func (c *Config) GetTrendRatioFactor() float64 {
+	return parseFloatWithDefault(c.TrendRatioFactor, "trend_ratio_factor")
+}
+
+func (c *Config) GetSnapshotWindow() time.Duration {
+	return parseDurationWithDefault(c.SnapshotWindow, "snapshot_window")
+}
+
+func (c *Config) GetTrendForecastDays() int {
+	return parseIntWithDefault(c.TrendForecastDays, "trend_forecast_days")
+}
+
+func (c *Config) GetAdaptiveConcurrency() bool {
+	return parseBoolWithDefault(c.AdaptiveConcurrency, "adaptive_concurrency")
+}
+
+func (c *Config) GetMinConcurrentSnapshots() int {
+	return parseIntWithDefault(c.MinConcurrentSnapshots, "min_concurrent_snapshots")
+}
+
+func (c *Config) GetAdaptiveInterval() time.Duration {
+	return parseDurationWithDefault(c.AdaptiveInterval, "adaptive_interval")
+}
+
+func (c *Config) GetAdaptiveMaxCPU() float64 {
+	return parseFloatWithDefault(c.AdaptiveMaxCPU, "adaptive_max_cpu")
+}
+
+func (c *Config) GetAdaptiveMaxLoad() float64 {
+	return parseFloatWithDefault(c.AdaptiveMaxLoad, "adaptive_max_load")
+}
+
+func (c *Config) GetAdaptiveMaxQueue() int {
+	return parseIntWithDefault(c.AdaptiveMaxQueue, "adaptive_max_queue")
+}
+
+func (c *Config) GetAdaptiveMaxRecoveryThrottle() float64 {
+	return parseFloatWithDefault(c.AdaptiveMaxRecoveryThrottle, "adaptive_max_recovery_throttle")
+}
+
+func (c *Config) GetAlertNotifiers() []string {
+	var notifiers []string
+	for _, n := range strings.Split(c.AlertNotifiers, ",") {
+		n = strings.TrimSpace(n)
+		if n != "" {
+			notifiers = append(notifiers, n)
+		}
+	}
+	return notifiers
+}
+
+func (c *Config) GetAlertmanagerURL() string {
+	return c.AlertmanagerURL
+}
+
+func (c *Config) GetAlertWebhookURL() string {
+	return c.AlertWebhookURL
+}
+
+func (c *Config) GetAlertWebhookTemplate() string {
+	return c.AlertWebhookTemplate
+}
+
+func (c *Config) GetSlackWebhookURL() string {
+	return c.SlackWebhookURL
+}
+
+func (c *Config) GetAlertStateStore() string {
+	return c.AlertStateStore
+}
+
+func (c *Config) GetAlertStateIndex() string {
+	return c.AlertStateIndex
+}
+
+func (c *Config) GetAlertStateFile() string {
+	return c.AlertStateFile
+}
+
+func (c *Config) GetAlertDedupWindow() time.Duration {
+	return parseDurationWithDefault(c.AlertDedupWindow, "alert_dedup_window")
+}
+
+func (c *Config) GetAlertResolve() bool {
+	return parseBoolWithDefault(c.AlertResolve, "alert_resolve")
+}
+
+func (c *Config) GetSilenceTrigger() string {
+	return c.SilenceTrigger
+}
+
+func (c *Config) GetSilenceSubject() string {
+	return c.SilenceSubject
+}
+
+func (c *Config) GetSilenceDuration() time.Duration {
+	return parseDurationWithDefault(c.SilenceDuration, "silence_duration")
+}
+
+func (c *Config) GetSilenceReason() string {
+	return c.SilenceReason
+}
+
+func (c *Config) GetSilenceID() string {
+	return c.SilenceID
+}
+
+func (c *Config) GetAlertLanguage() string {
+	if c.AlertLanguage == "" {
+		return "ru"
+	}
+	return c.AlertLanguage
+}
+
+func (c *Config) GetAlertTemplatesDir() string {
+	return c.AlertTemplatesDir
+}
+
+func (c *Config) GetAlertClusterName() string {
+	return c.AlertClusterName
+}
+
+func (c *Config) GetAlertRunLink() string {
+	return c.AlertRunLink
+}
+
+func (c *Config) GetMetricsListen() string {
+	return c.MetricsListen
+}
+
+func (c *Config) GetMetricsPushgatewayURL() string {
+	return c.MetricsPushgatewayURL
+}
+
+func (c *Config) GetMetricsJob() string {
+	if c.MetricsJob == "" {
+		return "osctl"
+	}
+	return c.MetricsJob
+}
+
+func (c *Config) GetMetricsTextfileDir() string {
+	return c.MetricsTextfileDir
+}
+
+func (c *Config) GetDaemonInterval() time.Duration {
+	return parseDurationWithDefault(c.DaemonInterval, "daemon_interval")
+}
+
+func (c *Config) GetLogLevel() string {
+	if c.LogLevel == "" {
+		return "info"
+	}
+	return c.LogLevel
+}
+
+func (c *Config) GetLogFormat() string {
+	if c.LogFormat == "" {
+		return "json"
+	}
+	return c.LogFormat
+}
+
+func (c *Config) GetReportFile() string {
+	return c.ReportFile
+}
+
+func (c *Config) GetReportIndex() bool {
+	return parseBoolWithDefault(c.ReportIndex, "report_index")
+}
+
+func (c *Config) GetAuditFile() string {
+	return c.AuditFile
+}
+
+func (c *Config) GetAuditIndex() string {
+	return c.AuditIndex
+}
+
 type FlagDefinition struct {
 	Name        string
 	Type        string
@@ -660,6 +1557,22 @@ var CommandFlags = map[string][]FlagDefinition{
 	},
 	"snapshots": {
 		{"dry-run", "bool", false, "Show what would be created without actually creating", []string{}},
+		{"state-store", "string", "none", "Where run progress is kept for resuming after a restart: index, file or none", []string{}},
+		{"state-index", "string", ".osctl-state", "Index for run progress when state-store=index", []string{}},
+		{"state-file", "string", "osctl-state.json", "File for run progress when state-store=file", []string{}},
+		{"snapshot-trends", "bool", true, "After creation compare today's snapshots with the baseline and forecast the nightly window", []string{}},
+		{"trend-baseline-days", "int", 7, "Number of previous snapshots of a prefix used as the baseline", []string{"min:3", "max:90"}},
+		{"trend-duration-factor", "float64", 2.0, "Flag a snapshot that takes longer than this multiple of the baseline median duration", []string{}},
+		{"trend-ratio-factor", "float64", 3.0, "Flag a snapshot whose incremental/total ratio exceeds this multiple of the baseline median", []string{}},
+		{"snapshot-window", "duration", 8 * time.Hour, "Nightly snapshot window used for the forecast", []string{}},
+		{"trend-forecast-days", "int", 14, "Alert when the forecast says the window is exceeded within this many days", []string{"min:0", "max:365"}},
+		{"adaptive-concurrency", "bool", false, "Adjust concurrency between min-concurrent-snapshots and max-concurrent-snapshots from node load signals", []string{}},
+		{"min-concurrent-snapshots", "int", 1, "Lower bound of adaptive concurrency; the limit starts at max-concurrent-snapshots", []string{"min:1", "max:10"}},
+		{"adaptive-interval", "duration", time.Minute, "How often node stats are polled to adjust concurrency", []string{}},
+		{"adaptive-max-cpu", "float64", 85.0, "Lower concurrency when CPU of any node exceeds this percentage", []string{"min:1", "max:100"}},
+		{"adaptive-max-load", "float64", 1.5, "Lower concurrency when load average 1m per processor of any node exceeds this value", []string{}},
+		{"adaptive-max-queue", "int", 10, "Lower concurrency when the snapshot thread pool queue across nodes exceeds this size", []string{"min:0", "max:1000"}},
+		{"adaptive-max-recovery-throttle", "float64", 1.0, "Lower concurrency when indices recovery throttle time (restores, replica recovery) grows faster than this many ms per ms", []string{}},
 	},
 	"snapshot-manual": {
 		{"snapshot-manual-kind", "string", "", "Pattern type: prefix or regex", []string{}},
@@ -692,6 +1605,16 @@ var CommandFlags = map[string][]FlagDefinition{
 		// Uses --osctl-indices-config for configuration
 		{"indices-list", "string", "", "Comma-separated list of indices to backfill snapshots for", []string{}},
 		{"dry-run", "bool", false, "Show what would be created without actually creating", []string{}},
+		{"state-store", "string", "none", "Where run progress is kept for resuming after a restart: index, file or none", []string{}},
+		{"state-index", "string", ".osctl-state", "Index for run progress when state-store=index", []string{}},
+		{"state-file", "string", "osctl-state.json", "File for run progress when state-store=file", []string{}},
+		{"adaptive-concurrency", "bool", false, "Adjust concurrency between min-concurrent-snapshots and max-concurrent-snapshots from node load signals", []string{}},
+		{"min-concurrent-snapshots", "int", 1, "Lower bound of adaptive concurrency; the limit starts at max-concurrent-snapshots", []string{"min:1", "max:10"}},
+		{"adaptive-interval", "duration", time.Minute, "How often node stats are polled to adjust concurrency", []string{}},
+		{"adaptive-max-cpu", "float64", 85.0, "Lower concurrency when CPU of any node exceeds this percentage", []string{"min:1", "max:100"}},
+		{"adaptive-max-load", "float64", 1.5, "Lower concurrency when load average 1m per processor of any node exceeds this value", []string{}},
+		{"adaptive-max-queue", "int", 10, "Lower concurrency when the snapshot thread pool queue across nodes exceeds this size", []string{"min:0", "max:1000"}},
+		{"adaptive-max-recovery-throttle", "float64", 1.0, "Lower concurrency when indices recovery throttle time (restores, replica recovery) grows faster than this many ms per ms", []string{}},
 	},
 	"coldstorage": {
 		{"hot-count", "int", 3, "Number of days to keep indices hot", []string{"min:1", "max:30"}},
@@ -741,14 +1664,130 @@ var CommandFlags = map[string][]FlagDefinition{
 		// Uses --osctl-indices-config for configuration
 	},
 	"danglingchecker": {
-		{"dry-run", "bool", false, "Show what alerts would be sent without sending", []string{}},
+		{"dangling-resolve", "bool", false, "Import or delete dangling indices according to osctl-indices-config policies", []string{}},
+		{"snap-repo", "string", "", "Snapshot repository used to check whether a dangling index is already covered by a snapshot", []string{}},
+		{"dry-run", "bool", false, "Show what alerts would be sent and dangling indices imported/deleted without executing", []string{}},
 	},
 	"restore": {
 		{"snap-repo", "string", "", "Snapshot repository name to restore from", []string{"required"}},
 		{"index-filter", "string", "", "Comma-separated glob patterns; only matching indices from the snapshots are restored (empty = all)", []string{}},
 		{"days", "int", 1, "How many days of snapshots to restore, starting from today and going back (1 = today only)", []string{"min:1", "max:60"}},
 		{"date", "string", "", "Restore only snapshots of this exact date (date_format, e.g. 2026.07.09); overrides --days", []string{}},
+		{"date-from", "string", "", "First date of the range to restore (date_format); used together with --date-to, overrides --date and --days", []string{}},
+		{"date-to", "string", "", "Last date of the range to restore (date_format, inclusive)", []string{}},
+		{"prefix", "string", "", "Comma-separated index prefixes to restore (added to --index-filter as <prefix>-*)", []string{}},
+		{"restore-target", "string", "main", "Cluster to restore into: main (opensearch_url) or recoverer (opensearch_recoverer_url)", []string{}},
+		{"restore-rename-template", "string", "", "Name of the restored index; placeholders <index> and <restore-date> (recoverer_date_format). Default for recoverer: <extracted_pattern><index>_<restore-date>", []string{}},
+		{"os-recoverer-url", "string", "", "OpenSearch recoverer cluster URL (for restore-target=recoverer)", []string{}},
+		{"recoverer-date-format", "string", "%d-%m-%Y", "Date format of <restore-date> in renamed indices", []string{}},
+		{"extracted-pattern", "string", "extracted_", "Prefix of restored indices on the recoverer", []string{}},
 		{"dry-run", "bool", false, "Show what would be restored without actually restoring", []string{}},
+		{"max-concurrent-snapshots", "int", 3, "Maximum number of restores to run simultaneously", []string{"min:1", "max:10"}},
+		{"adaptive-concurrency", "bool", false, "Adjust concurrency between min-concurrent-snapshots and max-concurrent-snapshots from node load signals", []string{}},
+		{"min-concurrent-snapshots", "int", 1, "Lower bound of adaptive concurrency; the limit starts at max-concurrent-snapshots", []string{"min:1", "max:10"}},
+		{"adaptive-interval", "duration", time.Minute, "How often node stats are polled to adjust concurrency", []string{}},
+		{"adaptive-max-cpu", "float64", 85.0, "Lower concurrency when CPU of any node exceeds this percentage", []string{"min:1", "max:100"}},
+		{"adaptive-max-load", "float64", 1.5, "Lower concurrency when load average 1m per processor of any node exceeds this value", []string{}},
+		{"adaptive-max-queue", "int", 10, "Lower concurrency when the snapshot thread pool queue across nodes exceeds this size", []string{"min:0", "max:1000"}},
+		{"adaptive-max-recovery-throttle", "float64", 1.0, "Lower concurrency when indices recovery throttle time (restores, replica recovery) grows faster than this many ms per ms", []string{}},
+	},
+	"mappingchecker": {
+		{"mappingchecker-threshold", "float64", 80.0, "Alert when an index uses more than this percentage of mapping.total_fields.limit", []string{"min:1", "max:100"}},
+		{"mappingchecker-top-fields", "int", 5, "Number of fastest-growing top-level fields to report per prefix", []string{"min:1", "max:50"}},
+		{"mappingchecker-raise-limit", "bool", false, "Raise mapping.total_fields.limit in the managed index template for prefixes over the threshold", []string{}},
+		{"mappingchecker-limit-step", "int", 1000, "How much to add to mapping.total_fields.limit when raising it", []string{"min:100", "max:10000"}},
+		{"dry-run", "bool", false, "Show what alerts and template changes would be made without applying", []string{}},
+	},
+	"healthchecker": {
+		{"reroute-failed", "bool", false, "Retry failed shard allocations via _cluster/reroute?retry_failed=true", []string{}},
+		{"healthchecker-max-explain", "int", 50, "Maximum number of allocation explain calls per run (the rest are classified by unassigned.reason)", []string{"min:1", "max:1000"}},
+		{"dry-run", "bool", false, "Show what alerts would be sent and reroutes run without executing", []string{}},
+	},
+	"repositories": {
+		{"repositories-verify-only", "bool", false, "Only verify repositories, do not register missing or update drifted ones", []string{}},
+		{"dry-run", "bool", false, "Show which repositories would be registered or updated without executing", []string{}},
+	},
+	"snapshotverify": {
+		{"snap-repo", "string", "", "Default snapshot repository (rules with repository use their own)", []string{"required"}},
+		{"snapshotverify-sample-size", "int", 1, "Number of snapshots per prefix to test-restore per run", []string{"min:1", "max:100"}},
+		{"snapshotverify-target", "string", "main", "Cluster to restore into: main or recoverer", []string{}},
+		{"snapshotverify-temp-prefix", "string", "osctl-verify-", "Prefix of temporary indices created by test restores", []string{}},
+		{"snapshotverify-timeout", "duration", 2 * time.Hour, "Maximum time to wait for one test restore", []string{}},
+		{"os-recoverer-url", "string", "", "OpenSearch recoverer cluster URL (for snapshotverify-target=recoverer)", []string{}},
+		{"dry-run", "bool", false, "Show which snapshots would be test-restored without restoring", []string{}},
+	},
+	"inventory": {
+		{"snap-repo", "string", "", "Default snapshot repository (repositories from osctl-indices-config are added)", []string{}},
+		{"inventory-format", "string", "table", "Report format: table, json, csv or html", []string{}},
+		{"inventory-output", "string", "", "Write the report to this file instead of stdout", []string{}},
+		{"inventory-sizes", "bool", true, "Read total and incremental sizes via _snapshot/<repo>/<snapshot>/_status (one request per snapshot)", []string{}},
+		{"trend-baseline-days", "int", 7, "Number of previous snapshots of a prefix used as the baseline", []string{"min:3", "max:90"}},
+		{"trend-duration-factor", "float64", 2.0, "Flag a snapshot that takes longer than this multiple of the baseline median duration", []string{}},
+		{"trend-ratio-factor", "float64", 3.0, "Flag a snapshot whose incremental/total ratio exceeds this multiple of the baseline median", []string{}},
+		{"snapshot-window", "duration", 8 * time.Hour, "Nightly snapshot window used for the forecast", []string{}},
+	},
+	"extract": {
+		{"snap-repo", "string", "", "Snapshot repository name to restore from (registered on the recoverer)", []string{"required"}},
+		{"date", "string", "", "Date of the snapshot to extract from (date_format, e.g. 2026.03.02)", []string{"required"}},
+		{"prefix", "string", "", "Comma-separated index prefixes to extract (<prefix>-* indices of the snapshot)", []string{"required"}},
+		{"extract-time-from", "string", "", "Start of the time range: HH:MM (UTC, on --date) or RFC3339", []string{"required"}},
+		{"extract-time-to", "string", "", "End of the time range (exclusive): HH:MM (UTC, on --date) or RFC3339", []string{"required"}},
+		{"extract-query", "string", "", "Optional query_string query; only matching documents are extracted", []string{}},
+		{"extract-time-field", "string", "@timestamp", "Time field used for the range filter and the index pattern", []string{}},
+		{"extract-tenant", "string", "global", "Kibana tenant for the created index pattern", []string{}},
+		{"extract-timeout", "duration", 2 * time.Hour, "Maximum time to wait for the restore and for the reindex of one index", []string{}},
+		{"os-recoverer-url", "string", "", "OpenSearch recoverer cluster URL", []string{"required"}},
+		{"recoverer-date-format", "string", "%d-%m-%Y", "Date format of <restore-date> in extracted index names", []string{}},
+		{"extracted-pattern", "string", "extracted_", "Prefix of extracted indices on the recoverer", []string{}},
+		{"kibana-user", "string", "", "Kibana API user", []string{}},
+		{"kibana-pass", "string", "", "Kibana API password", []string{}},
+		{"datasource-name", "string", "recoverer", "Title of the recoverer data source referenced by the index pattern", []string{}},
+		{"dry-run", "bool", false, "Show what would be restored and extracted without executing", []string{}},
+	},
+	"snapshotcopy": {
+		{"snap-repo", "string", "", "Default snapshot repository (rules with repository use their own)", []string{"required"}},
+		{"snapshotcopy-mode", "string", "auto", "How to copy: auto (_clone when repositories share a store, otherwise restore), clone or restore", []string{}},
+		{"os-scratch-url", "string", "", "Scratch OpenSearch cluster URL for restore-and-resnapshot copies", []string{}},
+		{"snapshotcopy-max-copies", "int", 10, "Maximum number of snapshots to copy per run", []string{"min:1", "max:1000"}},
+		{"snapshotcopy-timeout", "duration", 6 * time.Hour, "Maximum time to wait for one restore or snapshot during a copy", []string{}},
+		{"dry-run", "bool", false, "Show which snapshots would be copied without copying", []string{}},
+	},
+	"status": {
+		{"snap-repo", "string", "", "Default snapshot repository for snapshot coverage", []string{}},
+		{"state-store", "string", "none", "Where run state is kept: index, file or none", []string{}},
+		{"state-index", "string", ".osctl-state", "Index for run state when state-store=index", []string{}},
+		{"state-file", "string", "osctl-state.json", "File for run state when state-store=file", []string{}},
+		{"status-all", "bool", false, "Also show finished runs", []string{}},
+		{"status-format", "string", "table", "Output format: table or json", []string{}},
+	},
+	"explain": {
+		{"snap-repo", "string", "", "Default snapshot repository", []string{}},
+		{"dereplicator-days-count", "int", 2, "Number of days to keep with replicas", []string{"min:1", "max:365"}},
+		{"dereplicator-use-snapshot", "bool", false, "Check for snapshots before reducing replicas", []string{}},
+		{"hot-count", "int", 3, "Number of days to keep indices hot", []string{"min:1", "max:30"}},
+		{"cold-attribute", "string", "", "Node attribute for cold storage", []string{}},
+		{"indicesdelete-check-snapshots", "bool", true, "Check for valid snapshots before deleting indices that should have snapshots", []string{}},
+		{"retention-days-count", "int", 2, "Number of days protected from retention", []string{"min:2", "max:365"}},
+		{"retention-threshold", "int", 75, "Disk usage threshold percentage", []string{"min:0", "max:100"}},
+		{"explain-format", "string", "text", "Output format: text or json", []string{}},
+	},
+	"config-validate": {
+		{"kibana-tenants-config", "string", "", "Path to kibana tenants config", []string{}},
+		{"indexpatterns-kibana-tenants-config", "string", "", "Path to indexpatterns tenants config", []string{}},
+	},
+	"config-lint": {
+		{"snap-repo", "string", "", "Default snapshot repository", []string{}},
+		{"lint-live", "bool", false, "Also check repositories and index names in the cluster", []string{}},
+	},
+	"alerts-silence": {
+		{"silence-trigger", "string", "", "Alert trigger to silence (event type, e.g. SnapshotsMissing) or * for all", []string{"required"}},
+		{"silence-subject", "string", "*", "Subject to silence: index, snapshot or prefix name; glob patterns allowed", []string{}},
+		{"silence-duration", "duration", 24 * time.Hour, "How long the silence lasts", []string{}},
+		{"silence-reason", "string", "", "Why the alert is silenced", []string{}},
+		{"dry-run", "bool", false, "Show the silence without saving it", []string{}},
+	},
+	"alerts-unsilence": {
+		{"silence-id", "string", "", "ID of the silence to expire", []string{"required"}},
 	},
 }
 
@@ -42,6 +42,9 @@ type IndexConfig struct {
 	Snapshot        bool   `yaml:"snapshot"`
 	SnapshotCountS3 int    `yaml:"snapshot_count_s3,omitempty"`
 	ManualSnapshot  bool   `yaml:"manual_snapshot,omitempty"`
+
+	SecondaryRepository    string `yaml:"secondary_repository,omitempty"`
+	SecondarySnapshotCount int    `yaml:"secondary_snapshot_count,omitempty"`
 }
 
 func LoadOsctlIndicesConfig(path string) (*OsctlIndicesConfig, error) {
@@ -53,7 +56,7 @@ func LoadOsctlIndicesConfig(path string) (*OsctlIndicesConfig, error) {
 	content := strings.TrimPrefix(string(data), "---")
 
 	var config OsctlIndicesConfig
-	err = yaml.Unmarshal([]byte(content), &config)
+	err = decodeStrict([]byte(content), &config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal osctl indices config: %w", err)
 	}
@@ -88,6 +91,12 @@ func LoadOsctlIndicesConfig(path string) (*OsctlIndicesConfig, error) {
 			if config.Indices[i].SnapshotCountS3 == 0 && config.Indices[i].Snapshot {
 				config.Indices[i].SnapshotCountS3 = config.S3Snapshots.UnitCount.All
 			}
+			if err := validateSecondaryRepository(config.Indices, i); err != nil {
+				return nil, err
+			}
+			if config.Indices[i].SecondaryRepository != "" && config.Indices[i].SecondarySnapshotCount == 0 {
+				config.Indices[i].SecondarySnapshotCount = config.Indices[i].SnapshotCountS3
+			}
 		}
 	}
 
@@ -98,6 +107,25 @@ func LoadOsctlIndicesConfig(path string) (*OsctlIndicesConfig, error) {
 	return &config, nil
 }
 
+func validateSecondaryRepository(indices []IndexConfig, i int) error {
+	ic := indices[i]
+	if ic.SecondarySnapshotCount < 0 {
+		return fmt.Errorf("index config #%d: secondary_snapshot_count must be >= 0 (or not set)", i+1)
+	}
+	if ic.SecondaryRepository == "" {
+		return nil
+	}
+	if !ic.Snapshot {
+		return fmt.Errorf("index config #%d: secondary_repository requires snapshot: true", i+1)
+	}
+	for _, other := range indices {
+		if other.Repository == ic.SecondaryRepository {
+			return fmt.Errorf("index config #%d: secondary_repository %q is used as a primary repository", i+1, ic.SecondaryRepository)
+		}
+	}
+	return nil
+}
+
 func ValidateOsctlIndicesConfig(config *OsctlIndicesConfig, dateFormat string) error {
 	for i, indexConfig := range config.Indices {
 		if indexConfig.Kind == "regex" {
@@ -139,15 +167,18 @@ func (c *Config) GetOsctlIndices() ([]IndexConfig, error) {
 	}
 
 	indices := c.OsctlIndicesConfig.Indices
+	SortIndexConfigs(indices)
+
+	return indices, nil
+}
 
+func SortIndexConfigs(indices []IndexConfig) {
 	sort.Slice(indices, func(i, j int) bool {
 		if indices[i].Kind != indices[j].Kind {
 			return indices[i].Kind == "prefix" && indices[j].Kind == "regex"
 		}
 		return indices[i].Value < indices[j].Value
 	})
-
-	return indices, nil
 }
 
 func (c *Config) GetOsctlIndicesUnknownConfig() UnknownConfig {
@@ -175,6 +206,30 @@ func (c *Config) IsOsctlIndicesMode() bool {
 	return c.OsctlIndicesConfig != nil
 }
 
+func FindIndexConfigLine(path string, ic IndexConfig) int {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(strings.TrimPrefix(string(data), "---")), &root); err != nil || len(root.Content) == 0 {
+		return 0
+	}
+	doc := root.Content[0]
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		if doc.Content[i].Value != "indices" {
+			continue
+		}
+		for _, item := range doc.Content[i+1].Content {
+			var candidate IndexConfig
+			if item.Decode(&candidate) == nil && candidate.Kind == ic.Kind && candidate.Value == ic.Value && candidate.Name == ic.Name {
+				return item.Line
+			}
+		}
+	}
+	return 0
+}
+
 func (c *Config) IsFullPrefixSnapshots() bool {
 	return c.OsctlIndicesConfig != nil && c.OsctlIndicesConfig.FullPrefixSnapshots
 }
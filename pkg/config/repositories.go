@@ -0,0 +1,87 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/viper"
+)
+
+type RepositoryConfig struct {
+	Name                   string            `mapstructure:"name" yaml:"name"`
+	Type                   string            `mapstructure:"type" yaml:"type"`
+	Bucket                 string            `mapstructure:"bucket" yaml:"bucket"`
+	BasePath               string            `mapstructure:"base_path" yaml:"base_path"`
+	Client                 string            `mapstructure:"client" yaml:"client"`
+	Compress               *bool             `mapstructure:"compress" yaml:"compress"`
+	MaxSnapshotBytesPerSec string            `mapstructure:"max_snapshot_bytes_per_sec" yaml:"max_snapshot_bytes_per_sec"`
+	MaxRestoreBytesPerSec  string            `mapstructure:"max_restore_bytes_per_sec" yaml:"max_restore_bytes_per_sec"`
+	Settings               map[string]string `mapstructure:"settings" yaml:"settings"`
+}
+
+func loadRepositoriesConfig() ([]RepositoryConfig, error) {
+	var repos []RepositoryConfig
+	if !viper.IsSet("repositories") {
+		return repos, nil
+	}
+	if err := viper.UnmarshalKey("repositories", &repos); err != nil {
+		return nil, fmt.Errorf("error parsing repositories: %w", err)
+	}
+	seen := make(map[string]bool)
+	for i := range repos {
+		r := &repos[i]
+		if r.Name == "" {
+			return nil, fmt.Errorf("repositories[%d]: name is required", i)
+		}
+		if seen[r.Name] {
+			return nil, fmt.Errorf("repositories[%d]: duplicate repository %s", i, r.Name)
+		}
+		seen[r.Name] = true
+		if r.Type == "" {
+			r.Type = "s3"
+		}
+		if r.Type == "s3" && r.Bucket == "" {
+			return nil, fmt.Errorf("repositories[%d] %s: bucket is required for s3 repositories", i, r.Name)
+		}
+	}
+	return repos, nil
+}
+
+func (r RepositoryConfig) DesiredSettings() map[string]string {
+	settings := map[string]string{}
+	if r.Compress != nil {
+		settings["compress"] = strconv.FormatBool(*r.Compress)
+	}
+	if r.Bucket != "" {
+		settings["bucket"] = r.Bucket
+	}
+	if r.BasePath != "" {
+		settings["base_path"] = r.BasePath
+	}
+	if r.Client != "" {
+		settings["client"] = r.Client
+	}
+	if r.MaxSnapshotBytesPerSec != "" {
+		settings["max_snapshot_bytes_per_sec"] = r.MaxSnapshotBytesPerSec
+	}
+	if r.MaxRestoreBytesPerSec != "" {
+		settings["max_restore_bytes_per_sec"] = r.MaxRestoreBytesPerSec
+	}
+	for k, v := range r.Settings {
+		settings[k] = v
+	}
+	return settings
+}
+
+func (c *Config) GetRepositories() []RepositoryConfig {
+	return c.Repositories
+}
+
+func (c *Config) GetRepositoryConfig(name string) *RepositoryConfig {
+	for i := range c.Repositories {
+		if c.Repositories[i].Name == name {
+			return &c.Repositories[i]
+		}
+	}
+	return nil
+}
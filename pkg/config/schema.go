@@ -0,0 +1,370 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const schemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+var SchemaKinds = []string{"config", "indices", "tenants"}
+
+type FlagInfo struct {
+	Type        string
+	Description string
+}
+
+var schemaPatterns = map[string]string{
+	"int":      `^-?[0-9]*$`,
+	"float64":  `^-?[0-9]*(\.[0-9]+)?$`,
+	"bool":     `^(1|0|t|f|T|F|true|false|TRUE|FALSE|True|False)?$`,
+	"duration": `^(0|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)?$`,
+}
+
+var schemaRequired = map[string][]string{
+	"IndexConfig":      {"kind", "value"},
+	"TenantSpec":       {"name"},
+	"RepositoryConfig": {"name"},
+}
+
+var schemaHints = map[string]map[string]any{
+	"IndexConfig.kind":                     {"enum": []any{"prefix", "regex"}},
+	"IndexConfig.value":                    {"description": "Index name prefix or regex with the date pattern of date_format"},
+	"IndexConfig.name":                     {"description": "Snapshot name for regex rules"},
+	"IndexConfig.days_count":               {"description": "Days to keep indices"},
+	"IndexConfig.snapshot_count_s3":        {"description": "Days to keep snapshots, defaults to s3_snapshots.unit_count.all"},
+	"IndexConfig.secondary_snapshot_count": {"description": "Days to keep copies in secondary_repository, defaults to snapshot_count_s3"},
+	"UnknownConfig.days_count":             {"description": "Days to keep dated indices that match no rule, 0 keeps them"},
+	"RepositoryConfig.type":                {"description": "Repository type, s3 by default"},
+}
+
+func Schema(kind string, lookup func(flag string) (FlagInfo, bool)) (map[string]any, error) {
+	var schema map[string]any
+	switch kind {
+	case "config":
+		schema = structSchema(reflect.TypeOf(Config{}), func(f reflect.StructField) map[string]any {
+			if flag := f.Tag.Get("flag"); flag != "" {
+				return flagSchema(flag, f.Tag.Get("type"), lookup)
+			}
+			return nil
+		})
+		schema["title"] = "osctl config.yaml"
+	case "indices":
+		schema = structSchema(reflect.TypeOf(OsctlIndicesConfig{}), nil)
+		schema["title"] = "osctl osctlindicesconfig.yaml"
+	case "tenants":
+		schema = structSchema(reflect.TypeOf(TenantsFile{}), nil)
+		schema["title"] = "osctl osctltenants.yaml"
+	default:
+		return nil, fmt.Errorf("unknown schema %q, expected one of: %s", kind, strings.Join(SchemaKinds, ", "))
+	}
+	schema["$schema"] = schemaDraft
+	return schema, nil
+}
+
+func flagSchema(flag, typ string, lookup func(flag string) (FlagInfo, bool)) map[string]any {
+	info := FlagInfo{Type: "string"}
+	if lookup != nil {
+		if found, ok := lookup(flag); ok {
+			info = found
+		}
+	}
+	if typ != "" {
+		info.Type = typ
+	}
+	schema := map[string]any{"type": "string"}
+	switch info.Type {
+	case "int":
+		schema["type"] = []any{"integer", "string"}
+	case "float64":
+		schema["type"] = []any{"number", "string"}
+	case "bool":
+		schema["type"] = []any{"boolean", "string"}
+	}
+	if pattern, ok := schemaPatterns[info.Type]; ok {
+		schema["pattern"] = pattern
+	}
+	if info.Description != "" {
+		schema["description"] = info.Description
+	}
+	if def := findFlagDefinition(flag); def != nil {
+		for _, rule := range def.Validation {
+			name, value, ok := strings.Cut(rule, ":")
+			if n, err := strconv.Atoi(value); ok && err == nil {
+				switch name {
+				case "min":
+					schema["minimum"] = n
+				case "max":
+					schema["maximum"] = n
+				}
+			}
+		}
+	}
+	return schema
+}
+
+func findFlagDefinition(flag string) *FlagDefinition {
+	groups := make([]string, 0, len(CommandFlags))
+	for group := range CommandFlags {
+		if group != "common" {
+			groups = append(groups, group)
+		}
+	}
+	sort.Strings(groups)
+	for _, group := range append([]string{"common"}, groups...) {
+		for i, def := range CommandFlags[group] {
+			if def.Name == flag {
+				return &CommandFlags[group][i]
+			}
+		}
+	}
+	return nil
+}
+
+func structSchema(t reflect.Type, field func(f reflect.StructField) map[string]any) map[string]any {
+	properties := map[string]any{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if name == "-" || name == "" {
+			continue
+		}
+		var prop map[string]any
+		if field != nil {
+			prop = field(f)
+		}
+		if prop == nil {
+			prop = typeSchema(f.Type, field)
+		}
+		for k, v := range schemaHints[t.Name()+"."+name] {
+			prop[k] = v
+		}
+		properties[name] = prop
+	}
+	schema := map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if required, ok := schemaRequired[t.Name()]; ok {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func typeSchema(t reflect.Type, field func(f reflect.StructField) map[string]any) map[string]any {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return typeSchema(t.Elem(), field)
+	case reflect.Struct:
+		return structSchema(t, field)
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": typeSchema(t.Elem(), field)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": map[string]any{"type": []any{"string", "number", "boolean"}}}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int64:
+		return map[string]any{"type": "integer", "minimum": 0}
+	}
+	return map[string]any{"type": "string"}
+}
+
+func ValidateAgainstSchema(schema map[string]any, data []byte) []string {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return []string{err.Error()}
+	}
+	if len(doc.Content) == 0 {
+		return nil
+	}
+	var problems []string
+	validateNode(schema, doc.Content[0], "", &problems)
+	return problems
+}
+
+func validateNode(schema map[string]any, n *yaml.Node, path string, problems *[]string) {
+	if n.Kind == yaml.AliasNode && n.Alias != nil {
+		n = n.Alias
+	}
+	report := func(format string, args ...any) {
+		where := path
+		if where == "" {
+			where = "(root)"
+		}
+		*problems = append(*problems, fmt.Sprintf("line %d: %s: %s", n.Line, where, fmt.Sprintf(format, args...)))
+	}
+	actual := nodeType(n)
+	if actual == "null" {
+		return
+	}
+	if types := schemaTypes(schema["type"]); len(types) > 0 && !typeAllowed(types, actual) {
+		report("expected %s, got %s", strings.Join(types, " or "), actual)
+		return
+	}
+
+	switch n.Kind {
+	case yaml.MappingNode:
+		properties, _ := schema["properties"].(map[string]any)
+		seen := map[string]bool{}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, value := n.Content[i].Value, n.Content[i+1]
+			seen[key] = true
+			if prop, ok := properties[key].(map[string]any); ok {
+				validateNode(prop, value, joinPath(path, key), problems)
+				continue
+			}
+			switch extra := schema["additionalProperties"].(type) {
+			case bool:
+				if !extra {
+					*problems = append(*problems, fmt.Sprintf("line %d: %s: unknown field%s", n.Content[i].Line, joinPath(path, key), suggestField(key, properties)))
+				}
+			case map[string]any:
+				validateNode(extra, value, joinPath(path, key), problems)
+			}
+		}
+		if required, ok := schema["required"].([]string); ok {
+			for _, key := range required {
+				if !seen[key] {
+					report("missing required field %s", key)
+				}
+			}
+		}
+	case yaml.SequenceNode:
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range n.Content {
+				validateNode(items, item, fmt.Sprintf("%s[%d]", path, i), problems)
+			}
+		}
+	case yaml.ScalarNode:
+		if enum, ok := schema["enum"].([]any); ok {
+			allowed := false
+			values := make([]string, len(enum))
+			for i, v := range enum {
+				values[i] = fmt.Sprint(v)
+				allowed = allowed || values[i] == n.Value
+			}
+			if !allowed {
+				report("%q is not one of %s", n.Value, strings.Join(values, ", "))
+			}
+		}
+		if pattern, ok := schema["pattern"].(string); ok && actual == "string" {
+			if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(n.Value) {
+				report("%q does not match %s", n.Value, pattern)
+			}
+		}
+		if actual == "integer" || actual == "number" {
+			value, err := strconv.ParseFloat(n.Value, 64)
+			if err != nil {
+				return
+			}
+			if minimum, ok := schema["minimum"].(int); ok && value < float64(minimum) {
+				report("%s is less than %d", n.Value, minimum)
+			}
+			if maximum, ok := schema["maximum"].(int); ok && value > float64(maximum) {
+				report("%s is greater than %d", n.Value, maximum)
+			}
+		}
+	}
+}
+
+func nodeType(n *yaml.Node) string {
+	switch n.Kind {
+	case yaml.MappingNode:
+		return "object"
+	case yaml.SequenceNode:
+		return "array"
+	}
+	switch n.ShortTag() {
+	case "!!null":
+		return "null"
+	case "!!bool":
+		return "boolean"
+	case "!!int":
+		return "integer"
+	case "!!float":
+		return "number"
+	}
+	return "string"
+}
+
+func schemaTypes(v any) []string {
+	switch t := v.(type) {
+	case string:
+		return []string{t}
+	case []any:
+		out := make([]string, 0, len(t))
+		for _, s := range t {
+			out = append(out, fmt.Sprint(s))
+		}
+		return out
+	}
+	return nil
+}
+
+func typeAllowed(types []string, actual string) bool {
+	for _, t := range types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func suggestField(key string, properties map[string]any) string {
+	best, bestDistance := "", len(key)/2+1
+	for name := range properties {
+		if d := editDistance(key, name); d < bestDistance || (d == bestDistance && name < best) {
+			best, bestDistance = name, d
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return fmt.Sprintf(", did you mean %s?", best)
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func decodeStrict(data []byte, out any) error {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(out); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
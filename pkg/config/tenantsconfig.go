@@ -4,8 +4,6 @@ import (
 	"fmt"
 	"os"
 	"strings"
-
-	"gopkg.in/yaml.v3"
 )
 
 type TenantSpec struct {
@@ -23,7 +21,7 @@ func LoadTenantsConfig(path string) (*TenantsFile, error) {
 		return nil, fmt.Errorf("failed to read tenants config: %w", err)
 	}
 	var tf TenantsFile
-	if err := yaml.Unmarshal(data, &tf); err != nil {
+	if err := decodeStrict(data, &tf); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal tenants config: %w", err)
 	}
 	return &tf, nil
@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"osctl/pkg/audit"
 	"strings"
 )
 
@@ -57,6 +58,10 @@ func (c *Client) FindSavedObjects(tenant string, objType string, perPage int) (*
 }
 
 func (c *Client) CreateDataSource(tenant, title, endpoint, user, password string) error {
+	return c.audit("CreateDataSource", tenant, title, c.createDataSource(tenant, title, endpoint, user, password))
+}
+
+func (c *Client) createDataSource(tenant, title, endpoint, user, password string) error {
 	u := fmt.Sprintf("%s/api/saved_objects/data-source", c.baseURL)
 	body := map[string]any{
 		"attributes": map[string]any{
@@ -162,6 +167,10 @@ func (c *Client) CheckIndexPatternExists(tenant, id string) (bool, error) {
 }
 
 func (c *Client) RefreshIndexPattern(tenant, id string, title string) error {
+	return c.audit("RefreshIndexPattern", tenant, title, c.refreshIndexPattern(tenant, id, title))
+}
+
+func (c *Client) refreshIndexPattern(tenant, id string, title string) error {
 	if id == "" {
 		return fmt.Errorf("index pattern id cannot be empty")
 	}
@@ -210,3 +219,77 @@ func (c *Client) RefreshIndexPattern(tenant, id string, title string) error {
 	}
 	return nil
 }
+
+func (c *Client) CreateIndexPattern(tenant, id, title, timeField, dataSourceID string) error {
+	return c.audit("CreateIndexPattern", tenant, title, c.createIndexPattern(tenant, id, title, timeField, dataSourceID))
+}
+
+func (c *Client) createIndexPattern(tenant, id, title, timeField, dataSourceID string) error {
+	if id == "" {
+		return fmt.Errorf("index pattern id cannot be empty")
+	}
+	if title == "" {
+		return fmt.Errorf("index pattern title cannot be empty")
+	}
+	u := fmt.Sprintf("%s/api/saved_objects/index-pattern/%s?overwrite=true", c.baseURL, url.PathEscape(id))
+	body := map[string]any{
+		"attributes": map[string]any{
+			"title":         title,
+			"timeFieldName": timeField,
+		},
+	}
+	if dataSourceID != "" {
+		body["references"] = []map[string]string{{
+			"id":   dataSourceID,
+			"type": "data-source",
+			"name": "dataSource",
+		}}
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal index pattern body: %w", err)
+	}
+	req, err := http.NewRequest("POST", u, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if tenant != "" && tenant != "global" {
+		req.Header.Set("securitytenant", tenant)
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("kibana create index pattern failed: %s — %s", resp.Status, strings.TrimSpace(string(snippet)))
+	}
+	return nil
+}
+
+func (c *Client) FindDataSourceID(tenant, title string) (string, error) {
+	fr, err := c.FindSavedObjects(tenant, "data-source", 10000)
+	if err != nil {
+		return "", err
+	}
+	for _, so := range fr.SavedObjects {
+		if t, ok := so.Attributes["title"].(string); ok && t == title {
+			return so.ID, nil
+		}
+	}
+	return "", nil
+}
+
+func (c *Client) audit(action, tenant, title string, err error) error {
+	if tenant == "" {
+		tenant = "global"
+	}
+	cluster := ""
+	if u, perr := url.Parse(c.baseURL); perr == nil {
+		cluster = u.Hostname()
+	}
+	audit.Log(audit.Record{Cluster: cluster, Action: action, Target: fmt.Sprintf("%s (tenant=%s)", title, tenant)}, err)
+	return err
+}
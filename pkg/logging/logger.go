@@ -1,14 +1,24 @@
 package logging
 
 import (
+	"fmt"
+	"strings"
 	"sync"
 
 	log "github.com/sirupsen/logrus"
 )
 
-type Logger struct{}
+type Fields map[string]any
 
-var initOnce sync.Once
+type Logger struct {
+	fields Fields
+}
+
+var (
+	initOnce  sync.Once
+	contextMu sync.RWMutex
+	context   = Fields{}
+)
 
 func NewLogger() *Logger {
 	initOnce.Do(func() {
@@ -18,14 +28,82 @@ func NewLogger() *Logger {
 	return &Logger{}
 }
 
+func Configure(level, format string) error {
+	NewLogger()
+	lvl, err := log.ParseLevel(level)
+	if err != nil {
+		return fmt.Errorf("invalid log level %q: %v", level, err)
+	}
+	log.SetLevel(lvl)
+	switch format {
+	case "json":
+		log.SetFormatter(&log.JSONFormatter{})
+	case "text":
+		log.SetFormatter(&log.TextFormatter{FullTimestamp: true})
+	default:
+		return fmt.Errorf("invalid log format %q: must be json or text", format)
+	}
+	return nil
+}
+
+func SetContext(key string, value any) {
+	contextMu.Lock()
+	defer contextMu.Unlock()
+	context[key] = value
+}
+
+func (l *Logger) WithFields(fields Fields) *Logger {
+	merged := make(Fields, len(l.fields)+len(fields))
+	for k, v := range l.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = normalize(v)
+	}
+	return &Logger{fields: merged}
+}
+
+func (l *Logger) WithField(key string, value any) *Logger {
+	return l.WithFields(Fields{key: value})
+}
+
+func normalize(v any) any {
+	switch x := v.(type) {
+	case error:
+		return x.Error()
+	case fmt.Stringer:
+		return x.String()
+	case []string:
+		return strings.Join(x, ",")
+	}
+	return v
+}
+
+func (l *Logger) entry() *log.Entry {
+	contextMu.RLock()
+	data := make(log.Fields, len(context)+len(l.fields))
+	for k, v := range context {
+		data[k] = v
+	}
+	contextMu.RUnlock()
+	for k, v := range l.fields {
+		data[k] = v
+	}
+	return log.WithFields(data)
+}
+
+func (l *Logger) Debug(message string) {
+	l.entry().Debug(message)
+}
+
 func (l *Logger) Info(message string) {
-	log.Info(message)
+	l.entry().Info(message)
 }
 
 func (l *Logger) Warn(message string) {
-	log.Warn(message)
+	l.entry().Warn(message)
 }
 
 func (l *Logger) Error(message string) {
-	log.Error(message)
+	l.entry().Error(message)
 }
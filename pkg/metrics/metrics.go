@@ -0,0 +1,237 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	kindCounter = "counter"
+	kindGauge   = "gauge"
+	kindSummary = "summary"
+)
+
+type sample struct {
+	labels []string
+	value  float64
+	count  uint64
+}
+
+type Metric struct {
+	name    string
+	help    string
+	kind    string
+	labels  []string
+	mu      sync.Mutex
+	samples map[string]*sample
+}
+
+type Registry struct {
+	mu      sync.Mutex
+	metrics []*Metric
+}
+
+var Default = &Registry{}
+
+func (r *Registry) register(name, help, kind string, labels []string) *Metric {
+	m := &Metric{name: name, help: help, kind: kind, labels: labels, samples: make(map[string]*sample)}
+	r.mu.Lock()
+	r.metrics = append(r.metrics, m)
+	r.mu.Unlock()
+	return m
+}
+
+func NewCounter(name, help string, labels ...string) *Metric {
+	return Default.register(name, help, kindCounter, labels)
+}
+
+func NewGauge(name, help string, labels ...string) *Metric {
+	return Default.register(name, help, kindGauge, labels)
+}
+
+func NewSummary(name, help string, labels ...string) *Metric {
+	return Default.register(name, help, kindSummary, labels)
+}
+
+func (m *Metric) sample(labelValues []string) *sample {
+	if len(labelValues) != len(m.labels) {
+		panic(fmt.Sprintf("metric %s: expected %d label values, got %d", m.name, len(m.labels), len(labelValues)))
+	}
+	key := strings.Join(labelValues, "\xff")
+	s, ok := m.samples[key]
+	if !ok {
+		s = &sample{labels: append([]string(nil), labelValues...)}
+		m.samples[key] = s
+	}
+	return s
+}
+
+func (m *Metric) Add(v float64, labelValues ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sample(labelValues).value += v
+}
+
+func (m *Metric) Inc(labelValues ...string) {
+	m.Add(1, labelValues...)
+}
+
+func (m *Metric) Set(v float64, labelValues ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sample(labelValues).value = v
+}
+
+func (m *Metric) SetToCurrentTime(labelValues ...string) {
+	m.Set(float64(time.Now().Unix()), labelValues...)
+}
+
+func (m *Metric) Observe(v float64, labelValues ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := m.sample(labelValues)
+	s.value += v
+	s.count++
+}
+
+func (m *Metric) Has(labelValues ...string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.samples[strings.Join(labelValues, "\xff")]
+	return ok
+}
+
+func (m *Metric) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.samples = make(map[string]*sample)
+}
+
+func (m *Metric) write(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.samples) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "# HELP %s %s\n", m.name, m.help)
+	fmt.Fprintf(w, "# TYPE %s %s\n", m.name, m.kind)
+	keys := make([]string, 0, len(m.samples))
+	for k := range m.samples {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		s := m.samples[k]
+		labels := formatLabels(m.labels, s.labels)
+		if m.kind == kindSummary {
+			fmt.Fprintf(w, "%s_sum%s %s\n", m.name, labels, formatValue(s.value))
+			fmt.Fprintf(w, "%s_count%s %d\n", m.name, labels, s.count)
+			continue
+		}
+		fmt.Fprintf(w, "%s%s %s\n", m.name, labels, formatValue(s.value))
+	}
+}
+
+func formatLabels(names, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	parts := make([]string, len(names))
+	for i, name := range names {
+		v := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(values[i])
+		parts[i] = fmt.Sprintf(`%s="%s"`, name, v)
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	metrics := append([]*Metric(nil), r.metrics...)
+	r.mu.Unlock()
+	var buf bytes.Buffer
+	for _, m := range metrics {
+		m.write(&buf)
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := r.WriteText(w); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+func (r *Registry) Push(gatewayURL, job string, grouping map[string]string) error {
+	var body bytes.Buffer
+	if err := r.WriteText(&body); err != nil {
+		return err
+	}
+	target := strings.TrimRight(gatewayURL, "/") + "/metrics/job/" + url.PathEscape(job)
+	keys := make([]string, 0, len(grouping))
+	for k := range grouping {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		target += "/" + url.PathEscape(k) + "/" + url.PathEscape(grouping[k])
+	}
+	req, err := http.NewRequest(http.MethodPost, target, &body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+	resp, err := (&http.Client{Timeout: 30 * time.Second}).Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to push metrics: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("pushgateway returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(snippet)))
+	}
+	return nil
+}
+
+func (r *Registry) WriteTextfile(dir, name string) error {
+	var body bytes.Buffer
+	if err := r.WriteText(&body); err != nil {
+		return err
+	}
+	path := filepath.Join(dir, name+".prom")
+	tmp, err := os.CreateTemp(dir, name+".prom.*")
+	if err != nil {
+		return fmt.Errorf("failed to write metrics file %s: %v", path, err)
+	}
+	if _, err := tmp.Write(body.Bytes()); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write metrics file %s: %v", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write metrics file %s: %v", path, err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write metrics file %s: %v", path, err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
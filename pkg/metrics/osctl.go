@@ -0,0 +1,24 @@
+package metrics
+
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+var (
+	RunsTotal            = NewCounter("osctl_runs_total", "Finished action runs by result.", "action", "result")
+	RunDuration          = NewGauge("osctl_run_duration_seconds", "Duration of the last action run.", "action")
+	LastRunTimestamp     = NewGauge("osctl_last_run_timestamp_seconds", "Unix time of the last finished action run.", "action")
+	LastSuccessTimestamp = NewGauge("osctl_last_success_timestamp_seconds", "Unix time of the last successful action run.", "action")
+
+	IndicesDeleted = NewCounter("osctl_indices_deleted_total", "Indices deleted.")
+	BytesFreed     = NewCounter("osctl_bytes_freed_total", "Store size of deleted indices in bytes.")
+
+	SnapshotsCreated = NewCounter("osctl_snapshots_created_total", "Snapshots created successfully.", "repository")
+	SnapshotsFailed  = NewCounter("osctl_snapshots_failed_total", "Snapshots that failed after all retries.", "repository")
+	SnapshotsMissing = NewGauge("osctl_snapshots_missing", "Indices without a valid snapshot found by the last check, per prefix.", "prefix")
+
+	RestoreDuration = NewSummary("osctl_restore_duration_seconds", "Duration of single index restores.", "repository", "result")
+
+	DiskUtilization = NewGauge("osctl_disk_utilization_percent", "Average disk utilization of data nodes seen by the last check.")
+)
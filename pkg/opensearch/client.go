@@ -5,11 +5,13 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"os"
+	"osctl/pkg/audit"
 	"strings"
 	"time"
 )
@@ -26,12 +28,34 @@ type Client struct {
 	retryAttempts      int
 	es5Compatibility   bool
 	httpClient         *http.Client
+	auditReason        string
 }
 
 func (c *Client) ES5Compatibility() bool {
 	return c.es5Compatibility
 }
 
+func (c *Client) WithAuditReason(reason string) *Client {
+	clone := *c
+	clone.auditReason = reason
+	return &clone
+}
+
+func (c *Client) audit(action, target string, err error) error {
+	return c.auditEach(action, []string{target}, err)
+}
+
+func (c *Client) auditEach(action string, targets []string, err error) error {
+	cluster := ""
+	if u, perr := url.Parse(c.baseURL); perr == nil {
+		cluster = u.Hostname()
+	}
+	for _, target := range targets {
+		audit.Log(audit.Record{Cluster: cluster, Action: action, Target: target, Reason: c.auditReason}, err)
+	}
+	return err
+}
+
 func escapePathSegment(s string) string {
 	return url.PathEscape(strings.TrimLeft(s, "/"))
 }
@@ -121,6 +145,25 @@ func NewClientWithOptions(baseURL string, opts ClientOptions) (*Client, error) {
 	}, nil
 }
 
+type ClientError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *ClientError) Error() string {
+	return fmt.Sprintf("client error: %d — %s", e.StatusCode, e.Message)
+}
+
+func IsNotFound(err error) bool {
+	var ce *ClientError
+	return errors.As(err, &ce) && ce.StatusCode == http.StatusNotFound
+}
+
+func IsConflict(err error) bool {
+	var ce *ClientError
+	return errors.As(err, &ce) && ce.StatusCode == http.StatusConflict
+}
+
 func (c *Client) executeRequest(req *http.Request) (*http.Response, error) {
 	var lastErr error
 
@@ -142,7 +185,7 @@ func (c *Client) executeRequest(req *http.Request) (*http.Response, error) {
 		if resp.StatusCode >= 400 && resp.StatusCode < 500 {
 			snippet := readErrorSnippet(resp)
 			resp.Body.Close()
-			return nil, fmt.Errorf("client error: %d — %s", resp.StatusCode, snippet)
+			return nil, &ClientError{StatusCode: resp.StatusCode, Message: snippet}
 		}
 
 		if resp.StatusCode >= 500 {
@@ -229,6 +272,33 @@ func (c *Client) postJSON(url string, data interface{}) error {
 	return nil
 }
 
+func (c *Client) postJSONWithResult(url string, data interface{}, result interface{}) error {
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal data: %v", err)
+	}
+
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.executeRequest(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("POST %s failed: %s — %s", req.URL.Path, resp.Status, readErrorSnippet(resp))
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, result)
+}
+
 func (c *Client) delete(url string) error {
 	req, err := http.NewRequest("DELETE", url, nil)
 	if err != nil {
@@ -88,3 +88,137 @@ func (c *Client) GetAliases(pattern string) ([]AliasInfo, error) {
 	}
 	return out, nil
 }
+
+type UnassignedShard struct {
+	Index            string `json:"index"`
+	Shard            string `json:"shard"`
+	Prirep           string `json:"prirep"`
+	State            string `json:"state"`
+	UnassignedReason string `json:"unassigned.reason"`
+}
+
+type AllocationDecider struct {
+	Decider     string `json:"decider"`
+	Decision    string `json:"decision"`
+	Explanation string `json:"explanation"`
+}
+
+type AllocationExplanation struct {
+	Index               string `json:"index"`
+	Shard               int    `json:"shard"`
+	Primary             bool   `json:"primary"`
+	CurrentState        string `json:"current_state"`
+	CanAllocate         string `json:"can_allocate"`
+	AllocateExplanation string `json:"allocate_explanation"`
+	UnassignedInfo      struct {
+		Reason               string `json:"reason"`
+		Details              string `json:"details"`
+		LastAllocationStatus string `json:"last_allocation_status"`
+	} `json:"unassigned_info"`
+	NodeAllocationDecisions []struct {
+		NodeName     string              `json:"node_name"`
+		NodeDecision string              `json:"node_decision"`
+		Deciders     []AllocationDecider `json:"deciders"`
+	} `json:"node_allocation_decisions"`
+}
+
+func (c *Client) GetUnassignedShards() ([]UnassignedShard, error) {
+	url := fmt.Sprintf("%s/_cat/shards?format=json&h=index,shard,prirep,state,unassigned.reason", c.baseURL)
+	var rows []UnassignedShard
+	if err := c.getJSON(url, &rows); err != nil {
+		return nil, err
+	}
+	out := make([]UnassignedShard, 0)
+	for _, r := range rows {
+		if r.State == "UNASSIGNED" {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func (c *Client) ExplainAllocation(index string, shard int, primary bool) (*AllocationExplanation, error) {
+	url := fmt.Sprintf("%s/_cluster/allocation/explain", c.baseURL)
+	body := map[string]any{"index": index, "shard": shard, "primary": primary}
+	var exp AllocationExplanation
+	if err := c.postJSONWithResult(url, body, &exp); err != nil {
+		return nil, err
+	}
+	return &exp, nil
+}
+
+func (c *Client) RerouteRetryFailed() error {
+	url := fmt.Sprintf("%s/_cluster/reroute?retry_failed=true", c.baseURL)
+	return c.audit("RerouteRetryFailed", "_cluster", c.postJSON(url, map[string]any{}))
+}
+
+type NodeLoadStats struct {
+	Name                   string
+	CPUPercent             float64
+	Load1m                 float64
+	Processors             int
+	SnapshotActive         int
+	SnapshotQueue          int
+	WriteRejected          int64
+	RecoveryThrottleMillis int64
+}
+
+type nodesLoadResponse struct {
+	Nodes map[string]struct {
+		Name string `json:"name"`
+		OS   struct {
+			CPU struct {
+				Percent     float64            `json:"percent"`
+				LoadAverage map[string]float64 `json:"load_average"`
+			} `json:"cpu"`
+			AllocatedProcessors int `json:"allocated_processors"`
+			AvailableProcessors int `json:"available_processors"`
+		} `json:"os"`
+		ThreadPool map[string]struct {
+			Active   int   `json:"active"`
+			Queue    int   `json:"queue"`
+			Rejected int64 `json:"rejected"`
+		} `json:"thread_pool"`
+		Indices struct {
+			Recovery struct {
+				ThrottleTimeInMillis int64 `json:"throttle_time_in_millis"`
+			} `json:"recovery"`
+		} `json:"indices"`
+	} `json:"nodes"`
+}
+
+func (c *Client) GetNodesLoadStats() ([]NodeLoadStats, error) {
+	var info nodesLoadResponse
+	if err := c.getJSON(fmt.Sprintf("%s/_nodes/os", c.baseURL), &info); err != nil {
+		return nil, err
+	}
+	var stats nodesLoadResponse
+	if err := c.getJSON(fmt.Sprintf("%s/_nodes/stats/os,thread_pool,indices", c.baseURL), &stats); err != nil {
+		return nil, err
+	}
+
+	result := make([]NodeLoadStats, 0, len(stats.Nodes))
+	for id, n := range stats.Nodes {
+		s := NodeLoadStats{
+			Name:                   n.Name,
+			CPUPercent:             n.OS.CPU.Percent,
+			Load1m:                 n.OS.CPU.LoadAverage["1m"],
+			RecoveryThrottleMillis: n.Indices.Recovery.ThrottleTimeInMillis,
+		}
+		if ni, ok := info.Nodes[id]; ok {
+			s.Processors = ni.OS.AllocatedProcessors
+			if s.Processors == 0 {
+				s.Processors = ni.OS.AvailableProcessors
+			}
+		}
+		if tp, ok := n.ThreadPool["snapshot"]; ok {
+			s.SnapshotActive = tp.Active
+			s.SnapshotQueue = tp.Queue
+		}
+		for _, pool := range []string{"write", "bulk", "index"} {
+			s.WriteRejected += n.ThreadPool[pool].Rejected
+		}
+		result = append(result, s)
+	}
+	return result, nil
+}
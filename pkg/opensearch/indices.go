@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
 )
 
 type IndexInfo struct {
@@ -34,8 +36,9 @@ type OSSearchResponse struct {
 }
 
 type DanglingIndex struct {
-	IndexName string `json:"index_name"`
-	IndexUUID string `json:"index_uuid"`
+	IndexName          string `json:"index_name"`
+	IndexUUID          string `json:"index_uuid"`
+	CreationDateMillis int64  `json:"creation_date_millis"`
 }
 
 type DanglingResponse struct {
@@ -101,6 +104,14 @@ func (c *Client) Search(index, query string) (*OSSearchResponse, error) {
 }
 
 func (c *Client) CreateDoc(index, id string, payload interface{}) error {
+	err := c.createDoc(index, id, payload)
+	if strings.HasPrefix(index, ".kibana") {
+		c.audit("CreateSavedObject", index+"/"+id, err)
+	}
+	return err
+}
+
+func (c *Client) createDoc(index, id string, payload interface{}) error {
 	url := fmt.Sprintf("%s/%s/_doc/%s", c.baseURL, escapePathSegment(index), escapePathSegment(id))
 	b, err := json.Marshal(payload)
 	if err != nil {
@@ -122,9 +133,71 @@ func (c *Client) CreateDoc(index, id string, payload interface{}) error {
 	return nil
 }
 
+func (c *Client) GetDoc(index, id string, result interface{}) (bool, error) {
+	url := fmt.Sprintf("%s/%s/_doc/%s", c.baseURL, escapePathSegment(index), escapePathSegment(id))
+	var doc struct {
+		Found  bool            `json:"found"`
+		Source json.RawMessage `json:"_source"`
+	}
+	if err := c.getJSON(url, &doc); err != nil {
+		if IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if !doc.Found {
+		return false, nil
+	}
+	return true, json.Unmarshal(doc.Source, result)
+}
+
+type DocVersion struct {
+	SeqNo       int64
+	PrimaryTerm int64
+}
+
+func (c *Client) GetDocVersioned(index, id string, result interface{}) (*DocVersion, error) {
+	url := fmt.Sprintf("%s/%s/_doc/%s", c.baseURL, escapePathSegment(index), escapePathSegment(id))
+	var doc struct {
+		Found       bool            `json:"found"`
+		SeqNo       int64           `json:"_seq_no"`
+		PrimaryTerm int64           `json:"_primary_term"`
+		Source      json.RawMessage `json:"_source"`
+	}
+	if err := c.getJSON(url, &doc); err != nil {
+		if IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !doc.Found {
+		return nil, nil
+	}
+	return &DocVersion{SeqNo: doc.SeqNo, PrimaryTerm: doc.PrimaryTerm}, json.Unmarshal(doc.Source, result)
+}
+
+func (c *Client) PutDocIfVersion(index, id string, payload interface{}, version *DocVersion) error {
+	url := fmt.Sprintf("%s/%s/_doc/%s?op_type=create", c.baseURL, escapePathSegment(index), escapePathSegment(id))
+	if version != nil {
+		url = fmt.Sprintf("%s/%s/_doc/%s?if_seq_no=%d&if_primary_term=%d", c.baseURL, escapePathSegment(index), escapePathSegment(id), version.SeqNo, version.PrimaryTerm)
+	}
+	return c.putJSON(url, payload)
+}
+
 func (c *Client) DeleteIndex(index string) error {
 	url := fmt.Sprintf("%s/%s", c.baseURL, escapePathSegment(index))
-	return c.delete(url)
+	return c.audit("DeleteIndex", index, c.delete(url))
+}
+
+func (c *Client) CountDocs(index string) (int64, error) {
+	url := fmt.Sprintf("%s/%s/_count", c.baseURL, escapePathSegment(index))
+	var data struct {
+		Count int64 `json:"count"`
+	}
+	if err := c.getJSON(url, &data); err != nil {
+		return 0, err
+	}
+	return data.Count, nil
 }
 
 func (c *Client) DeleteIndices(indices []string) error {
@@ -134,7 +207,7 @@ func (c *Client) DeleteIndices(indices []string) error {
 
 	indicesList := escapePathList(indices)
 	url := fmt.Sprintf("%s/%s", c.baseURL, indicesList)
-	return c.delete(url)
+	return c.auditEach("DeleteIndices", indices, c.delete(url))
 }
 
 func (c *Client) GetDanglingIndices() ([]DanglingIndex, error) {
@@ -148,6 +221,16 @@ func (c *Client) GetDanglingIndices() ([]DanglingIndex, error) {
 	return result.DanglingIndices, nil
 }
 
+func (c *Client) ImportDanglingIndex(uuid string) error {
+	url := fmt.Sprintf("%s/_dangling/%s?accept_data_loss=true", c.baseURL, escapePathSegment(uuid))
+	return c.audit("ImportDanglingIndex", uuid, c.postJSON(url, map[string]any{}))
+}
+
+func (c *Client) DeleteDanglingIndex(uuid string) error {
+	url := fmt.Sprintf("%s/_dangling/%s?accept_data_loss=true", c.baseURL, escapePathSegment(uuid))
+	return c.audit("DeleteDanglingIndex", uuid, c.delete(url))
+}
+
 func (c *Client) SetReplicas(index string, replicas int) error {
 	url := fmt.Sprintf("%s/%s/_settings", c.baseURL, escapePathSegment(index))
 
@@ -157,7 +240,7 @@ func (c *Client) SetReplicas(index string, replicas int) error {
 		},
 	}
 
-	return c.putJSON(url, settings)
+	return c.audit("SetReplicas", fmt.Sprintf("%s replicas=%d", index, replicas), c.putJSON(url, settings))
 }
 
 func (c *Client) SetColdStorage(index, coldAttribute string) error {
@@ -170,7 +253,7 @@ func (c *Client) SetColdStorage(index, coldAttribute string) error {
 		},
 	}
 
-	return c.putJSON(url, settings)
+	return c.audit("SetColdStorage", fmt.Sprintf("%s temp=%s", index, coldAttribute), c.putJSON(url, settings))
 }
 
 func (c *Client) GetIndexColdRequirement(index string) (string, error) {
@@ -226,3 +309,103 @@ func (c *Client) GetIndexColdRequirement(index string) (string, error) {
 	}
 	return "", nil
 }
+
+func (c *Client) GetIndicesColdRequirement(pattern string) (map[string]string, error) {
+	url := fmt.Sprintf("%s/%s/_settings/index.routing.allocation.require.temp?flat_settings=true", c.baseURL, escapePathSegment(pattern))
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	resp, err := c.executeRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("GET %s failed: %s — %s", req.URL.Path, resp.Status, readErrorSnippet(resp))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]struct {
+		Settings map[string]string `json:"settings"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+	result := make(map[string]string, len(raw))
+	for index, data := range raw {
+		if v, ok := data.Settings["index.routing.allocation.require.temp"]; ok {
+			result[index] = v
+		}
+	}
+	return result, nil
+}
+
+type IndexMapping struct {
+	Mappings map[string]any `json:"mappings"`
+}
+
+func (c *Client) GetMappings(pattern string) (map[string]IndexMapping, error) {
+	url := fmt.Sprintf("%s/%s/_mapping", c.baseURL, escapePathSegment(pattern))
+	result := map[string]IndexMapping{}
+	if err := c.getJSON(url, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) GetTotalFieldsLimits(pattern string) (map[string]int, error) {
+	url := fmt.Sprintf("%s/%s/_settings/index.mapping.total_fields.limit?include_defaults=true&flat_settings=true", c.baseURL, escapePathSegment(pattern))
+	var raw map[string]struct {
+		Settings map[string]any `json:"settings"`
+		Defaults map[string]any `json:"defaults"`
+	}
+	if err := c.getJSON(url, &raw); err != nil {
+		return nil, err
+	}
+	limits := make(map[string]int, len(raw))
+	for index, data := range raw {
+		v, ok := data.Settings["index.mapping.total_fields.limit"]
+		if !ok {
+			v = data.Defaults["index.mapping.total_fields.limit"]
+		}
+		if s, ok := v.(string); ok {
+			if n, err := strconv.Atoi(s); err == nil {
+				limits[index] = n
+			}
+		}
+	}
+	return limits, nil
+}
+
+func (c *Client) StartReindex(body map[string]any) (string, error) {
+	url := fmt.Sprintf("%s/_reindex?wait_for_completion=false", c.baseURL)
+	var data struct {
+		Task string `json:"task"`
+	}
+	if err := c.postJSONWithResult(url, body, &data); err != nil {
+		return "", c.audit("StartReindex", reindexTarget(body), err)
+	}
+	if data.Task == "" {
+		return "", c.audit("StartReindex", reindexTarget(body), fmt.Errorf("reindex did not return a task id"))
+	}
+	c.audit("StartReindex", reindexTarget(body), nil)
+	return data.Task, nil
+}
+
+func reindexTarget(body map[string]any) string {
+	var source, dest any
+	if s, ok := body["source"].(map[string]any); ok {
+		source = s["index"]
+	}
+	if d, ok := body["dest"].(map[string]any); ok {
+		dest = d["index"]
+	}
+	return fmt.Sprintf("%v → %v", source, dest)
+}
@@ -0,0 +1,59 @@
+package opensearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type Repository struct {
+	Type     string         `json:"type"`
+	Settings map[string]any `json:"settings"`
+}
+
+func (c *Client) GetRepositories() (map[string]Repository, error) {
+	url := fmt.Sprintf("%s/_snapshot", c.baseURL)
+	repos := map[string]Repository{}
+	if err := c.getJSON(url, &repos); err != nil {
+		return nil, err
+	}
+	return repos, nil
+}
+
+func (c *Client) PutRepository(name string, repo Repository) error {
+	url := fmt.Sprintf("%s/_snapshot/%s", c.baseURL, escapePathSegment(name))
+	return c.audit("PutRepository", name, c.putJSON(url, repo))
+}
+
+func (c *Client) VerifyRepository(name string) ([]string, error) {
+	url := fmt.Sprintf("%s/_snapshot/%s/_verify", c.baseURL, escapePathSegment(name))
+	req, err := http.NewRequest("POST", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	resp, err := c.executeRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("POST %s failed: %s — %s", req.URL.Path, resp.Status, readErrorSnippet(resp))
+	}
+	var data struct {
+		Nodes map[string]struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, err
+	}
+	nodes := make([]string, 0, len(data.Nodes))
+	for id, n := range data.Nodes {
+		if n.Name != "" {
+			nodes = append(nodes, n.Name)
+		} else {
+			nodes = append(nodes, id)
+		}
+	}
+	return nodes, nil
+}
@@ -20,7 +20,15 @@ func (c *Client) GetSnapshotsDetailed(repo, pattern string) ([]Snapshot, error)
 
 func (c *Client) RestoreSnapshot(repo, snapshot string, body map[string]any) error {
 	url := fmt.Sprintf("%s/_snapshot/%s/%s/_restore?wait_for_completion=false", c.baseURL, escapePathSegment(repo), escapePathSegment(snapshot))
-	return c.postJSON(url, body)
+	return c.audit("RestoreSnapshot", restoreTarget(repo, snapshot, body), c.postJSON(url, body))
+}
+
+func restoreTarget(repo, snapshot string, body map[string]any) string {
+	target := fmt.Sprintf("%s/%s indices=%v", repo, snapshot, body["indices"])
+	if as, ok := body["rename_replacement"]; ok {
+		target += fmt.Sprintf(" as %v", as)
+	}
+	return target
 }
 
 func (c *Client) IndexExists(index string) (bool, error) {
@@ -4,14 +4,17 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 )
 
 type Snapshot struct {
-	Snapshot          string   `json:"snapshot"`
-	State             string   `json:"state"`
-	Indices           []string `json:"indices"`
-	StartTimeInMillis int64    `json:"start_time_in_millis"`
-	DurationInMillis  int64    `json:"duration_in_millis"`
+	Snapshot          string         `json:"snapshot"`
+	UUID              string         `json:"uuid"`
+	State             string         `json:"state"`
+	Indices           []string       `json:"indices"`
+	StartTimeInMillis int64          `json:"start_time_in_millis"`
+	DurationInMillis  int64          `json:"duration_in_millis"`
+	Metadata          map[string]any `json:"metadata,omitempty"`
 }
 
 type SnapshotResponse struct {
@@ -96,7 +99,16 @@ func (c *Client) GetSnapshots(repo, pattern string) ([]Snapshot, error) {
 func (c *Client) CreateSnapshot(repo, snapshot string, body map[string]any) error {
 	url := fmt.Sprintf("%s/_snapshot/%s/%s", c.baseURL, escapePathSegment(repo), escapePathSegment(snapshot))
 
-	return c.putJSON(url, body)
+	return c.audit("CreateSnapshot", repo+"/"+snapshot, c.putJSON(url, body))
+}
+
+func (c *Client) CloneSnapshot(repo, source, target string, indices []string) error {
+	url := fmt.Sprintf("%s/_snapshot/%s/%s/_clone/%s", c.baseURL, escapePathSegment(repo), escapePathSegment(source), escapePathSegment(target))
+	indicesParam := "*"
+	if len(indices) > 0 {
+		indicesParam = strings.Join(indices, ",")
+	}
+	return c.audit("CloneSnapshot", fmt.Sprintf("%s/%s → %s/%s", repo, source, repo, target), c.putJSON(url, map[string]any{"indices": indicesParam}))
 }
 
 func (c *Client) DeleteSnapshots(snapRepo string, snapshotNames []string) error {
@@ -115,12 +127,16 @@ func (c *Client) DeleteSnapshots(snapRepo string, snapshotNames []string) error
 
 	snapshotsList := escapePathList(snapshotNames)
 	url := fmt.Sprintf("%s/_snapshot/%s/%s", c.baseURL, escapePathSegment(snapRepo), snapshotsList)
-	return c.delete(url)
+	targets := make([]string, len(snapshotNames))
+	for i, name := range snapshotNames {
+		targets[i] = snapRepo + "/" + name
+	}
+	return c.auditEach("DeleteSnapshots", targets, c.delete(url))
 }
 
 func (c *Client) DeleteSnapshot(snapRepo, snapshotName string) error {
 	url := fmt.Sprintf("%s/_snapshot/%s/%s", c.baseURL, escapePathSegment(snapRepo), escapePathSegment(snapshotName))
-	return c.delete(url)
+	return c.audit("DeleteSnapshot", snapRepo+"/"+snapshotName, c.delete(url))
 }
 
 func (c *Client) GetSnapshotStatus() (*SnapshotStatus, error) {
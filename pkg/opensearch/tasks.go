@@ -42,3 +42,46 @@ func (c *Client) GetTasks() (*TasksResponse, error) {
 
 	return &tasks, nil
 }
+
+type TaskStatus struct {
+	Completed bool `json:"completed"`
+	Task      struct {
+		Status struct {
+			Total   int64 `json:"total"`
+			Created int64 `json:"created"`
+			Batches int64 `json:"batches"`
+		} `json:"status"`
+	} `json:"task"`
+	Response struct {
+		Created  int64 `json:"created"`
+		Total    int64 `json:"total"`
+		Failures []any `json:"failures"`
+	} `json:"response"`
+	Error map[string]any `json:"error"`
+}
+
+func (c *Client) GetTask(taskID string) (*TaskStatus, error) {
+	url := fmt.Sprintf("%s/_tasks/%s", c.baseURL, escapePathSegment(taskID))
+	var status TaskStatus
+	if err := c.getJSON(url, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+func (c *Client) CancelTask(taskID string) error {
+	url := fmt.Sprintf("%s/_tasks/%s/_cancel", c.baseURL, escapePathSegment(taskID))
+	req, err := http.NewRequest("POST", url, nil)
+	if err != nil {
+		return c.audit("CancelTask", taskID, err)
+	}
+	resp, err := c.executeRequest(req)
+	if err != nil {
+		return c.audit("CancelTask", taskID, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		err = fmt.Errorf("POST %s failed: %s — %s", req.URL.Path, resp.Status, readErrorSnippet(resp))
+	}
+	return c.audit("CancelTask", taskID, err)
+}
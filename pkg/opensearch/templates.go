@@ -39,7 +39,7 @@ func (c *Client) FindIndexTemplateByPattern(pattern string) (string, error) {
 
 func (c *Client) PutIndexTemplate(name string, body map[string]any) error {
 	url := fmt.Sprintf("%s/_index_template/%s", c.baseURL, name)
-	return c.putJSON(url, body)
+	return c.audit("PutIndexTemplate", name, c.putJSON(url, body))
 }
 
 func (c *Client) GetIndexTemplate(name string) (*IndexTemplate, error) {
@@ -59,3 +59,24 @@ func (c *Client) GetAllIndexTemplates() (*IndexTemplate, error) {
 	}
 	return &it, nil
 }
+
+type LegacyTemplate struct {
+	Order         int      `json:"order"`
+	IndexPatterns []string `json:"index_patterns"`
+	Template      string   `json:"template,omitempty"`
+}
+
+func (c *Client) GetLegacyTemplates() (map[string]LegacyTemplate, error) {
+	url := fmt.Sprintf("%s/_template", c.baseURL)
+	templates := map[string]LegacyTemplate{}
+	if err := c.getJSON(url, &templates); err != nil {
+		return nil, err
+	}
+	for name, t := range templates {
+		if len(t.IndexPatterns) == 0 && t.Template != "" {
+			t.IndexPatterns = []string{t.Template}
+			templates[name] = t
+		}
+	}
+	return templates, nil
+}
@@ -0,0 +1,136 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	StatusSuccess = "success"
+	StatusFailure = "failure"
+
+	IndexPrefix = "osctl-runs-"
+)
+
+type Item struct {
+	Name            string  `json:"name"`
+	Reason          string  `json:"reason,omitempty"`
+	DurationSeconds float64 `json:"durationSeconds,omitempty"`
+}
+
+type Totals struct {
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+	Skipped   int `json:"skipped"`
+}
+
+type RunReport struct {
+	Timestamp       time.Time `json:"@timestamp"`
+	RunID           string    `json:"runId"`
+	Action          string    `json:"action"`
+	Version         string    `json:"version,omitempty"`
+	Cluster         string    `json:"cluster,omitempty"`
+	DryRun          bool      `json:"dryRun"`
+	Status          string    `json:"status"`
+	Error           string    `json:"error,omitempty"`
+	StartedAt       time.Time `json:"startedAt"`
+	FinishedAt      time.Time `json:"finishedAt"`
+	DurationSeconds float64   `json:"durationSeconds"`
+	Totals          Totals    `json:"totals"`
+	Succeeded       []*Item   `json:"succeeded"`
+	Failed          []*Item   `json:"failed"`
+	Skipped         []*Item   `json:"skipped"`
+}
+
+var (
+	mu      sync.Mutex
+	current = &RunReport{}
+)
+
+func Start(action, runID string) *RunReport {
+	mu.Lock()
+	defer mu.Unlock()
+	now := time.Now().UTC()
+	current = &RunReport{
+		Timestamp: now,
+		RunID:     runID,
+		Action:    action,
+		StartedAt: now,
+		Succeeded: []*Item{},
+		Failed:    []*Item{},
+		Skipped:   []*Item{},
+	}
+	return current
+}
+
+func Current() *RunReport {
+	mu.Lock()
+	defer mu.Unlock()
+	return current
+}
+
+func Finish(err error) *RunReport {
+	mu.Lock()
+	defer mu.Unlock()
+	r := current
+	r.FinishedAt = time.Now().UTC()
+	r.DurationSeconds = r.FinishedAt.Sub(r.StartedAt).Seconds()
+	r.Status = StatusSuccess
+	if err != nil {
+		r.Status = StatusFailure
+		r.Error = err.Error()
+	}
+	r.Totals = Totals{Succeeded: len(r.Succeeded), Failed: len(r.Failed), Skipped: len(r.Skipped)}
+	return r
+}
+
+func add(list func(r *RunReport) *[]*Item, name, reason string) *Item {
+	mu.Lock()
+	defer mu.Unlock()
+	item := &Item{Name: name, Reason: reason}
+	items := list(current)
+	*items = append(*items, item)
+	return item
+}
+
+func Succeeded(name string) *Item {
+	return add(func(r *RunReport) *[]*Item { return &r.Succeeded }, name, "")
+}
+
+func Failed(name string, reason any) *Item {
+	return add(func(r *RunReport) *[]*Item { return &r.Failed }, name, fmt.Sprint(reason))
+}
+
+func Skipped(name, reason string) *Item {
+	return add(func(r *RunReport) *[]*Item { return &r.Skipped }, name, reason)
+}
+
+func (i *Item) Took(d time.Duration) *Item {
+	mu.Lock()
+	defer mu.Unlock()
+	i.DurationSeconds = d.Seconds()
+	return i
+}
+
+func (r *RunReport) Write(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if path == "-" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write report %s: %v", path, err)
+	}
+	return nil
+}
+
+func (r *RunReport) IndexName() string {
+	return IndexPrefix + r.StartedAt.Format("2006.01.02")
+}
@@ -0,0 +1,241 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"osctl/pkg/opensearch"
+	"path"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	AlertFiring   = "firing"
+	AlertResolved = "resolved"
+
+	alertStateDocID = "alerts"
+
+	alertStateUpdateAttempts = 5
+)
+
+type AlertRecord struct {
+	Trigger      string    `json:"trigger"`
+	Subject      string    `json:"subject"`
+	Status       string    `json:"status"`
+	Severity     string    `json:"severity"`
+	Count        int       `json:"count"`
+	FirstSeen    time.Time `json:"first_seen"`
+	LastSeen     time.Time `json:"last_seen"`
+	LastNotified time.Time `json:"last_notified"`
+	ResolvedAt   time.Time `json:"resolved_at"`
+}
+
+type Silence struct {
+	ID        string    `json:"id"`
+	Trigger   string    `json:"trigger"`
+	Subject   string    `json:"subject"`
+	Reason    string    `json:"reason"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (s *Silence) Active(now time.Time) bool {
+	return now.Before(s.ExpiresAt)
+}
+
+func (s *Silence) Matches(trigger, subject string, now time.Time) bool {
+	if !s.Active(now) {
+		return false
+	}
+	if s.Trigger != "" && s.Trigger != "*" && s.Trigger != trigger {
+		return false
+	}
+	if s.Subject == "" || s.Subject == "*" {
+		return true
+	}
+	matched, err := path.Match(s.Subject, subject)
+	return err == nil && matched
+}
+
+type AlertState struct {
+	Alerts    map[string]*AlertRecord `json:"alerts"`
+	Silences  []*Silence              `json:"silences"`
+	UpdatedAt time.Time               `json:"updated_at"`
+}
+
+func AlertKey(trigger, subject string) string {
+	return trigger + "/" + subject
+}
+
+func (st *AlertState) Silenced(trigger, subject string, now time.Time) *Silence {
+	for _, s := range st.Silences {
+		if s.Matches(trigger, subject, now) {
+			return s
+		}
+	}
+	return nil
+}
+
+func (st *AlertState) PruneSilences(now time.Time) {
+	active := st.Silences[:0]
+	for _, s := range st.Silences {
+		if s.Active(now) {
+			active = append(active, s)
+		}
+	}
+	st.Silences = active
+}
+
+func (st *AlertState) PruneResolved(before time.Time) {
+	for key, r := range st.Alerts {
+		if r.Status == AlertResolved && r.ResolvedAt.Before(before) {
+			delete(st.Alerts, key)
+		}
+	}
+}
+
+func (st *AlertState) Records() []*AlertRecord {
+	records := make([]*AlertRecord, 0, len(st.Alerts))
+	for _, r := range st.Alerts {
+		records = append(records, r)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Trigger != records[j].Trigger {
+			return records[i].Trigger < records[j].Trigger
+		}
+		return records[i].Subject < records[j].Subject
+	})
+	return records
+}
+
+func newAlertState() *AlertState {
+	return &AlertState{Alerts: make(map[string]*AlertRecord)}
+}
+
+type AlertStore interface {
+	LoadAlerts() (*AlertState, error)
+	UpdateAlerts(update func(st *AlertState) bool) error
+}
+
+type AlertIndexStore struct {
+	client *opensearch.Client
+	index  string
+}
+
+func NewAlertIndexStore(client *opensearch.Client, index string) *AlertIndexStore {
+	return &AlertIndexStore{client: client, index: index}
+}
+
+func (s *AlertIndexStore) LoadAlerts() (*AlertState, error) {
+	st, _, err := s.load()
+	return st, err
+}
+
+func (s *AlertIndexStore) load() (*AlertState, *opensearch.DocVersion, error) {
+	st := newAlertState()
+	version, err := s.client.GetDocVersioned(s.index, alertStateDocID, st)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read alert state from %s: %v", s.index, err)
+	}
+	if st.Alerts == nil {
+		st.Alerts = make(map[string]*AlertRecord)
+	}
+	return st, version, nil
+}
+
+func (s *AlertIndexStore) UpdateAlerts(update func(st *AlertState) bool) error {
+	for attempt := 1; attempt <= alertStateUpdateAttempts; attempt++ {
+		st, version, err := s.load()
+		if err != nil {
+			return err
+		}
+		if !update(st) {
+			return nil
+		}
+		st.UpdatedAt = time.Now().UTC()
+		err = s.client.PutDocIfVersion(s.index, alertStateDocID, st, version)
+		if err == nil {
+			return nil
+		}
+		if !opensearch.IsConflict(err) {
+			return fmt.Errorf("failed to write alert state to %s: %v", s.index, err)
+		}
+		time.Sleep(time.Duration(attempt) * 200 * time.Millisecond)
+	}
+	return fmt.Errorf("failed to write alert state to %s: document changed concurrently %d times", s.index, alertStateUpdateAttempts)
+}
+
+type AlertFileStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewAlertFileStore(path string) *AlertFileStore {
+	return &AlertFileStore{path: path}
+}
+
+func (s *AlertFileStore) LoadAlerts() (*AlertState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load()
+}
+
+func (s *AlertFileStore) UpdateAlerts(update func(st *AlertState) bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, err := s.load()
+	if err != nil {
+		return err
+	}
+	if !update(st) {
+		return nil
+	}
+	return s.save(st)
+}
+
+func (s *AlertFileStore) load() (*AlertState, error) {
+	st := newAlertState()
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return st, nil
+		}
+		return nil, fmt.Errorf("failed to read alert state file %s: %v", s.path, err)
+	}
+	if len(data) == 0 {
+		return st, nil
+	}
+	if err := json.Unmarshal(data, st); err != nil {
+		return nil, fmt.Errorf("failed to parse alert state file %s: %v", s.path, err)
+	}
+	if st.Alerts == nil {
+		st.Alerts = make(map[string]*AlertRecord)
+	}
+	return st, nil
+}
+
+func (s *AlertFileStore) save(st *AlertState) error {
+	st.UpdatedAt = time.Now().UTC()
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write alert state file %s: %v", s.path, err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write alert state file %s: %v", s.path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write alert state file %s: %v", s.path, err)
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
@@ -0,0 +1,221 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"osctl/pkg/opensearch"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	RunRunning = "running"
+	RunDone    = "done"
+	RunFailed  = "failed"
+
+	TaskPending = "pending"
+	TaskRunning = "running"
+	TaskSuccess = "success"
+	TaskPartial = "partial"
+	TaskFailed  = "failed"
+
+	ModeDaily      = "daily"
+	ModeFullPrefix = "full-prefix"
+)
+
+type Task struct {
+	Repo      string    `json:"repo"`
+	Snapshot  string    `json:"snapshot"`
+	Indices   string    `json:"indices"`
+	Status    string    `json:"status"`
+	Attempts  int       `json:"attempts"`
+	LastError string    `json:"last_error,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type Run struct {
+	ID        string    `json:"run_id"`
+	Action    string    `json:"action"`
+	Mode      string    `json:"mode"`
+	Repo      string    `json:"repo"`
+	Date      string    `json:"date"`
+	Pod       string    `json:"pod"`
+	Status    string    `json:"status"`
+	Resumed   int       `json:"resumed"`
+	StartedAt time.Time `json:"started_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Tasks     []*Task   `json:"tasks"`
+}
+
+func (r *Run) Task(repo, snapshot string) *Task {
+	for _, t := range r.Tasks {
+		if t.Repo == repo && t.Snapshot == snapshot {
+			return t
+		}
+	}
+	return nil
+}
+
+func (r *Run) Counts() map[string]int {
+	counts := make(map[string]int)
+	for _, t := range r.Tasks {
+		counts[t.Status]++
+	}
+	return counts
+}
+
+func (r *Run) Key() string {
+	return RunKey(r.Action, r.Mode, r.Repo, r.Date)
+}
+
+type Store interface {
+	Load(key string) (*Run, error)
+	Save(run *Run) error
+	List() ([]*Run, error)
+}
+
+func RunKey(action, mode, repo, date string) string {
+	return strings.Join([]string{action, mode, repo, date}, "-")
+}
+
+func sortRuns(runs []*Run) {
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].StartedAt.After(runs[j].StartedAt)
+	})
+}
+
+type IndexStore struct {
+	client *opensearch.Client
+	index  string
+}
+
+func NewIndexStore(client *opensearch.Client, index string) *IndexStore {
+	return &IndexStore{client: client, index: index}
+}
+
+func (s *IndexStore) Load(key string) (*Run, error) {
+	var run Run
+	found, err := s.client.GetDoc(s.index, key, &run)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read run state from %s: %v", s.index, err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &run, nil
+}
+
+func (s *IndexStore) Save(run *Run) error {
+	if err := s.client.CreateDoc(s.index, run.Key(), run); err != nil {
+		return fmt.Errorf("failed to write run state to %s: %v", s.index, err)
+	}
+	return nil
+}
+
+func (s *IndexStore) List() ([]*Run, error) {
+	resp, err := s.client.Search(s.index, "size=1000")
+	if err != nil {
+		if opensearch.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list runs in %s: %v", s.index, err)
+	}
+	var runs []*Run
+	for _, hit := range resp.Hits.Hits {
+		raw, err := json.Marshal(hit.Source)
+		if err != nil {
+			continue
+		}
+		var run Run
+		if err := json.Unmarshal(raw, &run); err != nil {
+			continue
+		}
+		runs = append(runs, &run)
+	}
+	sortRuns(runs)
+	return runs, nil
+}
+
+type FileStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+func (s *FileStore) read() (map[string]*Run, error) {
+	runs := make(map[string]*Run)
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return runs, nil
+		}
+		return nil, fmt.Errorf("failed to read state file %s: %v", s.path, err)
+	}
+	if len(data) == 0 {
+		return runs, nil
+	}
+	if err := json.Unmarshal(data, &runs); err != nil {
+		return nil, fmt.Errorf("failed to parse state file %s: %v", s.path, err)
+	}
+	return runs, nil
+}
+
+func (s *FileStore) Load(key string) (*Run, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	runs, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	return runs[key], nil
+}
+
+func (s *FileStore) Save(run *Run) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	runs, err := s.read()
+	if err != nil {
+		return err
+	}
+	runs[run.Key()] = run
+	data, err := json.MarshalIndent(runs, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write state file %s: %v", s.path, err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write state file %s: %v", s.path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write state file %s: %v", s.path, err)
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+func (s *FileStore) List() ([]*Run, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	runs := make([]*Run, 0, len(stored))
+	for _, run := range stored {
+		runs = append(runs, run)
+	}
+	sortRuns(runs)
+	return runs, nil
+}
@@ -0,0 +1,232 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"osctl/pkg/alerts"
+	"osctl/pkg/config"
+	"osctl/pkg/logging"
+	"osctl/pkg/opensearch"
+	"osctl/pkg/state"
+	"strings"
+	"sync"
+	"time"
+)
+
+const resolvedAlertRetention = 30 * 24 * time.Hour
+
+func NewAlertStore(cfg *config.Config, client *opensearch.Client) state.AlertStore {
+	switch cfg.GetAlertStateStore() {
+	case "index":
+		return state.NewAlertIndexStore(client, cfg.GetAlertStateIndex())
+	case "file":
+		return state.NewAlertFileStore(cfg.GetAlertStateFile())
+	}
+	return nil
+}
+
+type AlertTracker struct {
+	next    alerts.Notifier
+	repeat  alerts.Notifier
+	all     alerts.Notifier
+	store   state.AlertStore
+	window  time.Duration
+	resolve bool
+	logger  *logging.Logger
+	mu      sync.Mutex
+}
+
+func NewAlertTracker(next, repeat alerts.Notifier, store state.AlertStore, window time.Duration, resolve bool, logger *logging.Logger) *AlertTracker {
+	return &AlertTracker{next: next, repeat: repeat, all: joinNotifiers(next, repeat), store: store, window: window, resolve: resolve, logger: logger}
+}
+
+func (t *AlertTracker) Name() string {
+	return t.all.Name()
+}
+
+func (t *AlertTracker) Notify(e alerts.Event) (string, error) {
+	if e.Resolved() || len(e.Items) == 0 {
+		return t.all.Notify(e)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now().UTC()
+	var notify, deduplicated, silenced []string
+	err := t.store.UpdateAlerts(func(st *state.AlertState) bool {
+		notify, deduplicated, silenced = nil, nil, nil
+		for _, item := range e.Items {
+			key := state.AlertKey(string(e.Type), item)
+			r := st.Alerts[key]
+			if r == nil || r.Status == state.AlertResolved {
+				r = &state.AlertRecord{Trigger: string(e.Type), Subject: item, FirstSeen: now}
+				st.Alerts[key] = r
+			}
+			r.Status = state.AlertFiring
+			r.Severity = e.Severity
+			r.Count++
+			r.LastSeen = now
+			switch {
+			case st.Silenced(string(e.Type), item, now) != nil:
+				silenced = append(silenced, item)
+			case t.window > 0 && now.Sub(r.LastNotified) < t.window:
+				deduplicated = append(deduplicated, item)
+			default:
+				notify = append(notify, item)
+			}
+		}
+		st.PruneSilences(now)
+		st.PruneResolved(now.Add(-resolvedAlertRetention))
+		return true
+	})
+	if err != nil {
+		t.logger.WithFields(logging.Fields{"type": e.Type, "error": err}).Warn("Alert state unavailable, sending without deduplication")
+		return t.all.Notify(e)
+	}
+	if len(silenced) > 0 {
+		t.logger.WithFields(logging.Fields{"type": e.Type, "subjects": strings.Join(silenced, ",")}).Info("Alert subjects silenced")
+	}
+	if len(notify) == 0 {
+		t.logger.WithFields(logging.Fields{"type": e.Type, "deduplicated": len(deduplicated), "silenced": len(silenced), "window": t.window}).Info("Alert suppressed")
+	}
+
+	var responses []string
+	var errs []error
+	delivered := false
+	send := func(n alerts.Notifier, items []string) {
+		if n == nil || len(items) == 0 {
+			return
+		}
+		filtered := e
+		filtered.Items = items
+		response, err := n.Notify(filtered)
+		if response != "" {
+			responses = append(responses, response)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if alerts.Delivered(err) {
+			delivered = true
+		}
+	}
+	send(t.next, notify)
+	send(t.repeat, append(append([]string(nil), notify...), deduplicated...))
+	if len(responses) == 0 && len(errs) == 0 {
+		return fmt.Sprintf("suppressed deduplicated=%d silenced=%d", len(deduplicated), len(silenced)), nil
+	}
+
+	if delivered && len(notify) > 0 {
+		serr := t.store.UpdateAlerts(func(st *state.AlertState) bool {
+			for _, item := range notify {
+				if r := st.Alerts[state.AlertKey(string(e.Type), item)]; r != nil {
+					r.LastNotified = now
+				}
+			}
+			return true
+		})
+		if serr != nil {
+			t.logger.WithFields(logging.Fields{"type": e.Type, "error": serr}).Warn("Failed to save alert state")
+		}
+	}
+	err = errors.Join(errs...)
+	if delivered && err != nil {
+		err = &alerts.PartialError{Err: err}
+	}
+	return strings.Join(responses, " "), err
+}
+
+func (t *AlertTracker) Resolve(trigger alerts.EventType, active []string) (string, error) {
+	activeSet := make(map[string]bool, len(active))
+	for _, a := range active {
+		activeSet[a] = true
+	}
+	return t.resolveWhere(trigger, func(subject string) bool { return !activeSet[subject] })
+}
+
+func (t *AlertTracker) ResolveSubjects(trigger alerts.EventType, subjects []string) (string, error) {
+	cleared := make(map[string]bool, len(subjects))
+	for _, s := range subjects {
+		cleared[s] = true
+	}
+	return t.resolveWhere(trigger, func(subject string) bool { return cleared[subject] })
+}
+
+func (t *AlertTracker) resolveWhere(trigger alerts.EventType, cleared func(subject string) bool) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now().UTC()
+	var resolved []string
+	severity := ""
+	err := t.store.UpdateAlerts(func(st *state.AlertState) bool {
+		resolved, severity = nil, ""
+		changed := false
+		for _, r := range st.Records() {
+			if r.Trigger != string(trigger) || r.Status != state.AlertFiring || !cleared(r.Subject) {
+				continue
+			}
+			r.Status = state.AlertResolved
+			r.ResolvedAt = now
+			changed = true
+			if !r.LastNotified.IsZero() && st.Silenced(r.Trigger, r.Subject, now) == nil {
+				resolved = append(resolved, r.Subject)
+				severity = r.Severity
+			}
+		}
+		return changed
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to update alert state: %v", err)
+	}
+	if len(resolved) == 0 {
+		return "", nil
+	}
+	t.logger.WithFields(logging.Fields{"type": trigger, "subjects": strings.Join(resolved, ",")}).Info("Alert condition cleared")
+	if !t.resolve {
+		return "", nil
+	}
+	return t.all.Notify(alerts.NewResolvedEvent(trigger, severity, resolved))
+}
+
+func ResolveAlerts(notifier alerts.Notifier, trigger alerts.EventType, active []string, logger *logging.Logger) {
+	tracker, ok := notifier.(*AlertTracker)
+	if !ok {
+		return
+	}
+	response, err := tracker.Resolve(trigger, active)
+	logResolve(tracker, trigger, response, err, logger)
+}
+
+func ResolveAlertSubjects(notifier alerts.Notifier, trigger alerts.EventType, subjects []string, logger *logging.Logger) {
+	tracker, ok := notifier.(*AlertTracker)
+	if !ok || len(subjects) == 0 {
+		return
+	}
+	response, err := tracker.ResolveSubjects(trigger, subjects)
+	logResolve(tracker, trigger, response, err, logger)
+}
+
+func logResolve(tracker *AlertTracker, trigger alerts.EventType, response string, err error, logger *logging.Logger) {
+	if err != nil {
+		logger.WithFields(logging.Fields{"type": trigger, "error": err}).Error("Failed to resolve alerts")
+		return
+	}
+	if response != "" {
+		logger.WithFields(logging.Fields{"type": trigger, "notifiers": tracker.Name(), "response": response}).Info("Resolve notification sent")
+	}
+}
+
+func SucceededTaskIndices(tasks []SnapshotTask, successful []string) []string {
+	done := make(map[string]bool, len(successful))
+	for _, name := range successful {
+		done[name] = true
+	}
+	var indices []string
+	for _, task := range tasks {
+		if done[task.SnapshotName] {
+			indices = append(indices, strings.Split(task.IndicesStr, ",")...)
+		}
+	}
+	return indices
+}
@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"osctl/pkg/audit"
+	"osctl/pkg/config"
+	"osctl/pkg/logging"
+)
+
+func ConfigureAudit(cfg *config.Config, logger *logging.Logger) {
+	var sinks []audit.Sink
+	if path := cfg.GetAuditFile(); path != "" {
+		sinks = append(sinks, audit.NewFileSink(path))
+	}
+	if index := cfg.GetAuditIndex(); index != "" {
+		client, err := NewOSClientWithURL(cfg, cfg.GetOpenSearchURL())
+		if err != nil {
+			logger.WithFields(logging.Fields{"index": index, "error": err}).Warn("Audit index disabled, failed to create OpenSearch client")
+		} else {
+			sinks = append(sinks, audit.NewIndexSink(client, index))
+		}
+	}
+	pod, _ := os.Hostname()
+	audit.Configure(fmt.Sprintf("osctl/%s@%s", OsctlVersion, pod), ClusterName(cfg), sinks...)
+	audit.OnError(func(err error) {
+		logger.WithField("error", err).Error("Failed to write audit record")
+	})
+}
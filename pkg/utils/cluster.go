@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"osctl/pkg/logging"
+	"osctl/pkg/metrics"
 	"osctl/pkg/opensearch"
 	"regexp"
 	"strconv"
@@ -71,6 +72,7 @@ func GetAverageUtilization(client *opensearch.Client, logger *logging.Logger, sh
 	}
 
 	avgUtil := int(sum / float64(count))
+	metrics.DiskUtilization.Set(sum / float64(count))
 	if showDetails {
 		logger.Info(fmt.Sprintf("Average disk utilization calculated from %d data nodes: %d%%", count, avgUtil))
 	}
@@ -114,7 +116,7 @@ func CheckNodesDown(client *opensearch.Client, logger *logging.Logger, checkEnab
 
 	if showDetails {
 		logger.Info(fmt.Sprintf("Nodes in cluster: %d", nodeCount))
-		logger.Info(fmt.Sprintf("StatefulSet base names found: %s", strings.Join(stsBaseNames, ", ")))
+		logger.WithField("statefulSets", strings.Join(stsBaseNames, ", ")).Info("StatefulSet base names found")
 	}
 
 	rc, err := rest.InClusterConfig()
@@ -149,7 +151,7 @@ func CheckNodesDown(client *opensearch.Client, logger *logging.Logger, checkEnab
 
 	if showDetails {
 		if len(matchingSts) > 0 {
-			logger.Info(fmt.Sprintf("StatefulSets found: %s", strings.Join(matchingSts, ", ")))
+			logger.WithField("statefulSets", strings.Join(matchingSts, ", ")).Info("StatefulSets found")
 			logger.Info(fmt.Sprintf("Total replicas in StatefulSets: %d", totalReplicas))
 		} else {
 			logger.Warn("No matching StatefulSets found for node base names")
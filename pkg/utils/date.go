@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"osctl/pkg/config"
 	"regexp"
 	"strings"
 	"time"
@@ -12,10 +13,7 @@ func FormatDate(t time.Time, dateFormat string) string {
 }
 
 func ConvertDateFormat(dateFormat string) string {
-	goFormat := strings.ReplaceAll(dateFormat, "%Y", "2006")
-	goFormat = strings.ReplaceAll(goFormat, "%m", "01")
-	goFormat = strings.ReplaceAll(goFormat, "%d", "02")
-	return goFormat
+	return config.ConvertDateFormat(dateFormat)
 }
 
 func ConvertDateFormatToRegex(dateFormat string) string {
@@ -62,6 +60,32 @@ func IsOlderThanCutoff(name, cutoffDate, dateFormat string) bool {
 	return itemTime.Before(cutoffTime) || itemTime.Equal(cutoffTime)
 }
 
+func ExtractLastDateFromIndex(index, dateFormat string) string {
+	re := regexp.MustCompile(ConvertDateFormatToRegex(dateFormat))
+	matches := re.FindAllString(index, -1)
+	if len(matches) == 0 {
+		return ""
+	}
+	return matches[len(matches)-1]
+}
+
+func IsLastDateOlderThanCutoff(name, cutoffDate, dateFormat string) bool {
+	lastDate := ExtractLastDateFromIndex(name, dateFormat)
+	if lastDate == "" {
+		return false
+	}
+	goFormat := ConvertDateFormat(dateFormat)
+	cutoffTime, err := time.Parse(goFormat, cutoffDate)
+	if err != nil {
+		return false
+	}
+	itemTime, err := time.Parse(goFormat, lastDate)
+	if err != nil {
+		return false
+	}
+	return !itemTime.After(cutoffTime)
+}
+
 func GetYesterdayFormatted(dateFormat string) string {
 	yesterday := time.Now().AddDate(0, 0, -1)
 	return FormatDate(yesterday, dateFormat)
@@ -107,3 +131,10 @@ func GroupIndicesByDate(indices []string, dateFormat string) map[string][]string
 	}
 	return groups
 }
+
+func IndexPrefixForDate(index, dateStr string) string {
+	if pos := strings.LastIndex(index, dateStr); pos >= 0 {
+		return strings.TrimSuffix(index[:pos], "-")
+	}
+	return index
+}
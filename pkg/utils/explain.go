@@ -0,0 +1,319 @@
+package utils
+
+import (
+	"fmt"
+	"osctl/pkg/config"
+	"osctl/pkg/opensearch"
+	"strings"
+	"time"
+)
+
+type RuleMatch struct {
+	Rule config.IndexConfig `json:"rule"`
+	Line int                `json:"line,omitempty"`
+}
+
+type ExplainEvent struct {
+	Event string `json:"event"`
+	Date  string `json:"date"`
+	Note  string `json:"note,omitempty"`
+}
+
+type ExplainAction struct {
+	Action   string `json:"action"`
+	Decision string `json:"decision"`
+}
+
+type Explanation struct {
+	Name       string          `json:"name"`
+	Type       string          `json:"type"`
+	Found      bool            `json:"found"`
+	Repository string          `json:"repository,omitempty"`
+	Date       string          `json:"date,omitempty"`
+	Matched    *RuleMatch      `json:"matched,omitempty"`
+	Unknown    bool            `json:"unknown"`
+	Shadowed   []RuleMatch     `json:"shadowed"`
+	Notes      []string        `json:"notes"`
+	Events     []ExplainEvent  `json:"events"`
+	Actions    []ExplainAction `json:"actions"`
+}
+
+type IndexFacts struct {
+	Found           bool
+	Replicas        string
+	ColdRequirement string
+	ValidSnapshot   map[string]bool
+}
+
+func (e *Explanation) event(name string, date time.Time, goFormat, note string) {
+	e.Events = append(e.Events, ExplainEvent{Event: name, Date: date.Format(goFormat), Note: note})
+}
+
+func (e *Explanation) action(name, format string, args ...any) {
+	e.Actions = append(e.Actions, ExplainAction{Action: name, Decision: fmt.Sprintf(format, args...)})
+}
+
+func matchRules(name string, rules []config.IndexConfig, path string, match func(string, config.IndexConfig) bool) (*RuleMatch, []RuleMatch) {
+	var matched *RuleMatch
+	shadowed := []RuleMatch{}
+	for _, ic := range rules {
+		if !match(name, ic) {
+			continue
+		}
+		m := RuleMatch{Rule: ic, Line: config.FindIndexConfigLine(path, ic)}
+		if matched == nil {
+			matched = &m
+			continue
+		}
+		shadowed = append(shadowed, m)
+	}
+	return matched, shadowed
+}
+
+func s3RetentionDays(ic config.IndexConfig, s3Config config.S3SnapshotsConfig) int {
+	if ic.SnapshotCountS3 > 0 {
+		return ic.SnapshotCountS3
+	}
+	return s3Config.UnitCount.All
+}
+
+func ExplainIndex(cfg *config.Config, name string, facts IndexFacts) (*Explanation, error) {
+	rules, err := cfg.GetOsctlIndices()
+	if err != nil {
+		return nil, err
+	}
+	unknown := cfg.GetOsctlIndicesUnknownConfig()
+	s3Config := cfg.GetOsctlIndicesS3SnapshotsConfig()
+	dateFormat := cfg.GetDateFormat()
+	goFormat := ConvertDateFormat(dateFormat)
+	now := time.Now()
+	older := func(days int) bool {
+		return IsOlderThanCutoff(name, FormatDate(now.AddDate(0, 0, -days), dateFormat), dateFormat)
+	}
+
+	e := &Explanation{Name: name, Type: "index", Found: facts.Found, Notes: []string{}, Events: []ExplainEvent{}, Actions: []ExplainAction{}}
+	e.Matched, e.Shadowed = matchRules(name, rules, cfg.GetOSCTLIndicesConfig(), MatchesIndex)
+	e.Notes = append(e.Notes, "rules are checked prefix first, then regex, each group sorted by value; the first match wins")
+	if !facts.Found {
+		e.Notes = append(e.Notes, "index not found in the cluster, decisions assume it exists")
+	}
+	system := strings.HasPrefix(name, ".")
+	if system {
+		e.Notes = append(e.Notes, "system index: only rules with system: true or a value starting with '.' match; indicesdelete, retention and dereplicator skip it")
+	} else if strings.HasPrefix(name, "extracted_") {
+		e.Notes = append(e.Notes, "extracted_ indices never match rules and are not treated as unknown")
+	}
+	if pattern := cfg.GetExtractedPattern(); pattern != "" && strings.HasPrefix(name, pattern) {
+		e.Notes = append(e.Notes, fmt.Sprintf("matches extracted_pattern %q: extracteddelete removes it on the recoverer cluster %d days after the last date in its name", pattern, cfg.GetExtractedDays()))
+	}
+
+	e.Date = ExtractDateFromIndex(name, dateFormat)
+	date, dateErr := time.Parse(goFormat, e.Date)
+	if e.Date == "" || dateErr != nil {
+		e.Notes = append(e.Notes, fmt.Sprintf("no date in name (date_format %s): date-based actions skip it", dateFormat))
+	}
+	hasDate := e.Date != "" && dateErr == nil
+	e.Unknown = e.Matched == nil && hasDate && !ShouldSkipIndex(name)
+
+	var rule config.IndexConfig
+	daysCount, s3Days, snapshot, manual := 0, 0, false, false
+	repo := cfg.GetSnapshotRepo()
+	switch {
+	case e.Matched != nil:
+		rule = e.Matched.Rule
+		daysCount, s3Days, snapshot, manual = rule.DaysCount, s3RetentionDays(rule, s3Config), rule.Snapshot, rule.ManualSnapshot
+		repo = SourceRepository(repo, rule)
+	case e.Unknown:
+		daysCount, s3Days, snapshot, manual = unknown.DaysCount, s3Config.UnitCount.Unknown, unknown.Snapshot, unknown.ManualSnapshot
+	}
+
+	if hasDate {
+		if daysCount > 0 {
+			e.event("delete", date.AddDate(0, 0, daysCount), goFormat, fmt.Sprintf("indicesdelete, days_count=%d", daysCount))
+		}
+		if snapshot && !manual {
+			snapDate := date.AddDate(0, 0, 1)
+			snapName := "unknown-" + snapDate.Format(goFormat)
+			if e.Matched != nil {
+				snapName = BuildSnapshotNameFromConfig(rule, snapDate.Format(goFormat))
+			}
+			switch {
+			case cfg.IsFullPrefixSnapshots():
+				e.Notes = append(e.Notes, "full_prefix_snapshots: the whole prefix is snapshotted every day while the index exists")
+			case e.Matched != nil && IsSystem(rule, rule.Value):
+				e.Notes = append(e.Notes, "system rule: the index is snapshotted on every snapshots run")
+			default:
+				e.event("snapshot", snapDate, goFormat, fmt.Sprintf("snapshots, %s in %s", snapName, repo))
+				if s3Days > 0 {
+					e.event("snapshot expiry", snapDate.AddDate(0, 0, s3Days), goFormat, fmt.Sprintf("snapshotsdelete, snapshot_count_s3=%d", s3Days))
+				}
+				if secondaryDays := SecondaryRetentionDays(rule, s3Config); e.Matched != nil && rule.SecondaryRepository != "" && secondaryDays > 0 {
+					e.event("secondary copy expiry", snapDate.AddDate(0, 0, secondaryDays), goFormat, fmt.Sprintf("snapshotsdelete, %s, %d days", rule.SecondaryRepository, secondaryDays))
+				}
+			}
+		}
+		if !system {
+			e.event("dereplicate", date.AddDate(0, 0, cfg.GetDereplicatorDaysCount()), goFormat, fmt.Sprintf("dereplicator, dereplicator_days_count=%d", cfg.GetDereplicatorDaysCount()))
+		}
+		if cfg.GetColdAttribute() != "" {
+			e.event("cold", date.AddDate(0, 0, cfg.GetHotCount()), goFormat, fmt.Sprintf("coldstorage, hot_count=%d, temp=%s", cfg.GetHotCount(), cfg.GetColdAttribute()))
+		}
+		if !system {
+			e.event("retention candidate", date.AddDate(0, 0, cfg.GetRetentionDaysCount()), goFormat, fmt.Sprintf("retention, only while disk utilization > %.0f%%", cfg.GetRetentionThreshold()))
+		}
+	}
+
+	switch {
+	case system || strings.HasPrefix(name, "extracted_"):
+		e.action("indicesdelete", "skip: not managed by indicesdelete")
+	case !hasDate:
+		e.action("indicesdelete", "skip: no date in name")
+	case daysCount == 0:
+		e.action("indicesdelete", "keep: no days_count for this index")
+	case !older(daysCount):
+		e.action("indicesdelete", "keep until %s", date.AddDate(0, 0, daysCount).Format(goFormat))
+	case snapshot && s3Days > 0 && !older(s3Days) && cfg.GetIndicesDeleteCheckSnapshots():
+		checkRepo := cfg.GetSnapshotRepo()
+		valid, checked := facts.ValidSnapshot[checkRepo]
+		switch {
+		case !checked:
+			e.action("indicesdelete", "delete if a valid snapshot exists in %s", checkRepo)
+		case valid:
+			e.action("indicesdelete", "delete: valid snapshot exists in %s", checkRepo)
+		default:
+			e.action("indicesdelete", "keep: no valid snapshot in %s yet", checkRepo)
+		}
+	default:
+		e.action("indicesdelete", "delete")
+	}
+
+	yesterday := FormatDate(now.AddDate(0, 0, -1), dateFormat)
+	today := FormatDate(now, dateFormat)
+	switch {
+	case !snapshot:
+		e.action("snapshots", "skip: snapshot disabled")
+	case manual:
+		e.action("snapshots", "skip: manual_snapshot, use snapshot-manual")
+	case cfg.IsFullPrefixSnapshots() || (e.Matched != nil && IsSystem(rule, rule.Value)):
+		e.action("snapshots", "include in %s in %s", BuildSnapshotNameFromConfig(rule, today), repo)
+	case e.Date != yesterday:
+		e.action("snapshots", "skip: only indices dated %s are snapshotted today", yesterday)
+	case e.Matched == nil:
+		e.action("snapshots", "include in unknown-%s in %s", today, repo)
+	default:
+		e.action("snapshots", "include in %s in %s", BuildSnapshotNameFromConfig(rule, today), repo)
+	}
+
+	derepDays := cfg.GetDereplicatorDaysCount()
+	switch {
+	case system:
+		e.action("dereplicator", "skip: system index")
+	case facts.Found && facts.Replicas == "0":
+		e.action("dereplicator", "skip: already has 0 replicas")
+	case !hasDate || !older(derepDays):
+		if hasDate {
+			e.action("dereplicator", "keep replicas until %s", date.AddDate(0, 0, derepDays).Format(goFormat))
+		} else {
+			e.action("dereplicator", "skip: no date in name")
+		}
+	case cfg.GetDereplicatorUseSnapshot() && !facts.ValidSnapshot[cfg.GetSnapshotRepo()]:
+		e.action("dereplicator", "skip unless a valid snapshot exists in %s", cfg.GetSnapshotRepo())
+	default:
+		e.action("dereplicator", "set replicas to 0")
+	}
+
+	hotCount := cfg.GetHotCount()
+	switch {
+	case cfg.GetColdAttribute() == "":
+		e.action("coldstorage", "skip: cold_attribute not set")
+	case !hasDate:
+		e.action("coldstorage", "skip: no date in name")
+	case !older(hotCount):
+		e.action("coldstorage", "keep hot until %s", date.AddDate(0, 0, hotCount).Format(goFormat))
+	case facts.ColdRequirement == cfg.GetColdAttribute():
+		e.action("coldstorage", "skip: already in cold")
+	default:
+		e.action("coldstorage", "move to cold (temp=%s, replicas 0)", cfg.GetColdAttribute())
+	}
+
+	retentionDays := cfg.GetRetentionDaysCount()
+	switch {
+	case ShouldSkipIndexRetention(name):
+		e.action("retention", "skip: system index")
+	case !hasDate:
+		e.action("retention", "skip: no date in name")
+	case !older(retentionDays):
+		e.action("retention", "protected until %s", date.AddDate(0, 0, retentionDays).Format(goFormat))
+	default:
+		e.action("retention", "delete only if disk utilization > %.0f%%, oldest indices first", cfg.GetRetentionThreshold())
+	}
+	return e, nil
+}
+
+func ExplainSnapshot(cfg *config.Config, snapshot opensearch.Snapshot, repo string) (*Explanation, error) {
+	rules, err := cfg.GetOsctlIndices()
+	if err != nil {
+		return nil, err
+	}
+	unknown := cfg.GetOsctlIndicesUnknownConfig()
+	s3Config := cfg.GetOsctlIndicesS3SnapshotsConfig()
+	dateFormat := cfg.GetDateFormat()
+	goFormat := ConvertDateFormat(dateFormat)
+	name := snapshot.Snapshot
+
+	e := &Explanation{Name: name, Type: "snapshot", Found: true, Repository: repo, Notes: []string{}, Events: []ExplainEvent{}, Actions: []ExplainAction{}}
+	snapshotRules := make([]config.IndexConfig, 0, len(rules))
+	for _, ic := range rules {
+		if ic.Snapshot {
+			snapshotRules = append(snapshotRules, ic)
+		}
+	}
+	e.Matched, e.Shadowed = matchRules(name, snapshotRules, cfg.GetOSCTLIndicesConfig(), MatchesSnapshot)
+	if ic := FindSnapshotConfig(snapshot, rules); ic != nil {
+		if e.Matched == nil || e.Matched.Rule != *ic {
+			e.Notes = append(e.Notes, "rule taken from snapshot metadata, not from the name")
+			e.Matched = &RuleMatch{Rule: *ic, Line: config.FindIndexConfigLine(cfg.GetOSCTLIndicesConfig(), *ic)}
+		}
+	}
+	e.Notes = append(e.Notes, fmt.Sprintf("state %s, %d indices", snapshot.State, len(snapshot.Indices)))
+	isCopy := IsSnapshotCopy(name)
+	if isCopy {
+		e.Notes = append(e.Notes, "secondary copy: retention follows secondary_snapshot_count of the rule, then snapshot_count_s3, then s3_snapshots.unit_count.all")
+	}
+
+	e.Date = ExtractDateFromIndex(name, dateFormat)
+	date, dateErr := time.Parse(goFormat, e.Date)
+	hasDate := e.Date != "" && dateErr == nil
+	e.Unknown = e.Matched == nil && hasDate
+
+	days := 0
+	switch {
+	case e.Matched != nil && (isCopy || repo == e.Matched.Rule.SecondaryRepository):
+		days = SecondaryRetentionDays(e.Matched.Rule, s3Config)
+	case e.Matched != nil:
+		days = s3RetentionDays(e.Matched.Rule, s3Config)
+	case e.Unknown && unknown.Snapshot:
+		days = s3Config.UnitCount.Unknown
+	}
+	if hasDate && days > 0 {
+		e.event("snapshot expiry", date.AddDate(0, 0, days), goFormat, fmt.Sprintf("snapshotsdelete, %d days", days))
+	}
+
+	switch {
+	case !hasDate:
+		e.action("snapshotsdelete", "keep: no date in name, dangling snapshot")
+	case e.Matched == nil && !unknown.Snapshot:
+		e.action("snapshotsdelete", "keep: no rule and unknown.snapshot is false")
+	case e.Matched != nil && !isCopy && repo != e.Matched.Rule.SecondaryRepository && repo != SourceRepository(cfg.GetSnapshotRepo(), e.Matched.Rule):
+		e.action("snapshotsdelete", "keep: dangling, the rule writes to %s", SourceRepository(cfg.GetSnapshotRepo(), e.Matched.Rule))
+	case days == 0:
+		e.action("snapshotsdelete", "keep: no retention days configured")
+	case snapshot.State == "IN_PROGRESS":
+		e.action("snapshotsdelete", "skip: in progress")
+	case IsOlderThanCutoff(name, FormatDate(time.Now().AddDate(0, 0, -days), dateFormat), dateFormat):
+		e.action("snapshotsdelete", "delete")
+	default:
+		e.action("snapshotsdelete", "keep until %s", date.AddDate(0, 0, days).Format(goFormat))
+	}
+	return e, nil
+}
@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"fmt"
+	"osctl/pkg/logging"
+	"osctl/pkg/opensearch"
+	"strings"
+	"time"
+)
+
+func ParseExtractTime(value, date, dateFormat string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	day, err := time.Parse(ConvertDateFormat(dateFormat), date)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q for date_format %s: %v", date, dateFormat, err)
+	}
+	clock, err := time.Parse("15:04", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q: expected HH:MM or RFC3339", value)
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, time.UTC), nil
+}
+
+func ExtractQuery(timeField string, from, to time.Time, query string) map[string]any {
+	filter := []map[string]any{{
+		"range": map[string]any{
+			timeField: map[string]any{
+				"gte":    from.Format(time.RFC3339),
+				"lt":     to.Format(time.RFC3339),
+				"format": "strict_date_optional_time",
+			},
+		},
+	}}
+	if strings.TrimSpace(query) != "" {
+		filter = append(filter, map[string]any{
+			"query_string": map[string]any{"query": query},
+		})
+	}
+	return map[string]any{"bool": map[string]any{"filter": filter}}
+}
+
+func ExtractIndexName(extractedPattern, index string, from, to time.Time, restoreDate string) string {
+	return fmt.Sprintf("%s%s_%s-%s_%s", extractedPattern, index, from.Format("1504"), to.Format("1504"), restoreDate)
+}
+
+func WaitForReindexTask(client *opensearch.Client, taskID string, pollInterval, timeout time.Duration, logger *logging.Logger) (int64, error) {
+	if pollInterval <= 0 {
+		pollInterval = 10 * time.Second
+	}
+	start := time.Now()
+	for {
+		status, err := client.GetTask(taskID)
+		if err != nil {
+			logger.WithFields(logging.Fields{"task": taskID, "error": err}).Warn("Failed to poll reindex task")
+		} else if status.Completed {
+			if len(status.Error) > 0 {
+				return status.Response.Created, fmt.Errorf("reindex task %s failed: %v", taskID, status.Error["reason"])
+			}
+			if len(status.Response.Failures) > 0 {
+				return status.Response.Created, fmt.Errorf("reindex task %s finished with %d failures", taskID, len(status.Response.Failures))
+			}
+			return status.Response.Created, nil
+		} else {
+			logger.WithFields(logging.Fields{"task": taskID, "created": status.Task.Status.Created, "total": status.Task.Status.Total, "elapsed": formatDuration(time.Since(start))}).Info("Reindex in progress")
+		}
+		if timeout > 0 && time.Since(start) >= timeout {
+			return 0, fmt.Errorf("reindex task %s did not finish within %s", taskID, formatDuration(timeout))
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+func CancelReindexTask(client *opensearch.Client, taskID string, pollInterval, timeout time.Duration, logger *logging.Logger) error {
+	if err := client.CancelTask(taskID); err != nil {
+		return fmt.Errorf("failed to cancel reindex task %s: %v", taskID, err)
+	}
+	logger.WithField("task", taskID).Warn("Reindex task cancelled, waiting for it to stop")
+	start := time.Now()
+	for {
+		status, err := client.GetTask(taskID)
+		if err != nil {
+			logger.WithFields(logging.Fields{"task": taskID, "error": err}).Warn("Failed to poll reindex task")
+		} else if status.Completed {
+			return nil
+		}
+		if time.Since(start) >= timeout {
+			return fmt.Errorf("reindex task %s did not stop within %s after cancel", taskID, formatDuration(timeout))
+		}
+		time.Sleep(pollInterval)
+	}
+}
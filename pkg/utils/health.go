@@ -0,0 +1,136 @@
+package utils
+
+import (
+	"osctl/pkg/opensearch"
+	"sort"
+)
+
+const (
+	AllocationReasonDiskWatermark    = "disk watermark"
+	AllocationReasonFilter           = "allocation filter"
+	AllocationReasonAwareness        = "allocation awareness"
+	AllocationReasonSameShard        = "not enough nodes for replicas"
+	AllocationReasonMaxRetry         = "max retries exceeded"
+	AllocationReasonShardsLimit      = "total shards per node limit"
+	AllocationReasonDisabled         = "allocation disabled"
+	AllocationReasonThrottled        = "throttled"
+	AllocationReasonNodeLeft         = "node left"
+	AllocationReasonDelayed          = "delayed allocation"
+	AllocationReasonNoValidCopy      = "no valid shard copy"
+	AllocationReasonRestore          = "restore"
+	AllocationReasonAllocationFailed = "allocation failed"
+	AllocationReasonUnknown          = "unknown"
+)
+
+var allocationDeciderReasons = map[string]string{
+	"disk_threshold":    AllocationReasonDiskWatermark,
+	"filter":            AllocationReasonFilter,
+	"awareness":         AllocationReasonAwareness,
+	"same_shard":        AllocationReasonSameShard,
+	"max_retry":         AllocationReasonMaxRetry,
+	"shards_limit":      AllocationReasonShardsLimit,
+	"enable":            AllocationReasonDisabled,
+	"cluster_rebalance": AllocationReasonDisabled,
+	"throttling":        AllocationReasonThrottled,
+}
+
+var unassignedInfoReasons = map[string]string{
+	"NODE_LEFT":               AllocationReasonNodeLeft,
+	"ALLOCATION_FAILED":       AllocationReasonAllocationFailed,
+	"NEW_INDEX_RESTORED":      AllocationReasonRestore,
+	"EXISTING_INDEX_RESTORED": AllocationReasonRestore,
+}
+
+var allocationReasonAdvice = map[string]map[string]string{
+	"ru": {
+		AllocationReasonDiskWatermark:    "освободите место на дисках (retention, удаление старых индексов) или поднимите cluster.routing.allocation.disk.watermark",
+		AllocationReasonFilter:           "проверьте настройки index.routing.allocation.* индекса и атрибуты нод (например temp=hot/cold)",
+		AllocationReasonAwareness:        "проверьте cluster.routing.allocation.awareness и наличие нод в каждой зоне",
+		AllocationReasonSameShard:        "добавьте data-ноды или уменьшите number_of_replicas",
+		AllocationReasonMaxRetry:         "устраните причину и выполните POST _cluster/reroute?retry_failed=true (--reroute-failed)",
+		AllocationReasonShardsLimit:      "поднимите index.routing.allocation.total_shards_per_node или добавьте ноды",
+		AllocationReasonDisabled:         "проверьте cluster.routing.allocation.enable",
+		AllocationReasonThrottled:        "аллокация идёт, дождитесь завершения recovery",
+		AllocationReasonNodeLeft:         "проверьте, что все data-ноды запущены",
+		AllocationReasonDelayed:          "нода недавно вышла, аллокация отложена по index.unassigned.node_left.delayed_timeout",
+		AllocationReasonNoValidCopy:      "данных шарда нет на кластере: восстановите индекс из снапшота или удалите его",
+		AllocationReasonRestore:          "рестор из снапшота не завершился, проверьте джобу восстановления",
+		AllocationReasonAllocationFailed: "проверьте логи нод на ошибки шардов и повторите аллокацию через --reroute-failed",
+		AllocationReasonUnknown:          "проверьте GET _cluster/allocation/explain вручную",
+	},
+	"en": {
+		AllocationReasonDiskWatermark:    "free disk space (retention, deleting old indices) or raise cluster.routing.allocation.disk.watermark",
+		AllocationReasonFilter:           "check the index.routing.allocation.* settings of the index and node attributes (e.g. temp=hot/cold)",
+		AllocationReasonAwareness:        "check cluster.routing.allocation.awareness and that every zone has nodes",
+		AllocationReasonSameShard:        "add data nodes or reduce number_of_replicas",
+		AllocationReasonMaxRetry:         "fix the cause and run POST _cluster/reroute?retry_failed=true (--reroute-failed)",
+		AllocationReasonShardsLimit:      "raise index.routing.allocation.total_shards_per_node or add nodes",
+		AllocationReasonDisabled:         "check cluster.routing.allocation.enable",
+		AllocationReasonThrottled:        "allocation is in progress, wait for recovery to finish",
+		AllocationReasonNodeLeft:         "check that all data nodes are running",
+		AllocationReasonDelayed:          "a node left recently, allocation is delayed by index.unassigned.node_left.delayed_timeout",
+		AllocationReasonNoValidCopy:      "the shard data is not on the cluster: restore the index from a snapshot or delete it",
+		AllocationReasonRestore:          "the snapshot restore did not finish, check the restore job",
+		AllocationReasonAllocationFailed: "check node logs for shard errors and retry allocation with --reroute-failed",
+		AllocationReasonUnknown:          "check GET _cluster/allocation/explain manually",
+	},
+}
+
+func AllocationReasonAdvice(reason, language string) string {
+	advice, ok := allocationReasonAdvice[language]
+	if !ok {
+		advice = allocationReasonAdvice["ru"]
+	}
+	if text, ok := advice[reason]; ok {
+		return text
+	}
+	return advice[AllocationReasonUnknown]
+}
+
+func ClassifyAllocation(exp *opensearch.AllocationExplanation) string {
+	switch exp.CanAllocate {
+	case "no_valid_shard_copy":
+		return AllocationReasonNoValidCopy
+	case "allocation_delayed":
+		return AllocationReasonDelayed
+	case "throttled":
+		return AllocationReasonThrottled
+	}
+
+	counts := make(map[string]int)
+	for _, node := range exp.NodeAllocationDecisions {
+		for _, d := range node.Deciders {
+			if d.Decision != "NO" {
+				continue
+			}
+			if reason, ok := allocationDeciderReasons[d.Decider]; ok {
+				counts[reason]++
+			}
+		}
+	}
+	if counts[AllocationReasonMaxRetry] > 0 {
+		return AllocationReasonMaxRetry
+	}
+	if len(counts) > 0 {
+		reasons := make([]string, 0, len(counts))
+		for r := range counts {
+			reasons = append(reasons, r)
+		}
+		sort.Slice(reasons, func(i, j int) bool {
+			if counts[reasons[i]] != counts[reasons[j]] {
+				return counts[reasons[i]] > counts[reasons[j]]
+			}
+			return reasons[i] < reasons[j]
+		})
+		return reasons[0]
+	}
+
+	return ClassifyUnassignedReason(exp.UnassignedInfo.Reason)
+}
+
+func ClassifyUnassignedReason(reason string) string {
+	if r, ok := unassignedInfoReasons[reason]; ok {
+		return r
+	}
+	return AllocationReasonUnknown
+}
@@ -1,12 +1,15 @@
 package utils
 
 import (
-	"fmt"
 	"osctl/pkg/config"
 	"osctl/pkg/logging"
+	"osctl/pkg/metrics"
 	"osctl/pkg/opensearch"
+	"osctl/pkg/report"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 )
 
 func IndexInfosToNames(list []opensearch.IndexInfo) []string {
@@ -110,7 +113,10 @@ func BatchDeleteIndices(client *opensearch.Client, indices []string, dryRun bool
 	var failed []string
 
 	if dryRun {
-		logger.Info(fmt.Sprintf("Dry run: would delete indices count=%d", len(indices)))
+		logger.WithField("count", len(indices)).Info("Dry run: would delete indices")
+		for _, index := range indices {
+			report.Skipped(index, "dry run")
+		}
 		return nil, nil, nil
 	}
 
@@ -121,16 +127,23 @@ func BatchDeleteIndices(client *opensearch.Client, indices []string, dryRun bool
 		}
 
 		batch := indices[i:end]
-		logger.Info(fmt.Sprintf("Deleting indices batch batch=%d indices=%v", i/batchSize+1, batch))
+		logger.WithFields(logging.Fields{"batch": i/batchSize + 1, "indices": batch}).Info("Deleting indices batch")
 
+		start := time.Now()
 		err := client.DeleteIndices(batch)
 		if err != nil {
-			logger.Error(fmt.Sprintf("Failed to delete indices batch indices=%v error=%v", batch, err))
+			logger.WithFields(logging.Fields{"indices": batch, "error": err}).Error("Failed to delete indices batch")
 			failed = append(failed, batch...)
+			for _, index := range batch {
+				report.Failed(index, err)
+			}
 			continue
 		}
-		logger.Info(fmt.Sprintf("Indices batch deleted successfully indices=%v", batch))
+		logger.WithField("indices", batch).Info("Indices batch deleted successfully")
 		successful = append(successful, batch...)
+		for _, index := range batch {
+			report.Succeeded(index).Took(time.Since(start))
+		}
 	}
 
 	return successful, failed, nil
@@ -139,3 +152,12 @@ func BatchDeleteIndices(client *opensearch.Client, indices []string, dryRun bool
 func NormalizeTenantName(name string) string {
 	return strings.ReplaceAll(name, "-", "")
 }
+
+func RecordDeletedIndices(indices []string, sizes map[string]string) {
+	for _, index := range indices {
+		metrics.IndicesDeleted.Inc()
+		if size, err := strconv.ParseFloat(sizes[index], 64); err == nil {
+			metrics.BytesFreed.Add(size)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package utils
+
+import (
+	"osctl/pkg/opensearch"
+	"sort"
+	"time"
+)
+
+type InventorySnapshot struct {
+	Snapshot         string  `json:"snapshot"`
+	UUID             string  `json:"uuid,omitempty"`
+	Date             string  `json:"date"`
+	State            string  `json:"state"`
+	Indices          int     `json:"indices"`
+	TotalBytes       int64   `json:"total_bytes"`
+	IncrementalBytes int64   `json:"incremental_bytes"`
+	StartTimeMillis  int64   `json:"start_time_in_millis"`
+	DurationMillis   int64   `json:"duration_in_millis"`
+	MBps             float64 `json:"mb_per_sec"`
+}
+
+type PrefixInventory struct {
+	Repo               string              `json:"repository"`
+	Prefix             string              `json:"prefix"`
+	FirstDate          string              `json:"first_date"`
+	LastDate           string              `json:"last_date"`
+	CoveredDates       []string            `json:"covered_dates"`
+	Gaps               []string            `json:"gaps"`
+	States             map[string]int      `json:"states"`
+	TotalBytes         int64               `json:"total_bytes"`
+	IncrementalBytes   int64               `json:"incremental_bytes"`
+	LastDurationMillis int64               `json:"last_duration_in_millis"`
+	AvgMBps            float64             `json:"avg_mb_per_sec"`
+	Snapshots          []InventorySnapshot `json:"snapshots"`
+}
+
+func SnapshotStatusSizes(client *opensearch.Client, repo, snapshot string) (int64, int64, error) {
+	detail, err := client.GetSnapshotStatusDetail(repo, snapshot)
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(detail.Snapshots) == 0 {
+		return 0, 0, nil
+	}
+	stats := detail.Snapshots[0].Stats
+	return stats.Total.SizeInBytes, stats.Incremental.SizeInBytes, nil
+}
+
+type InventoryTotals struct {
+	Snapshots        int   `json:"snapshots"`
+	TotalBytes       int64 `json:"total_bytes"`
+	IncrementalBytes int64 `json:"incremental_bytes"`
+}
+
+func SumInventory(report []PrefixInventory) InventoryTotals {
+	var totals InventoryTotals
+	seen := make(map[string]bool)
+	for _, p := range report {
+		for _, s := range p.Snapshots {
+			key := s.UUID
+			if key == "" {
+				key = p.Repo + "/" + s.Snapshot
+			}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			totals.Snapshots++
+			totals.TotalBytes += s.TotalBytes
+			totals.IncrementalBytes += s.IncrementalBytes
+		}
+	}
+	return totals
+}
+
+func BuildPrefixInventory(repo string, snapshots []InventorySnapshot, dateFormat string) []PrefixInventory {
+	byPrefix := make(map[string][]InventorySnapshot)
+	for _, s := range snapshots {
+		prefix := s.Snapshot
+		if s.Date != "" {
+			prefix = IndexPrefixForDate(s.Snapshot, s.Date)
+		}
+		byPrefix[prefix] = append(byPrefix[prefix], s)
+	}
+
+	prefixes := make([]string, 0, len(byPrefix))
+	for p := range byPrefix {
+		prefixes = append(prefixes, p)
+	}
+	sort.Strings(prefixes)
+
+	goFormat := ConvertDateFormat(dateFormat)
+	out := make([]PrefixInventory, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		snaps := byPrefix[prefix]
+		sort.Slice(snaps, func(i, j int) bool {
+			if snaps[i].Date != snaps[j].Date {
+				return dateBefore(snaps[i].Date, snaps[j].Date, goFormat)
+			}
+			return snaps[i].Snapshot < snaps[j].Snapshot
+		})
+		inv := PrefixInventory{Repo: repo, Prefix: prefix, States: make(map[string]int), Snapshots: snaps}
+		covered := make(map[string]bool)
+		for _, s := range snaps {
+			inv.States[s.State]++
+			inv.TotalBytes += s.TotalBytes
+			inv.IncrementalBytes += s.IncrementalBytes
+			if s.Date != "" && s.State == "SUCCESS" && !covered[s.Date] {
+				covered[s.Date] = true
+				inv.CoveredDates = append(inv.CoveredDates, s.Date)
+			}
+		}
+		if len(inv.CoveredDates) > 0 {
+			inv.FirstDate = inv.CoveredDates[0]
+			inv.LastDate = inv.CoveredDates[len(inv.CoveredDates)-1]
+			inv.Gaps = dateGaps(inv.FirstDate, inv.LastDate, covered, goFormat)
+		}
+		FillPrefixThroughput(&inv)
+		out = append(out, inv)
+	}
+	return out
+}
+
+func dateBefore(a, b, goFormat string) bool {
+	ta, errA := time.Parse(goFormat, a)
+	tb, errB := time.Parse(goFormat, b)
+	if errA != nil || errB != nil {
+		return a < b
+	}
+	return ta.Before(tb)
+}
+
+func dateGaps(first, last string, covered map[string]bool, goFormat string) []string {
+	start, err := time.Parse(goFormat, first)
+	if err != nil {
+		return nil
+	}
+	end, err := time.Parse(goFormat, last)
+	if err != nil {
+		return nil
+	}
+	var gaps []string
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		if s := d.Format(goFormat); !covered[s] {
+			gaps = append(gaps, s)
+		}
+	}
+	return gaps
+}
+
+func FormatSize(bytes int64) string {
+	return formatSize(bytes)
+}
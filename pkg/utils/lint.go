@@ -0,0 +1,320 @@
+package utils
+
+import (
+	"fmt"
+	"osctl/pkg/config"
+	"regexp"
+	"regexp/syntax"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	LintError   = "error"
+	LintWarning = "warning"
+	LintInfo    = "info"
+)
+
+type LintFinding struct {
+	Severity string `json:"severity"`
+	Check    string `json:"check"`
+	Rule     string `json:"rule,omitempty"`
+	Message  string `json:"message"`
+}
+
+type configLinter struct {
+	path     string
+	findings []LintFinding
+}
+
+func (l *configLinter) add(severity, check, rule, format string, args ...any) {
+	l.findings = append(l.findings, LintFinding{Severity: severity, Check: check, Rule: rule, Message: fmt.Sprintf(format, args...)})
+}
+
+func (l *configLinter) label(ic config.IndexConfig) string {
+	label := ic.Kind + " " + ic.Value
+	if ic.Kind == "regex" && ic.Name != "" {
+		label += " (" + ic.Name + ")"
+	}
+	if line := config.FindIndexConfigLine(l.path, ic); line > 0 {
+		label += fmt.Sprintf(" line %d", line)
+	}
+	return label
+}
+
+func LintIndicesConfig(oic *config.OsctlIndicesConfig, path, dateFormat, defaultRepo string, declaredRepos []string) []LintFinding {
+	l := &configLinter{path: path}
+	rules := append([]config.IndexConfig(nil), oic.Indices...)
+	config.SortIndexConfigs(rules)
+	today := FormatDate(time.Now(), dateFormat)
+
+	l.lintRegexes(rules, dateFormat, today)
+	l.lintShadowing(rules, today)
+	l.lintSnapshotNames(rules, oic.Unknown, defaultRepo, today)
+	l.lintRetention(rules, oic)
+	l.lintDeclaredRepositories(ReferencedRepositories(oic, defaultRepo), declaredRepos)
+	return l.findings
+}
+
+func (l *configLinter) lintRegexes(rules []config.IndexConfig, dateFormat, today string) {
+	dateRe := regexp.MustCompile(ConvertDateFormatToRegex(dateFormat))
+	for _, ic := range rules {
+		if ic.Kind != "prefix" && ic.Kind != "regex" {
+			l.add(LintError, "kind", l.label(ic), "kind must be prefix or regex, the rule never matches")
+			continue
+		}
+		if ic.Kind != "regex" {
+			continue
+		}
+		re, err := regexp.Compile(ic.Value)
+		if err != nil {
+			l.add(LintError, "regex", l.label(ic), "invalid regex, the rule never matches: %v", err)
+			continue
+		}
+		if samples := datedRegexSamples(ic.Value, today); !matchesDatedSample(re, dateRe, samples) {
+			l.add(LintError, "regex", l.label(ic), "regex matches none of %s for date_format %s, indices with dates never match", strings.Join(samples, ", "), dateFormat)
+		}
+		if ic.Name == "" {
+			l.add(LintError, "regex", l.label(ic), "regex rule has no name, snapshot names cannot be built")
+		}
+		if ic.System {
+			l.add(LintError, "regex", l.label(ic), "regex rules cannot be system: true")
+		}
+	}
+}
+
+func datedRegexSamples(pattern, today string) []string {
+	parsed, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil
+	}
+	base := regexSample(parsed)
+	samples := []string{base}
+	for _, sep := range []string{"", "-", "_", "."} {
+		samples = append(samples, base+sep+today)
+	}
+	return samples
+}
+
+func matchesDatedSample(re, dateRe *regexp.Regexp, samples []string) bool {
+	for _, s := range samples {
+		if re.MatchString(s) && dateRe.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+func regexSample(re *syntax.Regexp) string {
+	switch re.Op {
+	case syntax.OpLiteral:
+		return string(re.Rune)
+	case syntax.OpCharClass:
+		if len(re.Rune) > 0 {
+			return string(re.Rune[0])
+		}
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		return "a"
+	case syntax.OpCapture, syntax.OpPlus, syntax.OpAlternate:
+		return regexSample(re.Sub[0])
+	case syntax.OpRepeat:
+		return strings.Repeat(regexSample(re.Sub[0]), re.Min)
+	case syntax.OpConcat:
+		var b strings.Builder
+		for _, sub := range re.Sub {
+			b.WriteString(regexSample(sub))
+		}
+		return b.String()
+	}
+	return ""
+}
+
+func (l *configLinter) lintShadowing(rules []config.IndexConfig, today string) {
+	for _, ic := range rules {
+		switch ic.Kind {
+		case "prefix":
+			sample := ic.Value + "-" + today
+			first := FindMatchingIndexConfig(sample, rules)
+			if first != nil && *first != ic {
+				l.add(LintError, "shadowed", l.label(ic), "unreachable: every index starting with %q matches %s first (days_count %d instead of %d)", ic.Value, l.label(*first), first.DaysCount, ic.DaysCount)
+			}
+		case "regex":
+			pattern := strings.TrimPrefix(ic.Value, "^")
+			anchored := pattern != ic.Value
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				continue
+			}
+			literal, _ := re.LiteralPrefix()
+			for _, other := range rules {
+				if other.Kind != "prefix" || IsSystem(other, other.Value) {
+					continue
+				}
+				switch {
+				case anchored && strings.HasPrefix(literal, other.Value):
+					l.add(LintError, "shadowed", l.label(ic), "unreachable: every name it matches starts with %q and matches %s first", literal, l.label(other))
+				case strings.HasPrefix(literal, other.Value),
+					anchored && literal != "" && strings.HasPrefix(other.Value, literal),
+					!anchored && literal != "" && strings.Contains(other.Value, literal):
+					l.add(LintWarning, "overlap", l.label(ic), "may overlap with %s, indices matching both get the prefix rule", l.label(other))
+				}
+			}
+		}
+	}
+}
+
+func (l *configLinter) lintSnapshotNames(rules []config.IndexConfig, unknown config.UnknownConfig, defaultRepo, today string) {
+	owners := make(map[string][]config.IndexConfig)
+	var keys []string
+	for _, ic := range rules {
+		if !ic.Snapshot {
+			continue
+		}
+		key := SourceRepository(defaultRepo, ic) + "/" + BuildSnapshotNameFromConfig(ic, today)
+		if _, ok := owners[key]; !ok {
+			keys = append(keys, key)
+		}
+		owners[key] = append(owners[key], ic)
+
+		sample := BuildSnapshotNameFromConfig(ic, today)
+		first := FindMatchingSnapshotConfig(sample, rules)
+		switch {
+		case first == nil:
+			l.add(LintWarning, "snapshot-name", l.label(ic), "snapshot %s matches no rule by name, snapshotsdelete relies on snapshot metadata for its retention", sample)
+		case *first != ic:
+			l.add(LintWarning, "snapshot-name", l.label(ic), "snapshot %s is attributed to %s by name (snapshot_count_s3 %d instead of %d) unless snapshot metadata is present", sample, l.label(*first), first.SnapshotCountS3, ic.SnapshotCountS3)
+		}
+	}
+	for _, key := range keys {
+		if list := owners[key]; len(list) > 1 {
+			labels := make([]string, len(list))
+			for i, ic := range list {
+				labels[i] = l.label(ic)
+			}
+			l.add(LintError, "snapshot-collision", labels[0], "rules %s produce the same snapshot %s", strings.Join(labels, ", "), key)
+		}
+	}
+	if unknown.Snapshot {
+		key := defaultRepo + "/unknown-" + today
+		if list, ok := owners[key]; ok {
+			l.add(LintError, "snapshot-collision", l.label(list[0]), "snapshot %s collides with the snapshot of unknown indices", key)
+		}
+	}
+}
+
+func (l *configLinter) lintRetention(rules []config.IndexConfig, oic *config.OsctlIndicesConfig) {
+	for _, ic := range rules {
+		if ic.Snapshot && ic.SnapshotCountS3 > 0 && ic.SnapshotCountS3 < ic.DaysCount {
+			l.add(LintWarning, "snapshot-retention", l.label(ic), "snapshot_count_s3 %d is smaller than days_count %d, snapshots expire while the index still exists", ic.SnapshotCountS3, ic.DaysCount)
+		}
+	}
+	unknownDays := oic.S3Snapshots.UnitCount.Unknown
+	if unknownDays == 0 {
+		unknownDays = oic.S3Snapshots.UnitCount.All
+	}
+	if oic.Unknown.Snapshot && unknownDays > 0 && unknownDays < oic.Unknown.DaysCount {
+		l.add(LintWarning, "snapshot-retention", "unknown", "s3_snapshots.unit_count.unknown %d is smaller than unknown.days_count %d", unknownDays, oic.Unknown.DaysCount)
+	}
+}
+
+func (l *configLinter) lintDeclaredRepositories(referenced, declared []string) {
+	if len(declared) == 0 {
+		return
+	}
+	known := make(map[string]bool, len(declared))
+	for _, name := range declared {
+		known[name] = true
+	}
+	for _, repo := range referenced {
+		if !known[repo] {
+			l.add(LintWarning, "repository", "", "repository %s is referenced but not declared in repositories, osctl repositories will not create it", repo)
+		}
+	}
+}
+
+func ReferencedRepositories(oic *config.OsctlIndicesConfig, defaultRepo string) []string {
+	seen := make(map[string]bool)
+	add := func(repo string) {
+		if repo != "" {
+			seen[repo] = true
+		}
+	}
+	if oic.Unknown.Snapshot {
+		add(defaultRepo)
+	}
+	for _, ic := range oic.Indices {
+		if !ic.Snapshot {
+			continue
+		}
+		add(SourceRepository(defaultRepo, ic))
+		add(ic.SecondaryRepository)
+	}
+	repos := make([]string, 0, len(seen))
+	for repo := range seen {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+	return repos
+}
+
+func LintRegisteredRepositories(referenced []string, registered map[string]bool) []LintFinding {
+	var findings []LintFinding
+	for _, repo := range referenced {
+		if !registered[repo] {
+			findings = append(findings, LintFinding{Severity: LintError, Check: "repository", Message: fmt.Sprintf("repository %s is referenced but not registered in the cluster", repo)})
+		}
+	}
+	return findings
+}
+
+func LintLiveIndices(oic *config.OsctlIndicesConfig, path, dateFormat string, indices []string) []LintFinding {
+	l := &configLinter{path: path}
+	rules := append([]config.IndexConfig(nil), oic.Indices...)
+	config.SortIndexConfigs(rules)
+
+	unmatched := make(map[string][]string)
+	overlaps := make(map[string][]string)
+	var unmatchedKeys, overlapKeys []string
+	for _, index := range indices {
+		var matched []config.IndexConfig
+		for _, ic := range rules {
+			if MatchesIndex(index, ic) {
+				matched = append(matched, ic)
+			}
+		}
+		if len(matched) > 1 {
+			labels := make([]string, len(matched))
+			for i, ic := range matched {
+				labels[i] = l.label(ic)
+			}
+			key := strings.Join(labels, " | ")
+			if _, ok := overlaps[key]; !ok {
+				overlapKeys = append(overlapKeys, key)
+			}
+			overlaps[key] = append(overlaps[key], index)
+		}
+		date := ExtractDateFromIndex(index, dateFormat)
+		if len(matched) == 0 && date != "" && !ShouldSkipIndex(index) {
+			prefix := IndexPrefixForDate(index, date)
+			if _, ok := unmatched[prefix]; !ok {
+				unmatchedKeys = append(unmatchedKeys, prefix)
+			}
+			unmatched[prefix] = append(unmatched[prefix], index)
+		}
+	}
+	for _, key := range overlapKeys {
+		names := overlaps[key]
+		l.add(LintWarning, "live-overlap", strings.SplitN(key, " | ", 2)[0], "%d indices match %s, the first wins (e.g. %s)", len(names), key, names[0])
+	}
+	sort.Strings(unmatchedKeys)
+	for _, prefix := range unmatchedKeys {
+		names := unmatched[prefix]
+		policy := "kept forever (unknown.days_count not set)"
+		if oic.Unknown.DaysCount > 0 {
+			policy = fmt.Sprintf("unknown.days_count %d applies", oic.Unknown.DaysCount)
+		}
+		l.add(LintInfo, "unmatched", "", "%d indices %s-* match no rule, %s (e.g. %s)", len(names), prefix, policy, names[0])
+	}
+	return l.findings
+}
@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"fmt"
+	"osctl/pkg/opensearch"
+	"strconv"
+)
+
+func CountMappingFields(mappings map[string]any) (int, map[string]int) {
+	topLevel := make(map[string]int)
+	props, ok := mappings["properties"].(map[string]any)
+	if !ok {
+		props = make(map[string]any)
+		for _, v := range mappings {
+			typeMapping, ok := v.(map[string]any)
+			if !ok {
+				continue
+			}
+			if p, ok := typeMapping["properties"].(map[string]any); ok {
+				for name, field := range p {
+					props[name] = field
+				}
+			}
+		}
+	}
+	total := 0
+	for name, field := range props {
+		n := countField(field)
+		topLevel[name] = n
+		total += n
+	}
+	return total, topLevel
+}
+
+func countField(field any) int {
+	f, ok := field.(map[string]any)
+	if !ok {
+		return 1
+	}
+	count := 1
+	if props, ok := f["properties"].(map[string]any); ok {
+		for _, sub := range props {
+			count += countField(sub)
+		}
+	}
+	if multi, ok := f["fields"].(map[string]any); ok {
+		for _, sub := range multi {
+			count += countField(sub)
+		}
+	}
+	return count
+}
+
+func SetTemplateTotalFieldsLimit(client *opensearch.Client, templateName string, limit int) error {
+	tpl, err := client.GetIndexTemplate(templateName)
+	if err != nil {
+		return fmt.Errorf("failed to get template %s: %v", templateName, err)
+	}
+	if len(tpl.IndexTemplates) == 0 {
+		return fmt.Errorf("template %s not found", templateName)
+	}
+	it := tpl.IndexTemplates[0].IndexTemplate
+	template := it.Template
+	if template == nil {
+		template = map[string]any{}
+	}
+	settings := childMap(template, "settings")
+	index := childMap(settings, "index")
+	delete(index, "mapping.total_fields.limit")
+	totalFields := childMap(childMap(index, "mapping"), "total_fields")
+	totalFields["limit"] = strconv.Itoa(limit)
+
+	body := map[string]any{
+		"index_patterns": it.IndexPatterns,
+		"priority":       it.Priority,
+		"template":       template,
+	}
+	if len(it.ComposedOf) > 0 {
+		body["composed_of"] = it.ComposedOf
+	}
+	return client.PutIndexTemplate(templateName, body)
+}
+
+func childMap(parent map[string]any, key string) map[string]any {
+	if m, ok := parent[key].(map[string]any); ok {
+		return m
+	}
+	m := map[string]any{}
+	parent[key] = m
+	return m
+}
@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"osctl/pkg/config"
+	"osctl/pkg/opensearch"
+	"sort"
+	"strings"
+)
+
+const SnapshotMetadataCreatedBy = "osctl"
+
+var OsctlVersion = "dev"
+
+type SnapshotPolicy struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+	Name  string `json:"name,omitempty"`
+}
+
+type SnapshotMetadata struct {
+	CreatedBy    string         `json:"created_by"`
+	Version      string         `json:"osctl_version"`
+	Action       string         `json:"action"`
+	Policy       SnapshotPolicy `json:"policy"`
+	IndicesHash  string         `json:"indices_hash,omitempty"`
+	IndicesCount int            `json:"indices_count,omitempty"`
+	Pod          string         `json:"pod"`
+}
+
+func NewSnapshotMetadata(action, kind, value, name string) *SnapshotMetadata {
+	pod, _ := os.Hostname()
+	return &SnapshotMetadata{
+		CreatedBy: SnapshotMetadataCreatedBy,
+		Version:   OsctlVersion,
+		Action:    action,
+		Policy:    SnapshotPolicy{Kind: kind, Value: value, Name: name},
+		Pod:       pod,
+	}
+}
+
+func NewSnapshotMetadataFromConfig(action string, ic config.IndexConfig) *SnapshotMetadata {
+	return NewSnapshotMetadata(action, ic.Kind, ic.Value, ic.Name)
+}
+
+func (g SnapshotGroup) Metadata(action string) *SnapshotMetadata {
+	return NewSnapshotMetadata(action, g.Kind, g.Pattern, g.Name)
+}
+
+func (m *SnapshotMetadata) ForIndices(indices []string) *SnapshotMetadata {
+	if m == nil {
+		return nil
+	}
+	tagged := *m
+	tagged.IndicesHash = HashIndices(indices)
+	tagged.IndicesCount = len(indices)
+	return &tagged
+}
+
+func HashIndices(indices []string) string {
+	sorted := append([]string(nil), indices...)
+	sort.Strings(sorted)
+	sum := sha256.Sum256([]byte(strings.Join(sorted, ",")))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func ParseSnapshotMetadata(s opensearch.Snapshot) (*SnapshotMetadata, bool) {
+	if len(s.Metadata) == 0 {
+		return nil, false
+	}
+	raw, err := json.Marshal(s.Metadata)
+	if err != nil {
+		return nil, false
+	}
+	var m SnapshotMetadata
+	if err := json.Unmarshal(raw, &m); err != nil || m.CreatedBy != SnapshotMetadataCreatedBy {
+		return nil, false
+	}
+	return &m, true
+}
+
+func FindSnapshotConfig(s opensearch.Snapshot, indicesConfig []config.IndexConfig) *config.IndexConfig {
+	m, ok := ParseSnapshotMetadata(s)
+	if !ok {
+		return FindMatchingSnapshotConfig(s.Snapshot, indicesConfig)
+	}
+	for _, ic := range indicesConfig {
+		if ic.Snapshot && ic.Kind == m.Policy.Kind && ic.Value == m.Policy.Value && (ic.Kind != "regex" || ic.Name == m.Policy.Name) {
+			return &ic
+		}
+	}
+	return nil
+}
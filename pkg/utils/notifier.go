@@ -0,0 +1,110 @@
+package utils
+
+import (
+	"net/url"
+	"os"
+	"osctl/pkg/alerts"
+	"osctl/pkg/config"
+	"osctl/pkg/logging"
+	"osctl/pkg/opensearch"
+)
+
+func NewNotifier(cfg *config.Config) alerts.Notifier {
+	var notifiers, repeated alerts.Multi
+	for _, name := range cfg.GetAlertNotifiers() {
+		switch name {
+		case "madison":
+			if cfg.GetMadisonKey() != "" && cfg.GetOSDURL() != "" && cfg.GetMadisonURL() != "" {
+				notifiers = append(notifiers, alerts.NewMadisonNotifier(cfg.GetMadisonKey(), cfg.GetOSDURL(), cfg.GetMadisonURL()))
+			}
+		case "alertmanager":
+			repeated = append(repeated, alerts.NewAlertmanagerNotifier(cfg.GetAlertmanagerURL(), cfg.GetOSDURL()))
+		case "webhook":
+			if webhook, err := alerts.NewWebhookNotifier(cfg.GetAlertWebhookURL(), cfg.GetOSDURL(), cfg.GetAlertWebhookTemplate()); err == nil {
+				notifiers = append(notifiers, webhook)
+			}
+		case "slack":
+			notifiers = append(notifiers, alerts.NewSlackNotifier(cfg.GetSlackWebhookURL()))
+		}
+	}
+	if len(notifiers)+len(repeated) == 0 {
+		return nil
+	}
+	texts := alertTexts(cfg)
+	return withAlertState(cfg, withTexts(joinNotifiers(notifiers...), texts), withTexts(joinNotifiers(repeated...), texts))
+}
+
+func joinNotifiers(notifiers ...alerts.Notifier) alerts.Notifier {
+	var joined alerts.Multi
+	for _, n := range notifiers {
+		if n != nil {
+			joined = append(joined, n)
+		}
+	}
+	switch len(joined) {
+	case 0:
+		return nil
+	case 1:
+		return joined[0]
+	}
+	return joined
+}
+
+func ClusterName(cfg *config.Config) string {
+	if name := cfg.GetAlertClusterName(); name != "" {
+		return name
+	}
+	if u, err := url.Parse(cfg.GetOpenSearchURL()); err == nil {
+		return u.Hostname()
+	}
+	return ""
+}
+
+func withTexts(notifier alerts.Notifier, texts *alerts.Texts) alerts.Notifier {
+	if notifier == nil {
+		return nil
+	}
+	return alerts.WithTexts(notifier, texts)
+}
+
+func alertTexts(cfg *config.Config) *alerts.Texts {
+	cluster := ClusterName(cfg)
+	pod, _ := os.Hostname()
+	opts := alerts.TextOptions{
+		Language:     cfg.GetAlertLanguage(),
+		OverridesDir: cfg.GetAlertTemplatesDir(),
+		RunLink:      cfg.GetAlertRunLink(),
+		Cluster:      cluster,
+		Kibana:       cfg.GetOSDURL(),
+		Namespace:    cfg.GetKubeNamespace(),
+		Pod:          pod,
+	}
+	texts, err := alerts.NewTexts(opts)
+	if err != nil {
+		logging.NewLogger().WithFields(logging.Fields{"language": opts.Language, "error": err}).Warn("Failed to load alert templates, using built-in texts")
+		opts.OverridesDir, opts.RunLink = "", ""
+		if texts, err = alerts.NewTexts(opts); err != nil {
+			opts.Language = "ru"
+			texts, _ = alerts.NewTexts(opts)
+		}
+	}
+	return texts
+}
+
+func withAlertState(cfg *config.Config, notifier, repeated alerts.Notifier) alerts.Notifier {
+	logger := logging.NewLogger()
+	var client *opensearch.Client
+	if cfg.GetAlertStateStore() == "index" {
+		var err error
+		client, err = NewOSClientWithURL(cfg, cfg.GetOpenSearchURL())
+		if err != nil {
+			logger.WithField("error", err).Warn("Alert state disabled, failed to create OpenSearch client")
+			return joinNotifiers(notifier, repeated)
+		}
+	}
+	store := NewAlertStore(cfg, client)
+	if store == nil {
+		return joinNotifiers(notifier, repeated)
+	}
+	return NewAlertTracker(notifier, repeated, store, cfg.GetAlertDedupWindow(), cfg.GetAlertResolve(), logger)
+}
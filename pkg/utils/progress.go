@@ -0,0 +1,208 @@
+package utils
+
+import (
+	"os"
+	"osctl/pkg/config"
+	"osctl/pkg/logging"
+	"osctl/pkg/opensearch"
+	"osctl/pkg/state"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func NewStateStore(cfg *config.Config, client *opensearch.Client) state.Store {
+	switch cfg.GetStateStore() {
+	case "index":
+		return state.NewIndexStore(client, cfg.GetStateIndex())
+	case "file":
+		return state.NewFileStore(cfg.GetStateFile())
+	}
+	return nil
+}
+
+type SnapshotProgress struct {
+	store  state.Store
+	run    *state.Run
+	logger *logging.Logger
+	mu     sync.Mutex
+	warned bool
+}
+
+func StartSnapshotProgress(store state.Store, action, mode, repo, date string, logger *logging.Logger) *SnapshotProgress {
+	if store == nil {
+		return nil
+	}
+	p := &SnapshotProgress{store: store, logger: logger}
+	now := time.Now().UTC()
+	pod, _ := os.Hostname()
+
+	run, err := store.Load(state.RunKey(action, mode, repo, date))
+	if err != nil {
+		logger.WithFields(logging.Fields{"action": action, "mode": mode, "repo": repo, "date": date, "error": err}).Warn("Failed to load run state, starting from scratch")
+	}
+	if run != nil && run.Status == state.RunRunning {
+		run.Resumed++
+		run.Pod = pod
+		counts := run.Counts()
+		logger.WithFields(logging.Fields{"stateRunId": run.ID, "action": action, "mode": mode, "repo": repo, "date": date, "startedAt": run.StartedAt.Format(time.RFC3339), "resumed": run.Resumed, "success": counts[state.TaskSuccess], "failed": counts[state.TaskFailed], "unfinished": counts[state.TaskPending] + counts[state.TaskRunning]}).Info("Resuming run")
+	} else {
+		run = &state.Run{
+			ID:        uuid.NewString(),
+			Action:    action,
+			Mode:      mode,
+			Repo:      repo,
+			Date:      date,
+			Pod:       pod,
+			Status:    state.RunRunning,
+			StartedAt: now,
+		}
+		logger.WithFields(logging.Fields{"stateRunId": run.ID, "action": action, "mode": mode, "repo": repo, "date": date}).Info("Starting run")
+	}
+	run.UpdatedAt = now
+	p.run = run
+	p.save()
+	return p
+}
+
+func (p *SnapshotProgress) save() {
+	p.run.UpdatedAt = time.Now().UTC()
+	if err := p.store.Save(p.run); err != nil {
+		if !p.warned {
+			p.logger.WithFields(logging.Fields{"stateRunId": p.run.ID, "error": err}).Warn("Failed to persist run state, progress will not survive a restart")
+		}
+		p.warned = true
+		return
+	}
+	p.warned = false
+}
+
+func (p *SnapshotProgress) Register(tasks []SnapshotTask) {
+	if p == nil || len(tasks) == 0 {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, t := range tasks {
+		if existing := p.run.Task(t.Repo, t.SnapshotName); existing != nil {
+			if existing.Status == state.TaskFailed {
+				p.logger.WithFields(logging.Fields{"stateRunId": p.run.ID, "repo": t.Repo, "snapshot": t.SnapshotName, "lastError": existing.LastError}).Info("Requeuing failed snapshot task")
+				existing.Status = state.TaskPending
+				existing.Attempts = 0
+				existing.UpdatedAt = time.Now().UTC()
+			}
+			continue
+		}
+		p.run.Tasks = append(p.run.Tasks, &state.Task{
+			Repo:      t.Repo,
+			Snapshot:  t.SnapshotName,
+			Indices:   t.IndicesStr,
+			Status:    state.TaskPending,
+			UpdatedAt: time.Now().UTC(),
+		})
+	}
+	p.save()
+}
+
+func (p *SnapshotProgress) Completed(repo, snapshot string) bool {
+	status := p.taskStatus(repo, snapshot)
+	return status == state.TaskSuccess || status == state.TaskPartial
+}
+
+func (p *SnapshotProgress) Started(repo, snapshot string) bool {
+	return p.taskStatus(repo, snapshot) == state.TaskRunning
+}
+
+func (p *SnapshotProgress) taskStatus(repo, snapshot string) string {
+	if p == nil {
+		return ""
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if t := p.run.Task(repo, snapshot); t != nil {
+		return t.Status
+	}
+	return ""
+}
+
+func (p *SnapshotProgress) FirstAttempt(repo, snapshot string) int {
+	if p == nil {
+		return 1
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if t := p.run.Task(repo, snapshot); t != nil && t.Attempts > 0 {
+		return t.Attempts
+	}
+	return 1
+}
+
+func (p *SnapshotProgress) Running(repo, snapshot string, attempt int, taskErr error) {
+	p.update(repo, snapshot, state.TaskRunning, attempt, taskErr)
+}
+
+func (p *SnapshotProgress) KeptPartial(repo, snapshot string) {
+	p.update(repo, snapshot, state.TaskPartial, 0, nil)
+}
+
+func (p *SnapshotProgress) Done(repo, snapshot string, taskErr error) {
+	if taskErr != nil {
+		p.update(repo, snapshot, state.TaskFailed, 0, taskErr)
+		return
+	}
+	p.update(repo, snapshot, state.TaskSuccess, 0, nil)
+}
+
+func (p *SnapshotProgress) update(repo, snapshot, status string, attempt int, taskErr error) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	t := p.run.Task(repo, snapshot)
+	if t == nil {
+		t = &state.Task{Repo: repo, Snapshot: snapshot}
+		p.run.Tasks = append(p.run.Tasks, t)
+	}
+	t.Status = status
+	if attempt > 0 {
+		t.Attempts = attempt
+	}
+	if taskErr != nil {
+		t.LastError = taskErr.Error()
+	} else if status == state.TaskSuccess {
+		t.LastError = ""
+	}
+	t.UpdatedAt = time.Now().UTC()
+	p.save()
+}
+
+func (p *SnapshotProgress) Finish() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	counts := p.run.Counts()
+	p.run.Status = state.RunDone
+	if counts[state.TaskFailed] > 0 {
+		p.run.Status = state.RunFailed
+	}
+	p.save()
+	p.logger.WithFields(logging.Fields{"stateRunId": p.run.ID, "status": p.run.Status, "success": counts[state.TaskSuccess], "failed": counts[state.TaskFailed]}).Info("Run finished")
+}
+
+func (p *SnapshotProgress) CheckAndCleanSnapshot(snapshotName, indexName string, snapshots []opensearch.Snapshot, client *opensearch.Client, snapRepo string, logger *logging.Logger) (bool, error) {
+	switch p.taskStatus(snapRepo, snapshotName) {
+	case state.TaskRunning:
+		logger.WithFields(logging.Fields{"repo": snapRepo, "snapshot": snapshotName}).Info("Snapshot was started by this run before a restart, waiting for it instead of cleaning")
+		return false, nil
+	case state.TaskSuccess:
+		return false, nil
+	case state.TaskPartial:
+		logger.WithFields(logging.Fields{"repo": snapRepo, "snapshot": snapshotName}).Info("PARTIAL snapshot was kept by this run before a restart, not cleaning")
+		return false, nil
+	}
+	return CheckAndCleanSnapshot(snapshotName, indexName, snapshots, client, snapRepo, logger)
+}
@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"fmt"
+	"osctl/pkg/config"
+	"osctl/pkg/logging"
+	"osctl/pkg/opensearch"
+	"sort"
+	"strings"
+)
+
+func SnapshotRepositoriesFor(defaultRepo string, indicesConfig []config.IndexConfig) []string {
+	return repositoriesFor(defaultRepo, indicesConfig, false)
+}
+
+func SnapshotAndCopyRepositoriesFor(defaultRepo string, indicesConfig []config.IndexConfig) []string {
+	return repositoriesFor(defaultRepo, indicesConfig, true)
+}
+
+func repositoriesFor(defaultRepo string, indicesConfig []config.IndexConfig, withCopies bool) []string {
+	seen := make(map[string]bool)
+	var repos []string
+	add := func(repo string) {
+		if repo != "" && !seen[repo] {
+			seen[repo] = true
+			repos = append(repos, repo)
+		}
+	}
+	add(defaultRepo)
+	for _, ic := range indicesConfig {
+		if ic.Snapshot {
+			add(ic.Repository)
+			if withCopies {
+				add(ic.SecondaryRepository)
+			}
+		}
+	}
+	sort.Strings(repos)
+	return repos
+}
+
+func RepositorySettingsDrift(desired map[string]string, current opensearch.Repository) []string {
+	var diffs []string
+	for key, want := range desired {
+		got, ok := current.Settings[key]
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("%s: <unset> → %s", key, want))
+			continue
+		}
+		if gotStr := fmt.Sprint(got); gotStr != want {
+			diffs = append(diffs, fmt.Sprintf("%s: %s → %s", key, gotStr, want))
+		}
+	}
+	sort.Strings(diffs)
+	return diffs
+}
+
+func CheckSnapshotRepositories(client *opensearch.Client, repos []string, logger *logging.Logger) error {
+	if len(repos) == 0 {
+		return nil
+	}
+	registered, err := client.GetRepositories()
+	if err != nil {
+		return fmt.Errorf("failed to get snapshot repositories: %v", err)
+	}
+	var problems []string
+	for _, repo := range repos {
+		if _, ok := registered[repo]; !ok {
+			problems = append(problems, fmt.Sprintf("%s: not registered", repo))
+			continue
+		}
+		nodes, err := client.VerifyRepository(repo)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: verification failed: %v", repo, err))
+			continue
+		}
+		logger.WithFields(logging.Fields{"repo": repo, "nodes": len(nodes)}).Info("Snapshot repository verified")
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("snapshot repository check failed: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"osctl/pkg/alerts"
 	"osctl/pkg/logging"
+	"osctl/pkg/metrics"
 	"osctl/pkg/opensearch"
+	"osctl/pkg/report"
 	"path"
 	"sort"
 	"strings"
@@ -13,11 +15,34 @@ import (
 )
 
 type RestoreTask struct {
-	SnapshotName string
-	Indices      []string
-	Repo         string
-	Size         int64
-	PollInterval time.Duration
+	SnapshotName   string
+	Indices        []string
+	Repo           string
+	Size           int64
+	PollInterval   time.Duration
+	RenameTemplate string
+	RestoreDate    string
+}
+
+func (t RestoreTask) TargetIndex(index string) string {
+	return RenderRestoreName(t.RenameTemplate, index, t.RestoreDate)
+}
+
+func RenderRestoreName(template, index, restoreDate string) string {
+	if template == "" {
+		return index
+	}
+	name := strings.ReplaceAll(template, "<index>", index)
+	return strings.ReplaceAll(name, "<restore-date>", restoreDate)
+}
+
+func RenameTemplateGlob(template string) string {
+	glob := strings.ReplaceAll(template, "<index>", "*")
+	glob = strings.ReplaceAll(glob, "<restore-date>", "*")
+	for strings.Contains(glob, "**") {
+		glob = strings.ReplaceAll(glob, "**", "*")
+	}
+	return glob
 }
 
 func MatchesAnyPattern(name string, patterns []string) bool {
@@ -63,7 +88,7 @@ func SortRestoreTasksBySizeDesc(tasks []RestoreTask) []RestoreTask {
 	return sorted
 }
 
-func RestoreSnapshotsInParallel(client *opensearch.Client, tasks []RestoreTask, maxConcurrent int, madisonClient *alerts.Client, namespace, dateStr string, filter []string, logger *logging.Logger) ([]string, []string) {
+func RestoreSnapshotsInParallel(client *opensearch.Client, tasks []RestoreTask, maxConcurrent int, notifier alerts.Notifier, namespace, dateStr string, filter []string, logger *logging.Logger, limiter *ConcurrencyLimiter) ([]string, []string) {
 	var successful, failed []string
 	var mu sync.Mutex
 	var wg sync.WaitGroup
@@ -74,7 +99,7 @@ func RestoreSnapshotsInParallel(client *opensearch.Client, tasks []RestoreTask,
 
 	sorted := SortRestoreTasksBySizeDesc(tasks)
 
-	logger.Info(fmt.Sprintf("Starting parallel restore tasksCount=%d maxConcurrent=%d sortOrder=descending (largest first)", len(sorted), maxConcurrent))
+	logger.WithFields(logging.Fields{"tasksCount": len(sorted), "maxConcurrent": maxConcurrent, "sortOrder": "descending"}).Info("Starting parallel restore (largest first)")
 	if len(sorted) > 0 {
 		order := make([]string, 0, len(sorted))
 		for _, t := range sorted {
@@ -89,6 +114,9 @@ func RestoreSnapshotsInParallel(client *opensearch.Client, tasks []RestoreTask,
 	}
 	close(taskChan)
 
+	limiter.Start()
+	defer limiter.Stop()
+
 	logger.Info(fmt.Sprintf("Creating %d worker goroutines for restore", maxConcurrent))
 	for i := 0; i < maxConcurrent; i++ {
 		workerID := i + 1
@@ -96,18 +124,20 @@ func RestoreSnapshotsInParallel(client *opensearch.Client, tasks []RestoreTask,
 		go func(id int) {
 			defer wg.Done()
 			for task := range taskChan {
-				err := RestoreOneSnapshot(client, task, madisonClient, namespace, dateStr, filter, maxConcurrent, logger, id)
+				limiter.Acquire()
+				err := RestoreOneSnapshot(client, task, notifier, namespace, dateStr, filter, limiter.Limit(maxConcurrent), logger, id)
+				limiter.Release()
 				mu.Lock()
 				if err != nil {
-					logger.Error(fmt.Sprintf("Worker %d: Snapshot restored with errors snapshot=%s error=%v", id, task.SnapshotName, err))
+					logger.WithFields(logging.Fields{"worker": id, "snapshot": task.SnapshotName, "error": err}).Error("Snapshot restored with errors")
 					failed = append(failed, task.SnapshotName)
 				} else {
-					logger.Info(fmt.Sprintf("Worker %d: Successfully restored snapshot snapshot=%s", id, task.SnapshotName))
+					logger.WithFields(logging.Fields{"worker": id, "snapshot": task.SnapshotName}).Info("Successfully restored snapshot")
 					successful = append(successful, task.SnapshotName)
 				}
 				mu.Unlock()
 			}
-			logger.Info(fmt.Sprintf("Worker %d: Finished processing all assigned restore tasks", id))
+			logger.WithField("worker", id).Info("Finished processing all assigned restore tasks")
 		}(workerID)
 	}
 
@@ -115,9 +145,9 @@ func RestoreSnapshotsInParallel(client *opensearch.Client, tasks []RestoreTask,
 	return successful, failed
 }
 
-func RestoreOneSnapshot(client *opensearch.Client, task RestoreTask, madisonClient *alerts.Client, namespace, dateStr string, filter []string, maxConcurrent int, logger *logging.Logger, workerID int) error {
+func RestoreOneSnapshot(client *opensearch.Client, task RestoreTask, notifier alerts.Notifier, namespace, dateStr string, filter []string, maxConcurrent int, logger *logging.Logger, workerID int) error {
 	start := time.Now()
-	logger.Info(fmt.Sprintf("Worker %d: Starting restore snapshot=%s repo=%s size=%s indicesCount=%d", workerID, task.SnapshotName, task.Repo, formatSize(task.Size), len(task.Indices)))
+	logger.WithFields(logging.Fields{"worker": workerID, "snapshot": task.SnapshotName, "repo": task.Repo, "size": formatSize(task.Size), "indicesCount": len(task.Indices)}).Info("Starting restore")
 
 	var failedIndices []string
 	for _, idx := range task.Indices {
@@ -127,20 +157,21 @@ func RestoreOneSnapshot(client *opensearch.Client, task RestoreTask, madisonClie
 		}
 
 		if err := restoreSingleIndex(client, task, idx, filter, maxConcurrent, logger, workerID); err != nil {
-			logger.Error(fmt.Sprintf("Worker %d: Failed to restore index index=%s snapshot=%s error=%v", workerID, idx, task.SnapshotName, err))
+			logger.WithFields(logging.Fields{"worker": workerID, "index": idx, "snapshot": task.SnapshotName, "error": err}).Error("Failed to restore index")
 			failedIndices = append(failedIndices, idx)
-			if madisonClient != nil {
-				if _, aerr := madisonClient.SendMadisonRestoreFailedAlert(task.SnapshotName, idx, task.Repo, namespace, dateStr); aerr != nil {
-					logger.Error(fmt.Sprintf("Worker %d: Failed to send Madison restore-failed alert index=%s error=%v", workerID, idx, aerr))
+			report.Failed(task.TargetIndex(idx), err)
+			if notifier != nil {
+				if _, aerr := notifier.Notify(alerts.NewRestoreFailedEvent(task.SnapshotName, idx, task.Repo, namespace, dateStr)); aerr != nil {
+					logger.WithFields(logging.Fields{"worker": workerID, "index": idx, "error": aerr}).Error("Failed to send restore-failed alert")
 				} else {
-					logger.Info(fmt.Sprintf("Worker %d: Madison alert sent: type=SnapshotRestoreFailed index=%s", workerID, idx))
+					logger.WithFields(logging.Fields{"type": "SnapshotRestoreFailed", "worker": workerID, "index": idx}).Info("Alert sent")
 				}
 			}
 			continue
 		}
 	}
 
-	logger.Info(fmt.Sprintf("Worker %d: Snapshot restore done snapshot=%s duration=%s failedIndices=%d", workerID, task.SnapshotName, formatDuration(time.Since(start)), len(failedIndices)))
+	logger.WithFields(logging.Fields{"worker": workerID, "snapshot": task.SnapshotName, "duration": formatDuration(time.Since(start)), "failedIndices": len(failedIndices)}).Info("Snapshot restore done")
 	if len(failedIndices) > 0 {
 		return fmt.Errorf("failed indices: %s", strings.Join(failedIndices, ", "))
 	}
@@ -148,36 +179,46 @@ func RestoreOneSnapshot(client *opensearch.Client, task RestoreTask, madisonClie
 }
 
 func restoreSingleIndex(client *opensearch.Client, task RestoreTask, index string, filter []string, maxConcurrent int, logger *logging.Logger, workerID int) error {
-	class, err := ClassifyRestore(client, index)
+	target := task.TargetIndex(index)
+	class, err := ClassifyRestore(client, target)
 	if err != nil {
-		logger.Warn(fmt.Sprintf("Worker %d: Failed to classify index, will attempt restore index=%s error=%v", workerID, index, err))
+		logger.WithFields(logging.Fields{"worker": workerID, "index": target, "error": err}).Warn("Failed to classify index, will attempt restore")
 		class = RestoreMissing
 	}
 	switch class {
 	case RestoreDone:
-		logger.Info(fmt.Sprintf("Worker %d: Index already restored and healthy, skipping index=%s", workerID, index))
+		logger.WithFields(logging.Fields{"worker": workerID, "index": target}).Info("Index already restored and healthy, skipping")
+		report.Skipped(target, "already restored")
 		return nil
 	case RestoreRestoring:
-		logger.Info(fmt.Sprintf("Worker %d: Index already restoring, waiting for it index=%s snapshot=%s", workerID, index, task.SnapshotName))
-		return WaitForRestore(client, []string{index}, task.PollInterval, logger, workerID, task.SnapshotName)
+		logger.WithFields(logging.Fields{"worker": workerID, "index": target, "snapshot": task.SnapshotName}).Info("Index already restoring, waiting for it")
+		return WaitForRestore(client, []string{target}, task.PollInterval, logger, workerID, task.SnapshotName)
 	case RestoreFailed:
-		logger.Warn(fmt.Sprintf("Worker %d: Index has a failed restore, deleting to retry index=%s", workerID, index))
-		if derr := client.DeleteIndex(index); derr != nil {
-			return fmt.Errorf("failed to delete failed-restore index %s: %v", index, derr)
+		logger.WithFields(logging.Fields{"worker": workerID, "index": target}).Warn("Index has a failed restore, deleting to retry")
+		if derr := client.DeleteIndex(target); derr != nil {
+			return fmt.Errorf("failed to delete failed-restore index %s: %v", target, derr)
 		}
 	}
 
 	WaitForOurRestoreSlot(client, filter, maxConcurrent, task.PollInterval, logger, workerID)
 
 	start := time.Now()
-	logger.Info(fmt.Sprintf("Worker %d: Restoring index=%s snapshot=%s", workerID, index, task.SnapshotName))
-	if err := client.RestoreSnapshot(task.Repo, task.SnapshotName, restoreBodyFor(index)); err != nil {
+	if target != index {
+		logger.WithFields(logging.Fields{"worker": workerID, "index": index, "as": target, "snapshot": task.SnapshotName}).Info("Restoring")
+	} else {
+		logger.WithFields(logging.Fields{"worker": workerID, "index": index, "snapshot": task.SnapshotName}).Info("Restoring")
+	}
+	if err := client.RestoreSnapshot(task.Repo, task.SnapshotName, RestoreBodyWithRename(index, target)); err != nil {
+		metrics.RestoreDuration.Observe(time.Since(start).Seconds(), task.Repo, metrics.ResultFailure)
 		return fmt.Errorf("failed to start restore: %v", err)
 	}
-	if err := WaitForRestore(client, []string{index}, task.PollInterval, logger, workerID, task.SnapshotName); err != nil {
+	if err := WaitForRestore(client, []string{target}, task.PollInterval, logger, workerID, task.SnapshotName); err != nil {
+		metrics.RestoreDuration.Observe(time.Since(start).Seconds(), task.Repo, metrics.ResultFailure)
 		return err
 	}
-	logger.Info(fmt.Sprintf("Worker %d: Index restored and verified index=%s snapshot=%s duration=%s", workerID, index, task.SnapshotName, formatDuration(time.Since(start))))
+	metrics.RestoreDuration.Observe(time.Since(start).Seconds(), task.Repo, metrics.ResultSuccess)
+	report.Succeeded(target).Took(time.Since(start))
+	logger.WithFields(logging.Fields{"worker": workerID, "index": target, "snapshot": task.SnapshotName, "duration": formatDuration(time.Since(start))}).Info("Index restored and verified")
 	return nil
 }
 
@@ -196,6 +237,19 @@ func restoreBodyFor(index string) map[string]any {
 	}
 }
 
+func RestoreBodyForIndices(indices []string) map[string]any {
+	return restoreBodyFor(strings.Join(indices, ","))
+}
+
+func RestoreBodyWithRename(index, target string) map[string]any {
+	body := restoreBodyFor(index)
+	if target != index {
+		body["rename_pattern"] = "^(.+)$"
+		body["rename_replacement"] = target
+	}
+	return body
+}
+
 type RestoreClass int
 
 const (
@@ -253,14 +307,14 @@ func WaitForOurRestoreSlot(client *opensearch.Client, filter []string, maxConcur
 	for {
 		active, err := client.ActiveSnapshotRecoveryIndices()
 		if err != nil {
-			logger.Warn(fmt.Sprintf("Worker %d: Failed to poll active restores, proceeding without slot wait error=%v", workerID, err))
+			logger.WithFields(logging.Fields{"worker": workerID, "error": err}).Warn("Failed to poll active restores, proceeding without slot wait")
 			return
 		}
 		ours := FilterIndices(active, filter)
 		if len(ours) < maxConcurrent {
 			return
 		}
-		logger.Info(fmt.Sprintf("Worker %d: Restore slot busy ourActiveRestores=%d max=%d, waiting", workerID, len(ours), maxConcurrent))
+		logger.WithFields(logging.Fields{"worker": workerID, "ourActiveRestores": len(ours), "max": maxConcurrent}).Info("Restore slot busy, waiting")
 		time.Sleep(pollInterval)
 	}
 }
@@ -280,7 +334,7 @@ func RepairFailedRestore(client *opensearch.Client, index string, filter []strin
 	if err := client.RestoreSnapshot(repo, snap, restoreBodyFor(index)); err != nil {
 		return fmt.Errorf("failed to restart restore for %s from %s/%s: %v", index, repo, snap, err)
 	}
-	logger.Info(fmt.Sprintf("Repaired failed restore: deleted and re-restoring index=%s from repo=%s snapshot=%s", index, repo, snap))
+	logger.WithFields(logging.Fields{"index": index, "repo": repo, "snapshot": snap}).Info("Repaired failed restore: deleted and re-restoring")
 	return nil
 }
 
@@ -296,7 +350,7 @@ func WaitForRestore(client *opensearch.Client, indices []string, pollInterval ti
 		health, err := client.GetIndicesHealth(indices)
 		if err != nil {
 			pollErrors++
-			logger.Warn(fmt.Sprintf("Worker %d: Failed to poll restore health snapshot=%s error=%v (%d/%d)", workerID, snapshotName, err, pollErrors, maxPollErrors))
+			logger.WithFields(logging.Fields{"worker": workerID, "snapshot": snapshotName, "error": err, "attempt": pollErrors, "maxAttempts": maxPollErrors}).Warn("Failed to poll restore health")
 			if pollErrors >= maxPollErrors {
 				return fmt.Errorf("exceeded consecutive health poll errors for snapshot %s: %v", snapshotName, err)
 			}
@@ -326,7 +380,7 @@ func WaitForRestore(client *opensearch.Client, indices []string, pollInterval ti
 		}
 
 		if allReady {
-			logger.Info(fmt.Sprintf("Worker %d: Restore healthy snapshot=%s indices=%d/%d elapsed=%s", workerID, snapshotName, ready, total, formatDuration(time.Since(start))))
+			logger.WithFields(logging.Fields{"worker": workerID, "snapshot": snapshotName, "indices": fmt.Sprintf("%d/%d", ready, total), "elapsed": formatDuration(time.Since(start))}).Info("Restore healthy")
 			return nil
 		}
 
@@ -334,7 +388,7 @@ func WaitForRestore(client *opensearch.Client, indices []string, pollInterval ti
 		if anyRed {
 			state = "recovering (some red)"
 		}
-		logger.Info(fmt.Sprintf("Worker %d: Restore in progress snapshot=%s state=%s readyIndices=%d/%d elapsed=%s", workerID, snapshotName, state, ready, total, formatDuration(time.Since(start))))
+		logger.WithFields(logging.Fields{"worker": workerID, "snapshot": snapshotName, "state": state, "readyIndices": fmt.Sprintf("%d/%d", ready, total), "elapsed": formatDuration(time.Since(start))}).Info("Restore in progress")
 		time.Sleep(pollInterval)
 	}
 }
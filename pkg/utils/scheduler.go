@@ -0,0 +1,218 @@
+package utils
+
+import (
+	"fmt"
+	"osctl/pkg/config"
+	"osctl/pkg/logging"
+	"osctl/pkg/opensearch"
+	"strings"
+	"sync"
+	"time"
+)
+
+type ConcurrencyThresholds struct {
+	MaxCPU                   float64
+	MaxLoadPerCPU            float64
+	MaxSnapshotQueue         int
+	MaxRecoveryThrottleRatio float64
+}
+
+type clusterLoad struct {
+	cpu            float64
+	cpuNode        string
+	loadPerCPU     float64
+	loadNode       string
+	snapshotQueue  int
+	rejected       int64
+	throttleMillis int64
+}
+
+type ConcurrencyLimiter struct {
+	client     *opensearch.Client
+	logger     *logging.Logger
+	kind       string
+	min        int
+	max        int
+	interval   time.Duration
+	thresholds ConcurrencyThresholds
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	limit  int
+	active int
+	prev   *clusterLoad
+	prevAt time.Time
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+func NewConcurrencyLimiter(cfg *config.Config, client *opensearch.Client, kind string, maxConcurrent int, logger *logging.Logger) *ConcurrencyLimiter {
+	if !cfg.GetAdaptiveConcurrency() {
+		return nil
+	}
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+	minConcurrent := min(max(cfg.GetMinConcurrentSnapshots(), 1), maxConcurrent)
+	l := &ConcurrencyLimiter{
+		client:   client,
+		logger:   logger,
+		kind:     kind,
+		min:      minConcurrent,
+		max:      maxConcurrent,
+		interval: cfg.GetAdaptiveInterval(),
+		thresholds: ConcurrencyThresholds{
+			MaxCPU:                   cfg.GetAdaptiveMaxCPU(),
+			MaxLoadPerCPU:            cfg.GetAdaptiveMaxLoad(),
+			MaxSnapshotQueue:         cfg.GetAdaptiveMaxQueue(),
+			MaxRecoveryThrottleRatio: cfg.GetAdaptiveMaxRecoveryThrottle(),
+		},
+		limit: maxConcurrent,
+	}
+	l.cond = sync.NewCond(&l.mu)
+	return l
+}
+
+func (l *ConcurrencyLimiter) Start() {
+	if l == nil {
+		return
+	}
+	l.stop = make(chan struct{})
+	l.done = make(chan struct{})
+	l.logger.WithFields(logging.Fields{"kind": l.kind, "min": l.min, "max": l.max, "start": l.limit, "interval": l.interval}).Info("Adaptive concurrency enabled")
+	go func() {
+		defer close(l.done)
+		ticker := time.NewTicker(l.interval)
+		defer ticker.Stop()
+		l.adjust()
+		for {
+			select {
+			case <-l.stop:
+				return
+			case <-ticker.C:
+				l.adjust()
+			}
+		}
+	}()
+}
+
+func (l *ConcurrencyLimiter) Stop() {
+	if l == nil || l.stop == nil {
+		return
+	}
+	close(l.stop)
+	<-l.done
+	l.mu.Lock()
+	l.logger.WithFields(logging.Fields{"kind": l.kind, "finalLimit": l.limit}).Info("Adaptive concurrency stopped")
+	l.mu.Unlock()
+}
+
+func (l *ConcurrencyLimiter) Limit(fallback int) int {
+	if l == nil {
+		return fallback
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
+
+func (l *ConcurrencyLimiter) Acquire() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for l.active >= l.limit {
+		l.cond.Wait()
+	}
+	l.active++
+}
+
+func (l *ConcurrencyLimiter) Release() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.active--
+	l.cond.Broadcast()
+}
+
+func (l *ConcurrencyLimiter) adjust() {
+	stats, err := l.client.GetNodesLoadStats()
+	if err != nil {
+		l.logger.WithFields(logging.Fields{"kind": l.kind, "limit": l.Limit(0), "error": err}).Warn("Failed to get node stats, keeping concurrency")
+		return
+	}
+	now := time.Now()
+	cur := summarizeClusterLoad(stats)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var rejectedDelta int64
+	var throttleRatio float64
+	if l.prev != nil {
+		rejectedDelta = max(cur.rejected-l.prev.rejected, 0)
+		if elapsed := now.Sub(l.prevAt).Milliseconds(); elapsed > 0 {
+			throttleRatio = float64(max(cur.throttleMillis-l.prev.throttleMillis, 0)) / float64(elapsed)
+		}
+	}
+	l.prev = &cur
+	l.prevAt = now
+
+	t := l.thresholds
+	var pressure []string
+	if t.MaxSnapshotQueue > 0 && cur.snapshotQueue > t.MaxSnapshotQueue {
+		pressure = append(pressure, fmt.Sprintf("snapshot_queue=%d>%d", cur.snapshotQueue, t.MaxSnapshotQueue))
+	}
+	if t.MaxCPU > 0 && cur.cpu > t.MaxCPU {
+		pressure = append(pressure, fmt.Sprintf("cpu=%.0f%%>%.0f%% node=%s", cur.cpu, t.MaxCPU, cur.cpuNode))
+	}
+	if t.MaxLoadPerCPU > 0 && cur.loadPerCPU > t.MaxLoadPerCPU {
+		pressure = append(pressure, fmt.Sprintf("load_per_cpu=%.2f>%.2f node=%s", cur.loadPerCPU, t.MaxLoadPerCPU, cur.loadNode))
+	}
+	if rejectedDelta > 0 {
+		pressure = append(pressure, fmt.Sprintf("indexing_rejections=+%d", rejectedDelta))
+	}
+	if t.MaxRecoveryThrottleRatio > 0 && throttleRatio > t.MaxRecoveryThrottleRatio {
+		pressure = append(pressure, fmt.Sprintf("recovery_throttle=%.2f>%.2f", throttleRatio, t.MaxRecoveryThrottleRatio))
+	}
+
+	if len(pressure) > 0 {
+		if l.limit > l.min {
+			l.logger.WithFields(logging.Fields{"kind": l.kind, "from": l.limit, "to": l.limit - 1, "signal": strings.Join(pressure, ",")}).Info("Concurrency lowered")
+			l.limit--
+		}
+		return
+	}
+
+	calm := cur.snapshotQueue == 0 &&
+		(t.MaxCPU <= 0 || cur.cpu < t.MaxCPU*0.7) &&
+		(t.MaxLoadPerCPU <= 0 || cur.loadPerCPU < t.MaxLoadPerCPU*0.7) &&
+		(t.MaxRecoveryThrottleRatio <= 0 || throttleRatio < t.MaxRecoveryThrottleRatio/2)
+	if calm && l.limit < l.max {
+		l.logger.WithFields(logging.Fields{"kind": l.kind, "from": l.limit, "to": l.limit + 1, "signal": "calm", "cpu": fmt.Sprintf("%.0f%%", cur.cpu), "loadPerCpu": fmt.Sprintf("%.2f", cur.loadPerCPU), "snapshotQueue": cur.snapshotQueue, "recoveryThrottle": fmt.Sprintf("%.2f", throttleRatio)}).Info("Concurrency raised")
+		l.limit++
+		l.cond.Broadcast()
+	}
+}
+
+func summarizeClusterLoad(stats []opensearch.NodeLoadStats) clusterLoad {
+	var load clusterLoad
+	for _, n := range stats {
+		if n.CPUPercent > load.cpu {
+			load.cpu = n.CPUPercent
+			load.cpuNode = n.Name
+		}
+		if n.Processors > 0 {
+			if perCPU := n.Load1m / float64(n.Processors); perCPU > load.loadPerCPU {
+				load.loadPerCPU = perCPU
+				load.loadNode = n.Name
+			}
+		}
+		load.snapshotQueue += n.SnapshotQueue
+		load.rejected += n.WriteRejected
+		load.throttleMillis += n.RecoveryThrottleMillis
+	}
+	return load
+}
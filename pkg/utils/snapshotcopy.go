@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"fmt"
+	"osctl/pkg/config"
+	"osctl/pkg/logging"
+	"osctl/pkg/opensearch"
+	"strings"
+	"time"
+)
+
+const SnapshotCopySuffix = "-copy"
+
+var repositoryStoreKeys = []string{"bucket", "base_path", "location", "container", "path"}
+
+func IsSnapshotCopy(name string) bool {
+	return strings.HasSuffix(name, SnapshotCopySuffix)
+}
+
+func SnapshotCopyName(snapshot string, sharedStore bool) string {
+	if sharedStore {
+		return snapshot + SnapshotCopySuffix
+	}
+	return snapshot
+}
+
+func SnapshotCopySource(name string) string {
+	return strings.TrimSuffix(name, SnapshotCopySuffix)
+}
+
+func RepositoriesShareStore(registered map[string]opensearch.Repository, a, b string) bool {
+	if a == b {
+		return true
+	}
+	ra, okA := registered[a]
+	rb, okB := registered[b]
+	if !okA || !okB || ra.Type != rb.Type {
+		return false
+	}
+	matched := false
+	for _, key := range repositoryStoreKeys {
+		va, hasA := ra.Settings[key]
+		vb, hasB := rb.Settings[key]
+		if hasA != hasB {
+			return false
+		}
+		if !hasA {
+			continue
+		}
+		if strings.Trim(fmt.Sprint(va), "/") != strings.Trim(fmt.Sprint(vb), "/") {
+			return false
+		}
+		matched = true
+	}
+	return matched
+}
+
+func SecondaryCopyRules(indicesConfig []config.IndexConfig) []config.IndexConfig {
+	var rules []config.IndexConfig
+	for _, ic := range indicesConfig {
+		if ic.Snapshot && ic.SecondaryRepository != "" {
+			rules = append(rules, ic)
+		}
+	}
+	return rules
+}
+
+func SourceRepository(defaultRepo string, ic config.IndexConfig) string {
+	if ic.Repository != "" {
+		return ic.Repository
+	}
+	return defaultRepo
+}
+
+func SecondaryRetentionDays(ic config.IndexConfig, s3Config config.S3SnapshotsConfig) int {
+	if ic.SecondarySnapshotCount > 0 {
+		return ic.SecondarySnapshotCount
+	}
+	if ic.SnapshotCountS3 > 0 {
+		return ic.SnapshotCountS3
+	}
+	return s3Config.UnitCount.All
+}
+
+func SnapshotBelongsToRule(s opensearch.Snapshot, ic config.IndexConfig, indicesConfig []config.IndexConfig) bool {
+	match := FindSnapshotConfig(s, indicesConfig)
+	return match != nil && match.Kind == ic.Kind && match.Value == ic.Value && match.Name == ic.Name
+}
+
+func IsManagedSnapshotCopy(s opensearch.Snapshot, indicesConfig []config.IndexConfig) bool {
+	if !IsSnapshotCopy(s.Snapshot) {
+		return false
+	}
+	source := s
+	source.Snapshot = SnapshotCopySource(s.Snapshot)
+	ic := FindSnapshotConfig(source, indicesConfig)
+	return ic != nil && ic.SecondaryRepository != ""
+}
+
+func HasSnapshotCopy(snapshot string, copies []opensearch.Snapshot) bool {
+	for _, c := range copies {
+		if c.State == "SUCCESS" && (c.Snapshot == snapshot || c.Snapshot == snapshot+SnapshotCopySuffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func WaitForSnapshotInRepo(client *opensearch.Client, repo, snapshot string, pollInterval, timeout time.Duration, logger *logging.Logger) error {
+	if pollInterval <= 0 {
+		pollInterval = 30 * time.Second
+	}
+	start := time.Now()
+	for {
+		snaps, err := client.GetSnapshotsDetailed(repo, snapshot)
+		if err != nil {
+			logger.WithFields(logging.Fields{"repo": repo, "snapshot": snapshot, "error": err}).Warn("Failed to poll snapshot")
+		} else if len(snaps) > 0 {
+			switch snaps[0].State {
+			case "SUCCESS":
+				return nil
+			case "IN_PROGRESS", "STARTED":
+			default:
+				return fmt.Errorf("snapshot %s/%s finished in state %s", repo, snapshot, snaps[0].State)
+			}
+		}
+		if timeout > 0 && time.Since(start) >= timeout {
+			return fmt.Errorf("snapshot %s/%s did not finish within %s", repo, snapshot, formatDuration(timeout))
+		}
+		logger.WithFields(logging.Fields{"repo": repo, "snapshot": snapshot, "elapsed": formatDuration(time.Since(start))}).Info("Snapshot in progress")
+		time.Sleep(pollInterval)
+	}
+}
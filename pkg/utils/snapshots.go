@@ -6,7 +6,9 @@ import (
 	"osctl/pkg/alerts"
 	"osctl/pkg/config"
 	"osctl/pkg/logging"
+	"osctl/pkg/metrics"
 	"osctl/pkg/opensearch"
+	"osctl/pkg/report"
 	"sort"
 	"strings"
 	"sync"
@@ -53,13 +55,13 @@ func CheckAndCleanSnapshot(snapshotName string, indexName string, snapshots []op
 				return true, nil
 			}
 			if snapshot.State == "PARTIAL" || snapshot.State == "FAILED" {
-				logger.Info(fmt.Sprintf("Deleting PARTIAL/FAILED snapshot snapshot=%s state=%s", snapshotName, snapshot.State))
+				logger.WithFields(logging.Fields{"snapshot": snapshotName, "state": snapshot.State}).Info("Deleting PARTIAL/FAILED snapshot")
 				err := DeleteSnapshotsWithRetry(client, snapRepo, []string{snapshotName}, logger)
 				if err != nil {
-					logger.Error(fmt.Sprintf("Failed to delete PARTIAL/FAILED snapshot snapshot=%s error=%v", snapshotName, err))
+					logger.WithFields(logging.Fields{"snapshot": snapshotName, "error": err}).Error("Failed to delete PARTIAL/FAILED snapshot")
 					return false, err
 				}
-				logger.Info(fmt.Sprintf("Deleted PARTIAL/FAILED snapshot snapshot=%s state=%s", snapshotName, snapshot.State))
+				logger.WithFields(logging.Fields{"snapshot": snapshotName, "state": snapshot.State}).Info("Deleted PARTIAL/FAILED snapshot")
 				return false, nil
 			}
 		}
@@ -95,7 +97,7 @@ func WaitForSnapshotCompletion(client *opensearch.Client, logger *logging.Logger
 	for {
 		status, err := client.GetSnapshotStatus()
 		if err != nil {
-			logger.Error(fmt.Sprintf("Failed to get snapshot status error=%v", err))
+			logger.WithField("error", err).Error("Failed to get snapshot status")
 			time.Sleep(60 * time.Second)
 			continue
 		}
@@ -132,13 +134,13 @@ func WaitForSnapshotCompletion(client *opensearch.Client, logger *logging.Logger
 
 		if len(identifiers) > 0 {
 			if targetSnapshot != "" {
-				logger.Info(fmt.Sprintf("Waiting for snapshots to complete count=%d jobs=%v target=%s", len(identifiers), identifiers, targetSnapshot))
+				logger.WithFields(logging.Fields{"count": len(identifiers), "jobs": identifiers, "target": targetSnapshot}).Info("Waiting for snapshots to complete")
 			} else {
-				logger.Info(fmt.Sprintf("Waiting for snapshots to complete count=%d jobs=%v", len(identifiers), identifiers))
+				logger.WithFields(logging.Fields{"count": len(identifiers), "jobs": identifiers}).Info("Waiting for snapshots to complete")
 			}
 		} else {
 			if targetSnapshot != "" {
-				logger.Info(fmt.Sprintf("Waiting for snapshots to complete target=%s", targetSnapshot))
+				logger.WithField("target", targetSnapshot).Info("Waiting for snapshots to complete")
 			} else {
 				logger.Info("Waiting for snapshots to complete")
 			}
@@ -172,16 +174,29 @@ type SnapshotTask struct {
 	DateStr      string
 	PollInterval time.Duration
 	Size         int64
+	Metadata     *SnapshotMetadata
 }
 
-func CreateSnapshotsInParallel(client *opensearch.Client, tasks []SnapshotTask, maxConcurrent int, madisonClient interface{}, logger *logging.Logger, sortDescending bool) ([]string, []string) {
+func CreateSnapshotsInParallel(client *opensearch.Client, tasks []SnapshotTask, maxConcurrent int, notifier alerts.Notifier, logger *logging.Logger, sortDescending bool, progress *SnapshotProgress, limiter *ConcurrencyLimiter) ([]string, []string) {
 	var successful []string
 	var failed []string
 	var mu sync.Mutex
 	var wg sync.WaitGroup
 
-	sortedTasks := make([]SnapshotTask, len(tasks))
-	copy(sortedTasks, tasks)
+	progress.Register(tasks)
+	sortedTasks := make([]SnapshotTask, 0, len(tasks))
+	for _, task := range tasks {
+		if !progress.Completed(task.Repo, task.SnapshotName) {
+			sortedTasks = append(sortedTasks, task)
+			continue
+		}
+		snapshotName := task.SnapshotName
+		if task.Repo != "" {
+			snapshotName = fmt.Sprintf("%s (repo=%s)", task.SnapshotName, task.Repo)
+		}
+		logger.WithFields(logging.Fields{"snapshot": task.SnapshotName, "repo": task.Repo}).Info("Snapshot task already completed in this run, skipping")
+		successful = append(successful, snapshotName)
+	}
 	sort.Slice(sortedTasks, func(i, j int) bool {
 		if sortDescending {
 			return sortedTasks[i].Size > sortedTasks[j].Size
@@ -193,13 +208,13 @@ func CreateSnapshotsInParallel(client *opensearch.Client, tasks []SnapshotTask,
 	if !sortDescending {
 		order = "ascending (smallest first)"
 	}
-	logger.Info(fmt.Sprintf("Starting parallel snapshot creation tasksCount=%d maxConcurrent=%d sortOrder=%s", len(sortedTasks), maxConcurrent, order))
+	logger.WithFields(logging.Fields{"tasksCount": len(sortedTasks), "maxConcurrent": maxConcurrent, "sortOrder": order}).Info("Starting parallel snapshot creation")
 	if len(sortedTasks) > 0 {
 		taskNames := make([]string, 0, len(sortedTasks))
 		for _, task := range sortedTasks {
 			taskNames = append(taskNames, task.SnapshotName)
 		}
-		logger.Info(fmt.Sprintf("Snapshot tasks in order: %s", strings.Join(taskNames, ", ")))
+		logger.WithField("snapshots", strings.Join(taskNames, ", ")).Info("Snapshot tasks in order")
 	}
 
 	taskChan := make(chan SnapshotTask, len(sortedTasks))
@@ -209,6 +224,9 @@ func CreateSnapshotsInParallel(client *opensearch.Client, tasks []SnapshotTask,
 	}
 	close(taskChan)
 
+	limiter.Start()
+	defer limiter.Stop()
+
 	logger.Info(fmt.Sprintf("Creating %d worker goroutines for snapshot creation", maxConcurrent))
 	for i := 0; i < maxConcurrent; i++ {
 		workerID := i + 1
@@ -217,10 +235,12 @@ func CreateSnapshotsInParallel(client *opensearch.Client, tasks []SnapshotTask,
 			defer wg.Done()
 
 			for task := range taskChan {
-				logger.Info(fmt.Sprintf("Worker %d: Starting snapshot creation snapshot=%s repo=%s", id, task.SnapshotName, task.Repo))
-				logger.Info(fmt.Sprintf("Worker %d: Snapshot indices %s", id, task.IndicesStr))
+				limiter.Acquire()
+				logger.WithFields(logging.Fields{"worker": id, "snapshot": task.SnapshotName, "repo": task.Repo}).Info("Starting snapshot creation")
+				logger.WithFields(logging.Fields{"worker": id, "indices": task.IndicesStr}).Info("Snapshot indices")
 
-				err := CreateSnapshotWithRetry(client, task.SnapshotName, task.IndicesStr, task.Repo, task.Namespace, task.DateStr, madisonClient, logger, task.PollInterval, maxConcurrent, id)
+				err := CreateSnapshotWithRetry(client, task.SnapshotName, task.IndicesStr, task.Repo, task.Namespace, task.DateStr, notifier, logger, task.PollInterval, limiter.Limit(maxConcurrent), id, progress, task.Metadata)
+				limiter.Release()
 
 				mu.Lock()
 				snapshotName := task.SnapshotName
@@ -228,15 +248,15 @@ func CreateSnapshotsInParallel(client *opensearch.Client, tasks []SnapshotTask,
 					snapshotName = fmt.Sprintf("%s (repo=%s)", task.SnapshotName, task.Repo)
 				}
 				if err != nil {
-					logger.Error(fmt.Sprintf("Worker %d: Failed to create snapshot after retries snapshot=%s error=%v", id, task.SnapshotName, err))
+					logger.WithFields(logging.Fields{"worker": id, "snapshot": task.SnapshotName, "error": err}).Error("Failed to create snapshot after retries")
 					failed = append(failed, snapshotName)
 				} else {
-					logger.Info(fmt.Sprintf("Worker %d: Successfully created snapshot snapshot=%s", id, task.SnapshotName))
+					logger.WithFields(logging.Fields{"worker": id, "snapshot": task.SnapshotName}).Info("Successfully created snapshot")
 					successful = append(successful, snapshotName)
 				}
 				mu.Unlock()
 			}
-			logger.Info(fmt.Sprintf("Worker %d: Finished processing all assigned tasks", id))
+			logger.WithField("worker", id).Info("Finished processing all assigned tasks")
 		}(workerID)
 	}
 
@@ -245,33 +265,21 @@ func CreateSnapshotsInParallel(client *opensearch.Client, tasks []SnapshotTask,
 }
 
 func WaitForSnapshotSlot(client *opensearch.Client, logger *logging.Logger, maxConcurrent int, waitInterval time.Duration, waitingForSnapshot string, workerID int) error {
+	wlog := workerLogger(logger, workerID)
+	if waitingForSnapshot != "" {
+		wlog = wlog.WithField("snapshot", waitingForSnapshot)
+	}
 	for {
 		activeSnapshots, err := GetActiveSnapshots(client)
 		if err != nil {
-			if workerID > 0 {
-				logger.Warn(fmt.Sprintf("Worker %d: Failed to get active snapshot status, retrying error=%v", workerID, err))
-			} else {
-				logger.Warn(fmt.Sprintf("Failed to get active snapshot status, retrying error=%v", err))
-			}
+			wlog.WithField("error", err).Warn("Failed to get active snapshot status, retrying")
 			time.Sleep(waitInterval)
 			continue
 		}
 
 		activeCount := len(activeSnapshots)
 		if activeCount < maxConcurrent {
-			if waitingForSnapshot != "" {
-				if workerID > 0 {
-					logger.Info(fmt.Sprintf("Worker %d: Snapshot slot available for snapshot=%s active=%d max=%d", workerID, waitingForSnapshot, activeCount, maxConcurrent))
-				} else {
-					logger.Info(fmt.Sprintf("Snapshot slot available for snapshot=%s active=%d max=%d", waitingForSnapshot, activeCount, maxConcurrent))
-				}
-			} else {
-				if workerID > 0 {
-					logger.Info(fmt.Sprintf("Worker %d: Snapshot slot available active=%d max=%d", workerID, activeCount, maxConcurrent))
-				} else {
-					logger.Info(fmt.Sprintf("Snapshot slot available active=%d max=%d", activeCount, maxConcurrent))
-				}
-			}
+			wlog.WithFields(logging.Fields{"active": activeCount, "max": maxConcurrent}).Info("Snapshot slot available")
 			return nil
 		}
 
@@ -283,19 +291,7 @@ func WaitForSnapshotSlot(client *opensearch.Client, logger *logging.Logger, maxC
 				activeNames = append(activeNames, s.Snapshot)
 			}
 		}
-		if waitingForSnapshot != "" {
-			if workerID > 0 {
-				logger.Info(fmt.Sprintf("Worker %d: Waiting for snapshot slot snapshot=%s active=%d max=%d activeSnapshots=[%s] waitInterval=%v", workerID, waitingForSnapshot, activeCount, maxConcurrent, strings.Join(activeNames, ", "), waitInterval))
-			} else {
-				logger.Info(fmt.Sprintf("Waiting for snapshot slot snapshot=%s active=%d max=%d activeSnapshots=[%s] waitInterval=%v", waitingForSnapshot, activeCount, maxConcurrent, strings.Join(activeNames, ", "), waitInterval))
-			}
-		} else {
-			if workerID > 0 {
-				logger.Info(fmt.Sprintf("Worker %d: Waiting for snapshot slot active=%d max=%d activeSnapshots=[%s] waitInterval=%v", workerID, activeCount, maxConcurrent, strings.Join(activeNames, ", "), waitInterval))
-			} else {
-				logger.Info(fmt.Sprintf("Waiting for snapshot slot active=%d max=%d activeSnapshots=[%s] waitInterval=%v", activeCount, maxConcurrent, strings.Join(activeNames, ", "), waitInterval))
-			}
-		}
+		wlog.WithFields(logging.Fields{"active": activeCount, "max": maxConcurrent, "activeSnapshots": strings.Join(activeNames, ", "), "waitInterval": waitInterval}).Info("Waiting for snapshot slot")
 		time.Sleep(waitInterval)
 	}
 }
@@ -312,7 +308,7 @@ func CheckIndicesExist(client *opensearch.Client, indicesStr string, logger *log
 
 		indicesInfo, err := client.GetIndicesWithFields(indexName, "index")
 		if err != nil {
-			logger.Warn(fmt.Sprintf("Failed to check index existence index=%s error=%v", indexName, err))
+			logger.WithFields(logging.Fields{"index": indexName, "error": err}).Warn("Failed to check index existence")
 			continue
 		}
 
@@ -327,67 +323,71 @@ func CheckIndicesExist(client *opensearch.Client, indicesStr string, logger *log
 		if found {
 			existingIndices = append(existingIndices, indexName)
 		} else {
-			logger.Warn(fmt.Sprintf("Index does not exist, skipping snapshot creation index=%s", indexName))
+			logger.WithField("index", indexName).Warn("Index does not exist, skipping snapshot creation")
 		}
 	}
 
 	return existingIndices, nil
 }
 
-func CreateSnapshotWithRetry(client *opensearch.Client, snapshotName, indexName, snapRepo, namespace, dateStr string, madisonClient interface{}, logger *logging.Logger, pollInterval time.Duration, maxConcurrent int, workerID int) error {
+func CreateSnapshotWithRetry(client *opensearch.Client, snapshotName, indexName, snapRepo, namespace, dateStr string, notifier alerts.Notifier, logger *logging.Logger, pollInterval time.Duration, maxConcurrent int, workerID int, progress *SnapshotProgress, metadata *SnapshotMetadata) error {
+	start := time.Now()
+	partial, err := createSnapshotWithRetry(client, snapshotName, indexName, snapRepo, namespace, dateStr, notifier, logger, pollInterval, maxConcurrent, workerID, progress, metadata)
+	item := fmt.Sprintf("%s (repo=%s)", snapshotName, snapRepo)
+	if partial {
+		progress.KeptPartial(snapRepo, snapshotName)
+		report.Skipped(item, "kept PARTIAL snapshot of a resumed run")
+		return nil
+	}
+	progress.Done(snapRepo, snapshotName, err)
+	if err != nil {
+		metrics.SnapshotsFailed.Inc(snapRepo)
+		report.Failed(item, err).Took(time.Since(start))
+	} else {
+		metrics.SnapshotsCreated.Inc(snapRepo)
+		report.Succeeded(item).Took(time.Since(start))
+	}
+	return err
+}
+
+func createSnapshotWithRetry(client *opensearch.Client, snapshotName, indexName, snapRepo, namespace, dateStr string, notifier alerts.Notifier, logger *logging.Logger, pollInterval time.Duration, maxConcurrent int, workerID int, progress *SnapshotProgress, metadata *SnapshotMetadata) (bool, error) {
 	const maxRetries = 7
+	wlog := workerLogger(logger, workerID)
 
 	existingIndices, err := CheckIndicesExist(client, indexName, logger)
 	if err != nil {
-		if workerID > 0 {
-			logger.Error(fmt.Sprintf("Worker %d: Failed to check indices existence snapshot=%s error=%v", workerID, snapshotName, err))
-		} else {
-			logger.Error(fmt.Sprintf("Failed to check indices existence snapshot=%s error=%v", snapshotName, err))
-		}
-		return err
+		wlog.WithFields(logging.Fields{"snapshot": snapshotName, "error": err}).Error("Failed to check indices existence")
+		return false, err
 	}
 
 	if len(existingIndices) == 0 {
-		if workerID > 0 {
-			logger.Warn(fmt.Sprintf("Worker %d: No existing indices found, skipping snapshot creation snapshot=%s indices=%s", workerID, snapshotName, indexName))
-		} else {
-			logger.Warn(fmt.Sprintf("No existing indices found, skipping snapshot creation snapshot=%s indices=%s", snapshotName, indexName))
-		}
-		return fmt.Errorf("no existing indices to snapshot")
+		wlog.WithFields(logging.Fields{"snapshot": snapshotName, "indices": indexName}).Warn("No existing indices found, skipping snapshot creation")
+		return false, fmt.Errorf("no existing indices to snapshot")
 	}
 
 	existingIndicesStr := strings.Join(existingIndices, ",")
 	if existingIndicesStr != indexName {
-		if workerID > 0 {
-			logger.Info(fmt.Sprintf("Worker %d: Some indices were removed, using only existing indices snapshot=%s existing=%s original=%s", workerID, snapshotName, existingIndicesStr, indexName))
-		} else {
-			logger.Info(fmt.Sprintf("Some indices were removed, using only existing indices snapshot=%s existing=%s original=%s", snapshotName, existingIndicesStr, indexName))
-		}
+		wlog.WithFields(logging.Fields{"snapshot": snapshotName, "existing": existingIndicesStr, "original": indexName}).Info("Some indices were removed, using only existing indices")
 		indexName = existingIndicesStr
 	}
 
+	firstAttempt := progress.FirstAttempt(snapRepo, snapshotName)
+	resumed := progress.Started(snapRepo, snapshotName)
+	if firstAttempt > 1 {
+		wlog.WithFields(logging.Fields{"snapshot": snapshotName, "repo": snapRepo, "attempt": firstAttempt, "maxRetries": maxRetries}).Info("Resuming snapshot task")
+	}
+
 retryLoop:
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		if workerID > 0 {
-			logger.Info(fmt.Sprintf("Worker %d: Creating snapshot attempt snapshot=%s attempt=%d maxRetries=%d", workerID, snapshotName, attempt, maxRetries))
-		} else {
-			logger.Info(fmt.Sprintf("Creating snapshot attempt snapshot=%s attempt=%d maxRetries=%d", snapshotName, attempt, maxRetries))
-		}
+	for attempt := firstAttempt; attempt <= maxRetries; attempt++ {
+		progress.Running(snapRepo, snapshotName, attempt, nil)
+		wlog.WithFields(logging.Fields{"snapshot": snapshotName, "attempt": attempt, "maxRetries": maxRetries}).Info("Creating snapshot attempt")
 
 		if maxConcurrent > 0 {
-			if workerID > 0 {
-				logger.Info(fmt.Sprintf("Worker %d: Waiting for snapshot slot before creating snapshot=%s attempt=%d", workerID, snapshotName, attempt))
-			} else {
-				logger.Info(fmt.Sprintf("Waiting for snapshot slot before creating snapshot=%s attempt=%d", snapshotName, attempt))
-			}
+			wlog.WithFields(logging.Fields{"snapshot": snapshotName, "attempt": attempt}).Info("Waiting for snapshot slot before creating")
 			err := WaitForSnapshotSlot(client, logger, maxConcurrent, pollInterval, snapshotName, workerID)
 			if err != nil {
-				if workerID > 0 {
-					logger.Error(fmt.Sprintf("Worker %d: Failed to wait for snapshot slot snapshot=%s error=%v", workerID, snapshotName, err))
-				} else {
-					logger.Error(fmt.Sprintf("Failed to wait for snapshot slot snapshot=%s error=%v", snapshotName, err))
-				}
-				return err
+				wlog.WithFields(logging.Fields{"snapshot": snapshotName, "error": err}).Error("Failed to wait for snapshot slot")
+				return false, err
 			}
 		}
 
@@ -396,25 +396,13 @@ retryLoop:
 		state, exists, err := CheckSnapshotStateInRepo(client, snapRepo, snapshotName)
 		shouldCreate := true
 		if err != nil {
-			if workerID > 0 {
-				logger.Warn(fmt.Sprintf("Worker %d: Failed to check snapshot state before creation snapshot=%s error=%v, will try to create", workerID, snapshotName, err))
-			} else {
-				logger.Warn(fmt.Sprintf("Failed to check snapshot state before creation snapshot=%s error=%v, will try to create", snapshotName, err))
-			}
+			wlog.WithFields(logging.Fields{"snapshot": snapshotName, "error": err}).Warn("Failed to check snapshot state before creation, will try to create")
 		} else if exists {
 			if state == "SUCCESS" {
-				if workerID > 0 {
-					logger.Info(fmt.Sprintf("Worker %d: Snapshot already exists with SUCCESS state, skipping creation snapshot=%s", workerID, snapshotName))
-				} else {
-					logger.Info(fmt.Sprintf("Snapshot already exists with SUCCESS state, skipping creation snapshot=%s", snapshotName))
-				}
-				return nil
-			}
-			if workerID > 0 {
-				logger.Info(fmt.Sprintf("Worker %d: Snapshot already exists with state=%s, will monitor it snapshot=%s", workerID, state, snapshotName))
-			} else {
-				logger.Info(fmt.Sprintf("Snapshot already exists with state=%s, will monitor it snapshot=%s", state, snapshotName))
+				wlog.WithField("snapshot", snapshotName).Info("Snapshot already exists with SUCCESS state, skipping creation")
+				return false, nil
 			}
+			wlog.WithFields(logging.Fields{"snapshot": snapshotName, "state": state}).Info("Snapshot already exists, will monitor it")
 			shouldCreate = false
 		}
 
@@ -424,53 +412,32 @@ retryLoop:
 				"ignore_unavailable":   true,
 				"include_global_state": false,
 			}
+			if metadata != nil && !client.ES5Compatibility() {
+				snapshotRequest["metadata"] = metadata.ForIndices(strings.Split(indexName, ","))
+			}
 
 			err = client.CreateSnapshot(snapRepo, snapshotName, snapshotRequest)
 			if err != nil {
-				if workerID > 0 {
-					logger.Error(fmt.Sprintf("Worker %d: Failed to create snapshot snapshot=%s attempt=%d error=%v", workerID, snapshotName, attempt, err))
-				} else {
-					logger.Error(fmt.Sprintf("Failed to create snapshot snapshot=%s attempt=%d error=%v", snapshotName, attempt, err))
-				}
+				progress.Running(snapRepo, snapshotName, attempt, err)
+				wlog.WithFields(logging.Fields{"snapshot": snapshotName, "attempt": attempt, "error": err}).Error("Failed to create snapshot")
 				if attempt < maxRetries {
 					time.Sleep(pollInterval)
 					continue
 				}
-				if workerID > 0 {
-					logger.Error(fmt.Sprintf("Worker %d: Snapshot creation failed after all retries snapshot=%s maxRetries=%d", workerID, snapshotName, maxRetries))
-					logger.Error(fmt.Sprintf("Worker %d: SENDING ALERT: Snapshot creation failed snapshot=%s index=%s message=%s", workerID, snapshotName, indexName,
-						fmt.Sprintf("Snapshot %s for index %s failed to create after %d retries", snapshotName, indexName, maxRetries)))
-				} else {
-					logger.Error(fmt.Sprintf("Snapshot creation failed after all retries snapshot=%s maxRetries=%d", snapshotName, maxRetries))
-					logger.Error(fmt.Sprintf("SENDING ALERT: Snapshot creation failed snapshot=%s index=%s message=%s", snapshotName, indexName,
-						fmt.Sprintf("Snapshot %s for index %s failed to create after %d retries", snapshotName, indexName, maxRetries)))
-				}
-				if madisonClient != nil {
-					if client, ok := madisonClient.(*alerts.Client); ok {
-						response, err := client.SendMadisonSnapshotCreationFailedAlert(snapshotName, indexName, snapRepo, namespace, dateStr)
-						if err != nil {
-							if workerID > 0 {
-								logger.Error(fmt.Sprintf("Worker %d: Failed to send Madison alert error=%v", workerID, err))
-							} else {
-								logger.Error(fmt.Sprintf("Failed to send Madison alert error=%v", err))
-							}
-						} else {
-							if workerID > 0 {
-								logger.Info(fmt.Sprintf("Worker %d: Madison alert sent successfully: type=SnapshotCreationFailed response=%s", workerID, response))
-							} else {
-								logger.Info(fmt.Sprintf("Madison alert sent successfully: type=SnapshotCreationFailed response=%s", response))
-							}
-						}
+				wlog.WithFields(logging.Fields{"snapshot": snapshotName, "maxRetries": maxRetries}).Error("Snapshot creation failed after all retries")
+				wlog.WithFields(logging.Fields{"snapshot": snapshotName, "index": indexName, "message": fmt.Sprintf("Snapshot %s for index %s failed to create after %d retries", snapshotName, indexName, maxRetries)}).Error("SENDING ALERT: Snapshot creation failed")
+				if notifier != nil {
+					response, err := notifier.Notify(alerts.NewSnapshotCreationFailedEvent(snapshotName, indexName, snapRepo, namespace, dateStr))
+					if err != nil {
+						wlog.WithField("error", err).Error("Failed to send alert")
+					} else {
+						wlog.WithFields(logging.Fields{"type": "SnapshotCreationFailed", "notifiers": notifier.Name(), "response": response}).Info("Alert sent successfully")
 					}
 				}
-				return err
+				return false, err
 			}
 		}
-		if workerID > 0 {
-			logger.Info(fmt.Sprintf("Worker %d: Waiting for snapshot completion snapshot=%s", workerID, snapshotName))
-		} else {
-			logger.Info(fmt.Sprintf("Waiting for snapshot completion snapshot=%s", snapshotName))
-		}
+		wlog.WithField("snapshot", snapshotName).Info("Waiting for snapshot completion")
 
 		const maxWaitForVisibility = 15 * time.Minute
 		visibilityDeadline := startTime.Add(maxWaitForVisibility)
@@ -479,41 +446,25 @@ retryLoop:
 			snapshots, err := client.GetSnapshots(snapRepo, snapshotName)
 			if err != nil {
 				if time.Now().After(visibilityDeadline) {
-					if workerID > 0 {
-						logger.Error(fmt.Sprintf("Worker %d: Error getting snapshot after creation timeout snapshot=%s timeout=%v attempt=%d error=%v", workerID, snapshotName, maxWaitForVisibility, attempt, err))
-					} else {
-						logger.Error(fmt.Sprintf("Error getting snapshot after creation timeout snapshot=%s timeout=%v attempt=%d error=%v", snapshotName, maxWaitForVisibility, attempt, err))
-					}
+					wlog.WithFields(logging.Fields{"snapshot": snapshotName, "timeout": maxWaitForVisibility, "attempt": attempt, "error": err}).Error("Error getting snapshot after creation timeout")
 					if attempt < maxRetries {
 						continue retryLoop
 					}
-					return fmt.Errorf("snapshot %s error after creation timeout: %v, attempt=%d", snapshotName, err, attempt)
-				}
-				if workerID > 0 {
-					logger.Error(fmt.Sprintf("Worker %d: Failed to get snapshots snapshot=%s error=%v attempt=%d, error might be transient, wait a bit and retry", workerID, snapshotName, err, attempt))
-				} else {
-					logger.Error(fmt.Sprintf("Failed to get snapshots snapshot=%s error=%v attempt=%d, error might be transient, wait a bit and retry", snapshotName, err, attempt))
+					return false, fmt.Errorf("snapshot %s error after creation timeout: %v, attempt=%d", snapshotName, err, attempt)
 				}
+				wlog.WithFields(logging.Fields{"snapshot": snapshotName, "error": err, "attempt": attempt}).Error("Failed to get snapshots, error might be transient, wait a bit and retry")
 				time.Sleep(pollInterval)
 				continue
 			}
 			if len(snapshots) == 0 {
 				if time.Now().After(visibilityDeadline) {
-					if workerID > 0 {
-						logger.Error(fmt.Sprintf("Worker %d: Snapshot not found in list after creation timeout snapshot=%s timeout=%v attempt=%d", workerID, snapshotName, maxWaitForVisibility, attempt))
-					} else {
-						logger.Error(fmt.Sprintf("Snapshot not found in list after creation timeout snapshot=%s timeout=%v attempt=%d", snapshotName, maxWaitForVisibility, attempt))
-					}
+					wlog.WithFields(logging.Fields{"snapshot": snapshotName, "timeout": maxWaitForVisibility, "attempt": attempt}).Error("Snapshot not found in list after creation timeout")
 					if attempt < maxRetries {
 						continue retryLoop
 					}
-					return fmt.Errorf("snapshot %s not found in list after creation", snapshotName)
-				}
-				if workerID > 0 {
-					logger.Info(fmt.Sprintf("Worker %d: Waiting for snapshot visibility snapshot=%s attempt=%d", workerID, snapshotName, attempt))
-				} else {
-					logger.Info(fmt.Sprintf("Waiting for snapshot visibility snapshot=%s attempt=%d", snapshotName, attempt))
+					return false, fmt.Errorf("snapshot %s not found in list after creation", snapshotName)
 				}
+				wlog.WithFields(logging.Fields{"snapshot": snapshotName, "attempt": attempt}).Info("Waiting for snapshot visibility")
 				time.Sleep(pollInterval)
 				continue
 			}
@@ -522,51 +473,28 @@ retryLoop:
 			if snapshot.State == "IN_PROGRESS" {
 				detailStatus, err := client.GetSnapshotStatusDetail(snapRepo, snapshotName)
 				if err != nil {
-					if workerID > 0 {
-						logger.Warn(fmt.Sprintf("Worker %d: Failed to get detailed snapshot status snapshot=%s error=%v, continuing with basic check", workerID, snapshotName, err))
-					} else {
-						logger.Warn(fmt.Sprintf("Failed to get detailed snapshot status snapshot=%s error=%v, continuing with basic check", snapshotName, err))
-					}
+					wlog.WithFields(logging.Fields{"snapshot": snapshotName, "error": err}).Warn("Failed to get detailed snapshot status, continuing with basic check")
 				} else if len(detailStatus.Snapshots) > 0 {
 					detail := detailStatus.Snapshots[0]
 					if detail.ShardsStats.Failed > 0 {
-						if workerID > 0 {
-							logger.Error(fmt.Sprintf("Worker %d: Snapshot has failed shards, deleting snapshot=%s failedShards=%d totalShards=%d", workerID, snapshotName, detail.ShardsStats.Failed, detail.ShardsStats.Total))
-						} else {
-							logger.Error(fmt.Sprintf("Snapshot has failed shards, deleting snapshot=%s failedShards=%d totalShards=%d", snapshotName, detail.ShardsStats.Failed, detail.ShardsStats.Total))
-						}
+						progress.Running(snapRepo, snapshotName, attempt, fmt.Errorf("snapshot has failed shards: %d of %d", detail.ShardsStats.Failed, detail.ShardsStats.Total))
+						wlog.WithFields(logging.Fields{"snapshot": snapshotName, "failedShards": detail.ShardsStats.Failed, "totalShards": detail.ShardsStats.Total}).Error("Snapshot has failed shards, deleting")
 						err := DeleteSnapshotsWithRetry(client, snapRepo, []string{snapshotName}, logger)
 						if err != nil {
-							if workerID > 0 {
-								logger.Error(fmt.Sprintf("Worker %d: Failed to delete snapshot with failed shards snapshot=%s error=%v", workerID, snapshotName, err))
-							} else {
-								logger.Error(fmt.Sprintf("Failed to delete snapshot with failed shards snapshot=%s error=%v", snapshotName, err))
-							}
+							wlog.WithFields(logging.Fields{"snapshot": snapshotName, "error": err}).Error("Failed to delete snapshot with failed shards")
 						} else {
-							if workerID > 0 {
-								logger.Info(fmt.Sprintf("Worker %d: Deleted snapshot with failed shards snapshot=%s failedShards=%d", workerID, snapshotName, detail.ShardsStats.Failed))
-							} else {
-								logger.Info(fmt.Sprintf("Deleted snapshot with failed shards snapshot=%s failedShards=%d", snapshotName, detail.ShardsStats.Failed))
-							}
+							wlog.WithFields(logging.Fields{"snapshot": snapshotName, "failedShards": detail.ShardsStats.Failed}).Info("Deleted snapshot with failed shards")
 						}
 						if attempt < maxRetries {
-							if workerID > 0 {
-								logger.Info(fmt.Sprintf("Worker %d: Waiting 15 minutes before retry after failed shards attempt=%d maxRetries=%d", workerID, attempt+1, maxRetries))
-							} else {
-								logger.Info(fmt.Sprintf("Waiting 15 minutes before retry after failed shards attempt=%d maxRetries=%d", attempt+1, maxRetries))
-							}
+							wlog.WithFields(logging.Fields{"attempt": attempt + 1, "maxRetries": maxRetries}).Info("Waiting 15 minutes before retry after failed shards")
 							time.Sleep(15 * time.Minute)
 							continue retryLoop
 						}
-						return fmt.Errorf("snapshot %s has failed shards (failed=%d), deleted and retrying", snapshotName, detail.ShardsStats.Failed)
+						return false, fmt.Errorf("snapshot %s has failed shards (failed=%d), deleted and retrying", snapshotName, detail.ShardsStats.Failed)
 					}
 				}
 
-				if workerID > 0 {
-					logger.Info(fmt.Sprintf("Worker %d: Snapshot still in progress snapshot=%s", workerID, snapshotName))
-				} else {
-					logger.Info(fmt.Sprintf("Snapshot still in progress snapshot=%s", snapshotName))
-				}
+				wlog.WithField("snapshot", snapshotName).Info("Snapshot still in progress")
 				time.Sleep(pollInterval)
 				continue
 			}
@@ -575,56 +503,33 @@ retryLoop:
 			case "SUCCESS":
 				duration := time.Since(startTime)
 				durationStr := formatDuration(duration)
-				if workerID > 0 {
-					logger.Info(fmt.Sprintf("Worker %d: Snapshot created successfully snapshot=%s duration=%s attempt=%d", workerID, snapshotName, durationStr, attempt))
-				} else {
-					logger.Info(fmt.Sprintf("Snapshot created successfully snapshot=%s duration=%s attempt=%d", snapshotName, durationStr, attempt))
-				}
-				return nil
+				wlog.WithFields(logging.Fields{"snapshot": snapshotName, "duration": durationStr, "attempt": attempt}).Info("Snapshot created successfully")
+				return false, nil
 			case "PARTIAL", "FAILED":
+				if snapshot.State == "PARTIAL" && resumed && !shouldCreate {
+					wlog.WithFields(logging.Fields{"snapshot": snapshotName, "repo": snapRepo, "attempt": attempt}).Warn("Snapshot started before a restart finished PARTIAL, keeping it")
+					return true, nil
+				}
+				progress.Running(snapRepo, snapshotName, attempt, fmt.Errorf("snapshot finished in state %s", snapshot.State))
 				duration := time.Since(startTime)
 				durationStr := formatDuration(duration)
-				if workerID > 0 {
-					logger.Warn(fmt.Sprintf("Worker %d: Snapshot is PARTIAL/FAILED, deleting and retrying snapshot=%s state=%s duration=%s attempt=%d", workerID, snapshotName, snapshot.State, durationStr, attempt))
-				} else {
-					logger.Warn(fmt.Sprintf("Snapshot is PARTIAL/FAILED, deleting and retrying snapshot=%s state=%s duration=%s attempt=%d", snapshotName, snapshot.State, durationStr, attempt))
-				}
+				wlog.WithFields(logging.Fields{"snapshot": snapshotName, "state": snapshot.State, "duration": durationStr, "attempt": attempt}).Warn("Snapshot is PARTIAL/FAILED, deleting and retrying")
 				err := DeleteSnapshotsWithRetry(client, snapRepo, []string{snapshotName}, logger)
 				if err != nil {
-					if workerID > 0 {
-						logger.Error(fmt.Sprintf("Worker %d: Failed to delete PARTIAL/FAILED snapshot snapshot=%s error=%v", workerID, snapshotName, err))
-					} else {
-						logger.Error(fmt.Sprintf("Failed to delete PARTIAL/FAILED snapshot snapshot=%s error=%v", snapshotName, err))
-					}
+					wlog.WithFields(logging.Fields{"snapshot": snapshotName, "error": err}).Error("Failed to delete PARTIAL/FAILED snapshot")
 				} else {
-					if workerID > 0 {
-						logger.Info(fmt.Sprintf("Worker %d: Deleted PARTIAL/FAILED snapshot snapshot=%s state=%s duration=%s attempt=%d", workerID, snapshotName, snapshot.State, durationStr, attempt))
-					} else {
-						logger.Info(fmt.Sprintf("Deleted PARTIAL/FAILED snapshot snapshot=%s state=%s duration=%s attempt=%d", snapshotName, snapshot.State, durationStr, attempt))
-					}
+					wlog.WithFields(logging.Fields{"snapshot": snapshotName, "state": snapshot.State, "duration": durationStr, "attempt": attempt}).Info("Deleted PARTIAL/FAILED snapshot")
 				}
 				if attempt < maxRetries {
-					if workerID > 0 {
-						logger.Info(fmt.Sprintf("Worker %d: Waiting 15 minutes before retry attempt=%d maxRetries=%d", workerID, attempt+1, maxRetries))
-					} else {
-						logger.Info(fmt.Sprintf("Waiting 15 minutes before retry attempt=%d maxRetries=%d", attempt+1, maxRetries))
-					}
+					wlog.WithFields(logging.Fields{"attempt": attempt + 1, "maxRetries": maxRetries}).Info("Waiting 15 minutes before retry")
 					time.Sleep(15 * time.Minute)
 					continue retryLoop
 				}
 			default:
-				if workerID > 0 {
-					logger.Warn(fmt.Sprintf("Worker %d: Unknown snapshot state snapshot=%s state=%s attempt=%d", workerID, snapshotName, snapshot.State, attempt))
-				} else {
-					logger.Warn(fmt.Sprintf("Unknown snapshot state snapshot=%s state=%s attempt=%d", snapshotName, snapshot.State, attempt))
-				}
+				wlog.WithFields(logging.Fields{"snapshot": snapshotName, "state": snapshot.State, "attempt": attempt}).Warn("Unknown snapshot state")
 				if attempt < maxRetries {
 					time.Sleep(time.Duration(attempt) * time.Second)
-					if workerID > 0 {
-						logger.Warn(fmt.Sprintf("Worker %d: Unknown snapshot state snapshot=%s state=%s attempt=%d, try again", workerID, snapshotName, snapshot.State, attempt))
-					} else {
-						logger.Warn(fmt.Sprintf("Unknown snapshot state snapshot=%s state=%s attempt=%d, try again", snapshotName, snapshot.State, attempt))
-					}
+					wlog.WithFields(logging.Fields{"snapshot": snapshotName, "state": snapshot.State, "attempt": attempt}).Warn("Unknown snapshot state, try again")
 					continue retryLoop
 				}
 			}
@@ -633,34 +538,24 @@ retryLoop:
 		}
 	}
 
-	if workerID > 0 {
-		logger.Error(fmt.Sprintf("Worker %d: Snapshot creation failed after all retries snapshot=%s maxRetries=%d", workerID, snapshotName, maxRetries))
-		logger.Error(fmt.Sprintf("Worker %d: SENDING ALERT: Snapshot creation failed snapshot=%s index=%s message=%s", workerID, snapshotName, indexName,
-			fmt.Sprintf("Snapshot %s for index %s failed to create after %d retries", snapshotName, indexName, maxRetries)))
-	} else {
-		logger.Error(fmt.Sprintf("Snapshot creation failed after all retries snapshot=%s maxRetries=%d", snapshotName, maxRetries))
-		logger.Error(fmt.Sprintf("SENDING ALERT: Snapshot creation failed snapshot=%s index=%s message=%s", snapshotName, indexName,
-			fmt.Sprintf("Snapshot %s for index %s failed to create after %d retries", snapshotName, indexName, maxRetries)))
-	}
-	if madisonClient != nil {
-		if client, ok := madisonClient.(*alerts.Client); ok {
-			response, err := client.SendMadisonSnapshotCreationFailedAlert(snapshotName, indexName, snapRepo, namespace, dateStr)
-			if err != nil {
-				if workerID > 0 {
-					logger.Error(fmt.Sprintf("Worker %d: Failed to send Madison alert error=%v", workerID, err))
-				} else {
-					logger.Error(fmt.Sprintf("Failed to send Madison alert error=%v", err))
-				}
-			} else {
-				if workerID > 0 {
-					logger.Info(fmt.Sprintf("Worker %d: Madison alert sent successfully: type=SnapshotCreationFailed response=%s", workerID, response))
-				} else {
-					logger.Info(fmt.Sprintf("Madison alert sent successfully: type=SnapshotCreationFailed response=%s", response))
-				}
-			}
+	wlog.WithFields(logging.Fields{"snapshot": snapshotName, "maxRetries": maxRetries}).Error("Snapshot creation failed after all retries")
+	wlog.WithFields(logging.Fields{"snapshot": snapshotName, "index": indexName, "message": fmt.Sprintf("Snapshot %s for index %s failed to create after %d retries", snapshotName, indexName, maxRetries)}).Error("SENDING ALERT: Snapshot creation failed")
+	if notifier != nil {
+		response, err := notifier.Notify(alerts.NewSnapshotCreationFailedEvent(snapshotName, indexName, snapRepo, namespace, dateStr))
+		if err != nil {
+			wlog.WithField("error", err).Error("Failed to send alert")
+		} else {
+			wlog.WithFields(logging.Fields{"type": "SnapshotCreationFailed", "notifiers": notifier.Name(), "response": response}).Info("Alert sent successfully")
 		}
 	}
-	return fmt.Errorf("snapshot creation failed after %d retries", maxRetries)
+	return false, fmt.Errorf("snapshot creation failed after %d retries", maxRetries)
+}
+
+func workerLogger(logger *logging.Logger, workerID int) *logging.Logger {
+	if workerID > 0 {
+		return logger.WithField("worker", workerID)
+	}
+	return logger
 }
 
 func FindMatchingSnapshotConfig(snapshotName string, indicesConfig []config.IndexConfig) *config.IndexConfig {
@@ -684,7 +579,10 @@ func BatchDeleteSnapshots(client *opensearch.Client, snapshots []string, snapRep
 	var failed []string
 
 	if dryRun {
-		logger.Info(fmt.Sprintf("Dry run: would delete snapshots count=%d", len(snapshots)))
+		logger.WithField("count", len(snapshots)).Info("Dry run: would delete snapshots")
+		for _, snapshotName := range snapshots {
+			report.Skipped(fmt.Sprintf("%s (repo=%s)", snapshotName, snapRepo), "dry run")
+		}
 		return nil, nil, nil
 	}
 
@@ -698,7 +596,7 @@ func BatchDeleteSnapshots(client *opensearch.Client, snapshots []string, snapRep
 
 		randomWaitMinutes := rand.Intn(5) + 1
 		randomWaitDuration := time.Duration(randomWaitMinutes) * time.Minute
-		logger.Info(fmt.Sprintf("Waiting %d minutes before deleting batch batch=%d snapshots=%v", randomWaitMinutes, i/batchSize+1, batch))
+		logger.WithFields(logging.Fields{"minutes": randomWaitMinutes, "batch": i/batchSize + 1, "snapshots": strings.Join(batch, ", ")}).Info("Waiting before deleting batch")
 		time.Sleep(randomWaitDuration)
 
 		var lastErr error
@@ -707,43 +605,49 @@ func BatchDeleteSnapshots(client *opensearch.Client, snapshots []string, snapRep
 			for _, snapshotName := range batch {
 				snapshots, err := GetSnapshotsIgnore404(client, snapRepo, snapshotName)
 				if err != nil {
-					logger.Warn(fmt.Sprintf("Failed to check snapshot existence snapshot=%s error=%v, will try to delete", snapshotName, err))
+					logger.WithFields(logging.Fields{"snapshot": snapshotName, "error": err}).Warn("Failed to check snapshot existence, will try to delete")
 					existingSnapshots = append(existingSnapshots, snapshotName)
 					continue
 				}
 				if len(snapshots) > 0 {
 					existingSnapshots = append(existingSnapshots, snapshotName)
 				} else {
-					logger.Info(fmt.Sprintf("Snapshot already deleted, skipping snapshot=%s", snapshotName))
+					logger.WithField("snapshot", snapshotName).Info("Snapshot already deleted, skipping")
 				}
 			}
 
 			if len(existingSnapshots) == 0 {
-				logger.Info(fmt.Sprintf("All snapshots from batch already deleted batch=%d attempt=%d snapshots=%v", i/batchSize+1, attempt, batch))
+				logger.WithFields(logging.Fields{"batch": i/batchSize + 1, "attempt": attempt, "snapshots": batch}).Info("All snapshots from batch already deleted")
 				break
 			}
 
-			logger.Info(fmt.Sprintf("Deleting snapshots batch batch=%d attempt=%d maxRetries=%d snapshots=%v", i/batchSize+1, attempt, maxRetries, existingSnapshots))
+			logger.WithFields(logging.Fields{"batch": i/batchSize + 1, "attempt": attempt, "maxRetries": maxRetries, "snapshots": existingSnapshots}).Info("Deleting snapshots batch")
 
 			err := client.DeleteSnapshots(snapRepo, existingSnapshots)
 			if err != nil {
 				lastErr = err
-				logger.Error(fmt.Sprintf("Failed to delete snapshots batch batch=%d attempt=%d snapshots=%v error=%v", i/batchSize+1, attempt, existingSnapshots, err))
+				logger.WithFields(logging.Fields{"batch": i/batchSize + 1, "attempt": attempt, "snapshots": existingSnapshots, "error": err}).Error("Failed to delete snapshots batch")
 				if attempt < maxRetries {
-					logger.Info(fmt.Sprintf("Waiting 5 minutes before retry batch=%d attempt=%d", i/batchSize+1, attempt+1))
+					logger.WithFields(logging.Fields{"batch": i/batchSize + 1, "attempt": attempt + 1}).Info("Waiting 5 minutes before retry")
 					time.Sleep(5 * time.Minute)
 					continue
 				}
 			} else {
-				logger.Info(fmt.Sprintf("Snapshots batch deleted successfully batch=%d attempt=%d snapshots=%v", i/batchSize+1, attempt, existingSnapshots))
+				logger.WithFields(logging.Fields{"batch": i/batchSize + 1, "attempt": attempt, "snapshots": existingSnapshots}).Info("Snapshots batch deleted successfully")
 				successful = append(successful, existingSnapshots...)
+				for _, snapshotName := range existingSnapshots {
+					report.Succeeded(fmt.Sprintf("%s (repo=%s)", snapshotName, snapRepo))
+				}
 				break
 			}
 		}
 
 		if lastErr != nil {
-			logger.Error(fmt.Sprintf("Failed to delete snapshots batch after all retries batch=%d maxRetries=%d snapshots=%v error=%v", i/batchSize+1, maxRetries, batch, lastErr))
+			logger.WithFields(logging.Fields{"batch": i/batchSize + 1, "maxRetries": maxRetries, "snapshots": batch, "error": lastErr}).Error("Failed to delete snapshots batch after all retries")
 			failed = append(failed, batch...)
+			for _, snapshotName := range batch {
+				report.Failed(fmt.Sprintf("%s (repo=%s)", snapshotName, snapRepo), lastErr)
+			}
 		}
 	}
 
@@ -763,41 +667,41 @@ func DeleteSnapshotsWithRetry(client *opensearch.Client, snapRepo string, snapsh
 		for _, snapshotName := range snapshotNames {
 			snapshots, err := GetSnapshotsIgnore404(client, snapRepo, snapshotName)
 			if err != nil {
-				logger.Warn(fmt.Sprintf("Failed to check snapshot existence snapshot=%s error=%v, will try to delete", snapshotName, err))
+				logger.WithFields(logging.Fields{"snapshot": snapshotName, "error": err}).Warn("Failed to check snapshot existence, will try to delete")
 				existingSnapshots = append(existingSnapshots, snapshotName)
 				continue
 			}
 			if len(snapshots) > 0 {
 				existingSnapshots = append(existingSnapshots, snapshotName)
 			} else {
-				logger.Info(fmt.Sprintf("Snapshot already deleted, skipping snapshot=%s", snapshotName))
+				logger.WithField("snapshot", snapshotName).Info("Snapshot already deleted, skipping")
 			}
 		}
 
 		if len(existingSnapshots) == 0 {
-			logger.Info(fmt.Sprintf("All snapshots already deleted attempt=%d snapshots=%v", attempt, snapshotNames))
+			logger.WithFields(logging.Fields{"attempt": attempt, "snapshots": snapshotNames}).Info("All snapshots already deleted")
 			return nil
 		}
 
-		logger.Info(fmt.Sprintf("Deleting snapshots attempt=%d maxRetries=%d snapshots=%v", attempt, maxRetries, existingSnapshots))
+		logger.WithFields(logging.Fields{"attempt": attempt, "maxRetries": maxRetries, "snapshots": existingSnapshots}).Info("Deleting snapshots")
 
 		err := client.DeleteSnapshots(snapRepo, existingSnapshots)
 		if err != nil {
 			lastErr = err
-			logger.Error(fmt.Sprintf("Failed to delete snapshots attempt=%d snapshots=%v error=%v", attempt, existingSnapshots, err))
+			logger.WithFields(logging.Fields{"attempt": attempt, "snapshots": existingSnapshots, "error": err}).Error("Failed to delete snapshots")
 			if attempt < maxRetries {
-				logger.Info(fmt.Sprintf("Waiting 1 minute before retry attempt=%d", attempt+1))
+				logger.WithField("attempt", attempt+1).Info("Waiting 1 minute before retry")
 				time.Sleep(1 * time.Minute)
 				continue
 			}
 		} else {
-			logger.Info(fmt.Sprintf("Snapshots deleted successfully attempt=%d snapshots=%v", attempt, existingSnapshots))
+			logger.WithFields(logging.Fields{"attempt": attempt, "snapshots": existingSnapshots}).Info("Snapshots deleted successfully")
 			return nil
 		}
 	}
 
 	if lastErr != nil {
-		logger.Error(fmt.Sprintf("Failed to delete snapshots after all retries maxRetries=%d snapshots=%v error=%v", maxRetries, snapshotNames, lastErr))
+		logger.WithFields(logging.Fields{"maxRetries": maxRetries, "snapshots": snapshotNames, "error": lastErr}).Error("Failed to delete snapshots after all retries")
 		return lastErr
 	}
 
@@ -809,6 +713,7 @@ type SnapshotGroup struct {
 	Indices      []string
 	Pattern      string
 	Kind         string
+	Name         string
 }
 
 func GroupIndicesForSnapshots(indices []string, indicesConfig []config.IndexConfig, dateStr string) []SnapshotGroup {
@@ -838,6 +743,7 @@ func GroupIndicesForSnapshots(indices []string, indicesConfig []config.IndexConf
 				Indices:      matchingIndices,
 				Pattern:      indexConfig.Value,
 				Kind:         indexConfig.Kind,
+				Name:         indexConfig.Name,
 			})
 		}
 	}
@@ -899,6 +805,7 @@ func AddIndexToSnapshotGroups(indexName string, indexConfig config.IndexConfig,
 				Indices:      []string{indexName},
 				Pattern:      indexConfig.Value,
 				Kind:         indexConfig.Kind,
+				Name:         indexConfig.Name,
 			}
 		}
 	} else {
@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"fmt"
+	"osctl/pkg/logging"
+	"osctl/pkg/opensearch"
+	"sort"
+	"time"
+)
+
+type VerifySample struct {
+	Repo     string
+	Prefix   string
+	Snapshot opensearch.Snapshot
+	Index    string
+}
+
+func GroupSnapshotsByPrefix(snapshots []opensearch.Snapshot, dateFormat string) map[string][]opensearch.Snapshot {
+	groups := make(map[string][]opensearch.Snapshot)
+	for _, s := range snapshots {
+		prefix := s.Snapshot
+		if dateStr := ExtractDateFromIndex(s.Snapshot, dateFormat); dateStr != "" {
+			prefix = IndexPrefixForDate(s.Snapshot, dateStr)
+		}
+		groups[prefix] = append(groups[prefix], s)
+	}
+	return groups
+}
+
+func RotatingSample(snapshots []opensearch.Snapshot, size int, day int) []opensearch.Snapshot {
+	if size <= 0 || len(snapshots) == 0 {
+		return nil
+	}
+	sorted := make([]opensearch.Snapshot, len(snapshots))
+	copy(sorted, snapshots)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].StartTimeInMillis != sorted[j].StartTimeInMillis {
+			return sorted[i].StartTimeInMillis < sorted[j].StartTimeInMillis
+		}
+		return sorted[i].Snapshot < sorted[j].Snapshot
+	})
+	if size >= len(sorted) {
+		return sorted
+	}
+	offset := (day * size) % len(sorted)
+	out := make([]opensearch.Snapshot, 0, size)
+	for i := 0; i < size; i++ {
+		out = append(out, sorted[(offset+i)%len(sorted)])
+	}
+	return out
+}
+
+func PickVerifyIndex(snapshot opensearch.Snapshot, day int) string {
+	var candidates []string
+	for _, idx := range snapshot.Indices {
+		if !ShouldSkipIndex(idx) {
+			candidates = append(candidates, idx)
+		}
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+	sort.Strings(candidates)
+	return candidates[day%len(candidates)]
+}
+
+func WaitForVerifyRestore(client *opensearch.Client, index string, pollInterval, timeout time.Duration, logger *logging.Logger) error {
+	if pollInterval <= 0 {
+		pollInterval = 30 * time.Second
+	}
+	start := time.Now()
+	for {
+		class, err := ClassifyRestore(client, index)
+		if err != nil {
+			logger.WithFields(logging.Fields{"index": index, "error": err}).Warn("Failed to poll verify restore")
+		} else {
+			switch class {
+			case RestoreDone:
+				return nil
+			case RestoreFailed:
+				return fmt.Errorf("restore of %s failed: primary shards unassigned after NEW_INDEX_RESTORED", index)
+			}
+		}
+		if timeout > 0 && time.Since(start) >= timeout {
+			return fmt.Errorf("restore of %s did not finish within %s", index, formatDuration(timeout))
+		}
+		logger.WithFields(logging.Fields{"index": index, "elapsed": formatDuration(time.Since(start))}).Info("Verify restore in progress")
+		time.Sleep(pollInterval)
+	}
+}
@@ -0,0 +1,186 @@
+package utils
+
+import (
+	"fmt"
+	"osctl/pkg/config"
+	"osctl/pkg/opensearch"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ClusterStatus struct {
+	Name            string   `json:"name"`
+	DiskUtilization int      `json:"disk_utilization_percent"`
+	ActiveSnapshots []string `json:"active_snapshots"`
+	ActiveRestores  []string `json:"active_restores"`
+}
+
+type PrefixStatus struct {
+	Prefix       string   `json:"prefix"`
+	Kind         string   `json:"kind"`
+	Policy       string   `json:"policy"`
+	Indices      int      `json:"indices"`
+	OldestDate   string   `json:"oldest_date"`
+	NewestDate   string   `json:"newest_date"`
+	TotalBytes   int64    `json:"total_bytes"`
+	HotIndices   int      `json:"hot_indices"`
+	ColdIndices  int      `json:"cold_indices"`
+	Replicas     []string `json:"replicas"`
+	SnapshotRepo string   `json:"snapshot_repository,omitempty"`
+	Snapshotted  int      `json:"snapshotted_indices"`
+	NextDeletion string   `json:"next_deletion,omitempty"`
+
+	daysCount int
+	names     []string
+}
+
+func (p *PrefixStatus) Tier() string {
+	switch {
+	case p.ColdIndices == 0:
+		return "hot"
+	case p.HotIndices == 0:
+		return "cold"
+	}
+	return fmt.Sprintf("hot %d/cold %d", p.HotIndices, p.ColdIndices)
+}
+
+func (p *PrefixStatus) Coverage() string {
+	if p.SnapshotRepo == "" {
+		return "-"
+	}
+	return fmt.Sprintf("%d/%d", p.Snapshotted, p.Indices)
+}
+
+func (p *PrefixStatus) FillSnapshotCoverage(snapshots []opensearch.Snapshot) {
+	p.Snapshotted = 0
+	for _, name := range p.names {
+		if HasValidSnapshot(name, snapshots) {
+			p.Snapshotted++
+		}
+	}
+}
+
+func BuildPrefixStatuses(indices []opensearch.IndexInfo, cold map[string]string, cfg *config.Config) ([]*PrefixStatus, error) {
+	rules, err := cfg.GetOsctlIndices()
+	if err != nil {
+		return nil, err
+	}
+	unknown := cfg.GetOsctlIndicesUnknownConfig()
+	s3Config := cfg.GetOsctlIndicesS3SnapshotsConfig()
+	dateFormat := cfg.GetDateFormat()
+
+	byRule := make(map[int]*PrefixStatus, len(rules))
+	out := make([]*PrefixStatus, 0, len(rules)+1)
+	for i, ic := range rules {
+		p := &PrefixStatus{Prefix: ic.Value, Kind: ic.Kind, daysCount: ic.DaysCount}
+		if ic.Kind == "regex" && ic.Name != "" {
+			p.Prefix = ic.Name
+		}
+		s3Days := s3Config.UnitCount.All
+		if ic.SnapshotCountS3 > 0 {
+			s3Days = ic.SnapshotCountS3
+		}
+		if ic.Snapshot {
+			p.SnapshotRepo = SourceRepository(cfg.GetSnapshotRepo(), ic)
+		}
+		p.Policy = statusPolicy(ic.DaysCount, ic.Snapshot, ic.ManualSnapshot, s3Days, ic.SecondaryRepository)
+		byRule[i] = p
+		out = append(out, p)
+	}
+	other := &PrefixStatus{Prefix: "unknown", Kind: "unknown", daysCount: unknown.DaysCount}
+	if unknown.Snapshot {
+		other.SnapshotRepo = cfg.GetSnapshotRepo()
+	}
+	other.Policy = statusPolicy(unknown.DaysCount, unknown.Snapshot, unknown.ManualSnapshot, s3Config.UnitCount.Unknown, "")
+
+	goFormat := ConvertDateFormat(dateFormat)
+	replicas := make(map[*PrefixStatus]map[string]bool)
+	for _, idx := range indices {
+		name := idx.Index
+		if extracted := cfg.GetExtractedPattern(); extracted != "" && strings.HasPrefix(name, extracted) {
+			continue
+		}
+		var p *PrefixStatus
+		for i, ic := range rules {
+			if MatchesIndex(name, ic) {
+				p = byRule[i]
+				break
+			}
+		}
+		date := ExtractDateFromIndex(name, dateFormat)
+		if p == nil {
+			if date == "" || ShouldSkipIndex(name) {
+				continue
+			}
+			p = other
+		}
+		p.Indices++
+		p.names = append(p.names, name)
+		if size, err := strconv.ParseInt(idx.Size, 10, 64); err == nil {
+			p.TotalBytes += size
+		}
+		if attr := cfg.GetColdAttribute(); attr != "" && cold[name] == attr {
+			p.ColdIndices++
+		} else {
+			p.HotIndices++
+		}
+		if replicas[p] == nil {
+			replicas[p] = make(map[string]bool)
+		}
+		replicas[p][idx.Rep] = true
+		if date != "" {
+			if p.OldestDate == "" || dateBefore(date, p.OldestDate, goFormat) {
+				p.OldestDate = date
+			}
+			if p.NewestDate == "" || dateBefore(p.NewestDate, date, goFormat) {
+				p.NewestDate = date
+			}
+		}
+	}
+	if other.Indices > 0 {
+		out = append(out, other)
+	}
+
+	today := time.Now().Truncate(24 * time.Hour)
+	for _, p := range out {
+		p.Replicas = []string{}
+		for rep := range replicas[p] {
+			p.Replicas = append(p.Replicas, rep)
+		}
+		sort.Strings(p.Replicas)
+		if p.daysCount <= 0 || p.OldestDate == "" {
+			continue
+		}
+		oldest, err := time.Parse(goFormat, p.OldestDate)
+		if err != nil {
+			continue
+		}
+		next := oldest.AddDate(0, 0, p.daysCount)
+		if next.Before(today) {
+			next = today
+		}
+		p.NextDeletion = next.Format(goFormat)
+	}
+	return out, nil
+}
+
+func statusPolicy(daysCount int, snapshot, manual bool, s3Days int, secondary string) string {
+	parts := []string{"keep"}
+	if daysCount > 0 {
+		parts[0] = fmt.Sprintf("delete after %dd", daysCount)
+	}
+	if snapshot && s3Days > 0 {
+		parts = append(parts, fmt.Sprintf("snapshot %dd", s3Days))
+	} else if snapshot {
+		parts = append(parts, "snapshot")
+	}
+	if manual {
+		parts = append(parts, "manual snapshot")
+	}
+	if secondary != "" {
+		parts = append(parts, "copy to "+secondary)
+	}
+	return strings.Join(parts, ", ")
+}
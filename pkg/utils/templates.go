@@ -3,10 +3,26 @@ package utils
 import (
 	"fmt"
 	"osctl/pkg/opensearch"
+	"sort"
 	"strconv"
 	"strings"
 )
 
+type TemplateCandidate struct {
+	Name     string
+	Patterns []string
+	Priority int
+	Legacy   bool
+}
+
+type TemplateResolution struct {
+	Index    string
+	Winner   *TemplateCandidate
+	Tied     []TemplateCandidate
+	Merged   []TemplateCandidate
+	Shadowed []TemplateCandidate
+}
+
 func TemplateExists(client *opensearch.Client, templateName string) (bool, error) {
 	_, err := client.GetIndexTemplate(templateName)
 	if err != nil {
@@ -50,3 +66,103 @@ func GetTemplateShardCount(tpl *opensearch.IndexTemplate) (int, error) {
 	}
 	return s, nil
 }
+
+func CollectTemplateCandidates(client *opensearch.Client) ([]TemplateCandidate, error) {
+	var candidates []TemplateCandidate
+	composable, err := client.GetAllIndexTemplates()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get index templates: %v", err)
+	}
+	for _, t := range composable.IndexTemplates {
+		candidates = append(candidates, TemplateCandidate{
+			Name:     t.Name,
+			Patterns: t.IndexTemplate.IndexPatterns,
+			Priority: t.IndexTemplate.Priority,
+		})
+	}
+	legacy, err := client.GetLegacyTemplates()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get legacy templates: %v", err)
+	}
+	for name, t := range legacy {
+		candidates = append(candidates, TemplateCandidate{
+			Name:     name,
+			Patterns: t.IndexPatterns,
+			Priority: t.Order,
+			Legacy:   true,
+		})
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Priority != candidates[j].Priority {
+			return candidates[i].Priority > candidates[j].Priority
+		}
+		return candidates[i].Name < candidates[j].Name
+	})
+	return candidates, nil
+}
+
+func MatchesTemplatePattern(pattern, index string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == index
+	}
+	if !strings.HasPrefix(index, parts[0]) {
+		return false
+	}
+	rest := index[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		pos := strings.Index(rest, part)
+		if pos < 0 {
+			return false
+		}
+		rest = rest[pos+len(part):]
+	}
+	return strings.HasSuffix(rest, parts[len(parts)-1])
+}
+
+func templateMatches(t TemplateCandidate, index string) bool {
+	for _, p := range t.Patterns {
+		if MatchesTemplatePattern(p, index) {
+			return true
+		}
+	}
+	return false
+}
+
+func ResolveTemplateForIndex(index string, candidates []TemplateCandidate) TemplateResolution {
+	res := TemplateResolution{Index: index}
+	var composable, legacy []TemplateCandidate
+	for _, t := range candidates {
+		if !templateMatches(t, index) {
+			continue
+		}
+		if t.Legacy {
+			legacy = append(legacy, t)
+		} else {
+			composable = append(composable, t)
+		}
+	}
+
+	winners := composable
+	if len(winners) == 0 {
+		winners = legacy
+	} else {
+		res.Shadowed = append(res.Shadowed, legacy...)
+	}
+	if len(winners) == 0 {
+		return res
+	}
+
+	winner := winners[0]
+	res.Winner = &winner
+	for _, t := range winners[1:] {
+		if t.Priority == winner.Priority && t.Legacy {
+			res.Merged = append(res.Merged, t)
+		} else if t.Priority == winner.Priority {
+			res.Tied = append(res.Tied, t)
+		} else if !t.Legacy {
+			res.Shadowed = append(res.Shadowed, t)
+		}
+	}
+	return res
+}
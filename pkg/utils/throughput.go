@@ -0,0 +1,224 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	TrendDuration         = "duration"
+	TrendIncrementalRatio = "incremental_ratio"
+
+	trendMinDuration = time.Minute
+	trendMinPoints   = 3
+)
+
+type TrendAnomaly struct {
+	Repo     string  `json:"repository"`
+	Prefix   string  `json:"prefix"`
+	Snapshot string  `json:"snapshot"`
+	Kind     string  `json:"kind"`
+	Value    float64 `json:"value"`
+	Baseline float64 `json:"baseline"`
+}
+
+func (a TrendAnomaly) String() string {
+	if a.Kind == TrendDuration {
+		return fmt.Sprintf("%s/%s: duration %s vs baseline %s (x%.1f)", a.Repo, a.Snapshot,
+			formatDuration(time.Duration(a.Value)*time.Millisecond), formatDuration(time.Duration(a.Baseline)*time.Millisecond), a.Value/a.Baseline)
+	}
+	return fmt.Sprintf("%s/%s: incremental ratio %.1f%% vs baseline %.1f%% (x%.1f)", a.Repo, a.Snapshot, a.Value*100, a.Baseline*100, a.Value/a.Baseline)
+}
+
+type WindowForecast struct {
+	Repo         string        `json:"repository"`
+	Window       time.Duration `json:"window"`
+	LastDate     string        `json:"last_date"`
+	LastLength   time.Duration `json:"last_length"`
+	GrowthPerDay time.Duration `json:"growth_per_day"`
+	DaysLeft     int           `json:"days_left"`
+	Points       int           `json:"points"`
+}
+
+func (f WindowForecast) String() string {
+	switch {
+	case f.Points < trendMinPoints:
+		return fmt.Sprintf("%s: not enough nights for a forecast (%d)", f.Repo, f.Points)
+	case f.DaysLeft < 0:
+		return fmt.Sprintf("%s: last night %s of window %s, not growing", f.Repo, formatDuration(f.LastLength), formatDuration(f.Window))
+	case f.DaysLeft == 0:
+		return fmt.Sprintf("%s: last night %s already exceeds window %s", f.Repo, formatDuration(f.LastLength), formatDuration(f.Window))
+	}
+	return fmt.Sprintf("%s: last night %s of window %s, growing %s/day, window exceeded in ~%d days", f.Repo, formatDuration(f.LastLength), formatDuration(f.Window), formatDuration(f.GrowthPerDay), f.DaysLeft)
+}
+
+func SnapshotMBps(bytes, durationMillis int64) float64 {
+	if durationMillis <= 0 {
+		return 0
+	}
+	return float64(bytes) / 1024 / 1024 / (float64(durationMillis) / 1000)
+}
+
+func IncrementalRatio(s InventorySnapshot) float64 {
+	if s.TotalBytes <= 0 {
+		return 0
+	}
+	return float64(s.IncrementalBytes) / float64(s.TotalBytes)
+}
+
+func FillPrefixThroughput(inv *PrefixInventory) {
+	var bytes, millis int64
+	for i := range inv.Snapshots {
+		s := &inv.Snapshots[i]
+		s.MBps = SnapshotMBps(s.IncrementalBytes, s.DurationMillis)
+		if s.State != "SUCCESS" || s.DurationMillis <= 0 {
+			continue
+		}
+		bytes += s.IncrementalBytes
+		millis += s.DurationMillis
+		inv.LastDurationMillis = s.DurationMillis
+	}
+	inv.AvgMBps = SnapshotMBps(bytes, millis)
+}
+
+func DetectTrendAnomalies(inv PrefixInventory, baselineDays int, durationFactor, ratioFactor float64) []TrendAnomaly {
+	var success []InventorySnapshot
+	for _, s := range inv.Snapshots {
+		if s.State == "SUCCESS" && s.Date != "" {
+			success = append(success, s)
+		}
+	}
+	if len(success) < trendMinPoints+1 {
+		return nil
+	}
+	last := success[len(success)-1]
+	history := success[:len(success)-1]
+	if baselineDays > 0 && len(history) > baselineDays {
+		history = history[len(history)-baselineDays:]
+	}
+
+	var anomalies []TrendAnomaly
+	var durations, ratios []float64
+	for _, s := range history {
+		if s.DurationMillis > 0 {
+			durations = append(durations, float64(s.DurationMillis))
+		}
+		if s.TotalBytes > 0 {
+			ratios = append(ratios, IncrementalRatio(s))
+		}
+	}
+	if len(durations) >= trendMinPoints && durationFactor > 0 && time.Duration(last.DurationMillis)*time.Millisecond >= trendMinDuration {
+		baseline := median(durations)
+		if baseline > 0 && float64(last.DurationMillis) > baseline*durationFactor {
+			anomalies = append(anomalies, TrendAnomaly{Repo: inv.Repo, Prefix: inv.Prefix, Snapshot: last.Snapshot, Kind: TrendDuration, Value: float64(last.DurationMillis), Baseline: baseline})
+		}
+	}
+	if len(ratios) >= trendMinPoints && ratioFactor > 0 && last.TotalBytes > 0 {
+		baseline := median(ratios)
+		if ratio := IncrementalRatio(last); baseline > 0 && ratio > baseline*ratioFactor {
+			anomalies = append(anomalies, TrendAnomaly{Repo: inv.Repo, Prefix: inv.Prefix, Snapshot: last.Snapshot, Kind: TrendIncrementalRatio, Value: ratio, Baseline: baseline})
+		}
+	}
+	return anomalies
+}
+
+func ForecastSnapshotWindow(repo string, snapshots []InventorySnapshot, window time.Duration, historyDays int, dateFormat string) WindowForecast {
+	forecast := WindowForecast{Repo: repo, Window: window, DaysLeft: -1}
+	type night struct {
+		start, end int64
+	}
+	nights := make(map[string]*night)
+	for _, s := range snapshots {
+		if s.State != "SUCCESS" || s.Date == "" || s.StartTimeMillis <= 0 {
+			continue
+		}
+		end := s.StartTimeMillis + s.DurationMillis
+		n, ok := nights[s.Date]
+		if !ok {
+			nights[s.Date] = &night{start: s.StartTimeMillis, end: end}
+			continue
+		}
+		n.start = min(n.start, s.StartTimeMillis)
+		n.end = max(n.end, end)
+	}
+
+	goFormat := ConvertDateFormat(dateFormat)
+	dates := make([]string, 0, len(nights))
+	for d := range nights {
+		if _, err := time.Parse(goFormat, d); err == nil {
+			dates = append(dates, d)
+		}
+	}
+	sort.Slice(dates, func(i, j int) bool { return dateBefore(dates[i], dates[j], goFormat) })
+	if historyDays > 0 && len(dates) > historyDays {
+		dates = dates[len(dates)-historyDays:]
+	}
+	forecast.Points = len(dates)
+	if len(dates) == 0 {
+		return forecast
+	}
+
+	first, _ := time.Parse(goFormat, dates[0])
+	xs := make([]float64, 0, len(dates))
+	ys := make([]float64, 0, len(dates))
+	for _, d := range dates {
+		t, _ := time.Parse(goFormat, d)
+		n := nights[d]
+		xs = append(xs, t.Sub(first).Hours()/24)
+		ys = append(ys, float64(n.end-n.start))
+	}
+	last := dates[len(dates)-1]
+	forecast.LastDate = last
+	forecast.LastLength = time.Duration(ys[len(ys)-1]) * time.Millisecond
+	if len(dates) < trendMinPoints || window <= 0 {
+		return forecast
+	}
+
+	slope, intercept := linearFit(xs, ys)
+	forecast.GrowthPerDay = time.Duration(slope) * time.Millisecond
+	current := intercept + slope*xs[len(xs)-1]
+	windowMillis := float64(window.Milliseconds())
+	switch {
+	case forecast.LastLength >= window || current >= windowMillis:
+		forecast.DaysLeft = 0
+	case slope > 0:
+		forecast.DaysLeft = int(math.Ceil((windowMillis - current) / slope))
+	}
+	return forecast
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+func linearFit(xs, ys []float64) (float64, float64) {
+	n := float64(len(xs))
+	var sx, sy, sxx, sxy float64
+	for i := range xs {
+		sx += xs[i]
+		sy += ys[i]
+		sxx += xs[i] * xs[i]
+		sxy += xs[i] * ys[i]
+	}
+	denom := n*sxx - sx*sx
+	if denom == 0 {
+		return 0, sy / n
+	}
+	slope := (n*sxy - sx*sy) / denom
+	return slope, (sy - slope*sx) / n
+}
+
+func FormatDuration(d time.Duration) string {
+	return formatDuration(d)
+}